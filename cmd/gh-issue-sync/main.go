@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/jessevdk/go-flags"
 	"github.com/mitsuhiko/gh-issue-sync/internal/app"
@@ -22,14 +24,31 @@ type Options struct {
 	Pull       PullCommand       `command:"pull" description:"Pull issues from GitHub" long-description:"Fetch issues from GitHub and write/update local issue files."`
 	Push       PushCommand       `command:"push" description:"Push local changes to GitHub" long-description:"Create or update GitHub issues based on local changes."`
 	Sync       SyncCommand       `command:"sync" description:"Pull and push issues" long-description:"Push local changes first, then pull updates from GitHub."`
+	Watch      WatchCommand      `command:"watch" description:"Keep issues continuously in sync" long-description:"Pull on an interval and push local edits to issue files once they settle. The sync lock is only held while pulling or pushing, so other commands keep working."`
 	Status     StatusCommand     `command:"status" description:"Show sync status" long-description:"Show local changes and last full pull time."`
 	List       ListCommand       `command:"list" alias:"ls" description:"List local issues" long-description:"Display a formatted list of local issues with filtering options."`
+	Board      BoardCommand      `command:"board" description:"Show issues as a kanban board" long-description:"Display local issues in columns grouped by a project field (status by default), a label prefix, milestone or assignee."`
+	TUI        TUICommand        `command:"tui" description:"Browse and triage issues interactively" long-description:"Full-screen issue browser with a filterable list, a preview pane and keys to label, assign, close/reopen, edit, queue comments and review pending changes before pushing."`
 	New        NewCommand        `command:"new" description:"Create a new local issue" long-description:"Create a new local issue file. Use --edit to open an editor for the initial content."`
 	Edit       EditCommand       `command:"edit" description:"Open an issue in your editor" long-description:"Open an issue file in your preferred editor ($VISUAL, $EDITOR, or git core.editor)."`
 	View       ViewCommand       `command:"view" description:"View an issue" long-description:"Display an issue with nice formatting, showing metadata and body."`
+	Comment    CommentCommand    `command:"comment" description:"Queue a comment on an issue" long-description:"Write a pending comment that is posted on the next push. Without --body the comment is opened in your editor. Without an issue, the issue of the checked-out branch is used."`
+	Start      StartCommand      `command:"start" description:"Start working on an issue" long-description:"Create and check out a git branch for an issue (named after branch.pattern in the config, {number}-{slug} by default), assign the issue to you and add the in-progress label locally."`
+	Current    CurrentCommand    `command:"current" description:"Show the issue of the current branch" long-description:"Infer the issue from the checked-out git branch name. view, edit and comment use it when no issue is given."`
 	Close      CloseCommand      `command:"close" description:"Mark an issue for closing" long-description:"Mark an issue as closed locally (use push to sync)." `
 	Reopen     ReopenCommand     `command:"reopen" description:"Reopen a closed issue" long-description:"Mark an issue as open locally (use push to sync)."`
+	Log        LogCommand        `command:"log" description:"Show the sync history of an issue" long-description:"Show how an issue's title, labels, body and other fields changed with each pull or push, newest first."`
+	Timeline   TimelineCommand   `command:"timeline" description:"Show the GitHub timeline of an issue" long-description:"Show who labeled, assigned, closed, reopened or renamed an issue and where it was referenced, as fetched by pull --timeline."`
 	Diff       DiffCommand       `command:"diff" description:"Show diff between local and original/remote" long-description:"Show what changed in a local issue compared to the last synced version or current remote state."`
+	Lint       LintCommand       `command:"lint" description:"Validate issue files" long-description:"Check issue files for misspelled front matter keys, unknown labels, milestones and issue types, invalid state reasons, misplaced or misnamed files and duplicate numbers."`
+	Milestones MilestonesCommand `command:"milestones" description:"List milestones and their progress" long-description:"List open milestones with their due dates and the number of open and closed issues. --progress adds the share of closed issues and a burndown chart of the open issues over time; --mermaid prints a Mermaid gantt chart of the milestones with due dates."`
+	Stats      StatsCommand      `command:"stats" description:"Show issue statistics" long-description:"Report on the local issues: open and closed counts by label, milestone, assignee and type, the median time to close, the age of open issues and the issues opened and closed per week. Works offline; pull with --all first to include closed issues."`
+	Changelog  ChangelogCommand  `command:"changelog" description:"Generate release notes from closed issues" long-description:"Print Markdown release notes for the issues closed as completed in a milestone or time range, grouped into sections by label or issue type (configured under changelog.sections), with links and author credit. Use --format release for the body of a GitHub release."`
+	Stale      StaleCommand      `command:"stale" description:"Apply stale issue policies" long-description:"Evaluate the policies under stale.policies in the config against the local issues: open issues without an update for a policy's days get its label (default stale) and a pending comment, and issues still stale after close_after_days more days are closed (default as not planned). The changes are staged for the next push; use --dry-run to only show them."`
+	TodoScan   TodoScanCommand   `command:"todo-scan" description:"Create issues from TODO comments" long-description:"Find TODO and FIXME comments without an issue reference in the files of the git working tree (ignored files are skipped) and create a local issue for each, linking the line and quoting the code around it. With --insert-refs the new issue reference is added to the comment and renumbered by the push that creates the issue."`
+	GitScan    GitScanCommand    `command:"git-scan" description:"Close issues fixed by local commits" long-description:"Read commit messages from git log and close issues referenced with closing keywords (Fixes #123, Closes #T1a2b) locally as completed, queuing a comment that names the commit. Scans commits not pushed upstream by default."`
+	Undo       UndoCommand       `command:"undo" description:"Undo the last pull or push" long-description:"Restore local issue files and originals to their state before the last pull or push. For pushes, issues it edited on GitHub are set back to their previous title, body, labels, assignees, milestone and state, and issues it created are closed as not planned."`
+	Lock       LockCommand       `command:"lock" description:"Inspect or break the sync lock" long-description:"Commands that change issue files take an exclusive lock, read-only commands a shared one. Use status to see who holds it and break to remove a lock left by a hung process."`
 	WriteSkill WriteSkillCommand `command:"write-skill" description:"Write agent skill file" long-description:"Write the gh-issue-sync skill file for coding agents to the specified location."`
 }
 
@@ -45,21 +64,25 @@ type InitCommand struct {
 
 type PullCommand struct {
 	BaseCommand
-	All   bool     `long:"all" description:"Pull all issues (including closed)"`
-	Force bool     `long:"force" description:"Overwrite local changes"`
-	Full  bool     `long:"full" description:"Force full sync (bypass incremental)"`
-	Label []string `long:"label" value-name:"LABEL" description:"Filter by label (repeatable)"`
-	Args  struct {
+	All         bool     `long:"all" description:"Pull all issues (including closed)"`
+	Force       bool     `long:"force" description:"Overwrite local changes"`
+	Full        bool     `long:"full" description:"Force full sync (bypass incremental)"`
+	Label       []string `long:"label" value-name:"LABEL" description:"Filter by label (repeatable)"`
+	Attachments string   `long:"attachments" optional:"yes" optional-value:"download" choice:"download" choice:"rewrite" value-name:"MODE" description:"Download images and attachments into .issues/.sync/assets (rewrite also points local bodies at the copies); remembered for later pulls"`
+	Timeline    bool     `long:"timeline" description:"Also fetch issue timelines (labels, assignments, closes, references) into .issues/.sync/timeline"`
+	Args        struct {
 		Issues []string `positional-arg-name:"issue" description:"Issue numbers, local IDs, or paths to pull"`
 	} `positional-args:"yes"`
 }
 
 type PushCommand struct {
 	BaseCommand
-	DryRun     bool `long:"dry-run" description:"Show what would happen without pushing"`
-	NoComments bool `long:"no-comments" description:"Skip posting pending comments"`
-	Force      bool `long:"force" description:"Skip conflict detection and push anyway"`
-	Args       struct {
+	DryRun      bool `long:"dry-run" description:"Show what would happen without pushing"`
+	NoComments  bool `long:"no-comments" description:"Skip posting pending comments"`
+	Force       bool `long:"force" description:"Skip conflict detection and push anyway"`
+	RewriteRefs bool `long:"rewrite-refs" description:"Rewrite local issue references in source files"`
+	NoUploads   bool `long:"no-upload-images" description:"Push images referenced by local path without uploading them"`
+	Args        struct {
 		Issues []string `positional-arg-name:"issue" description:"Issue numbers, local IDs, or paths to push"`
 	} `positional-args:"yes"`
 }
@@ -71,6 +94,14 @@ type SyncCommand struct {
 	Label []string `long:"label" value-name:"LABEL" description:"Filter by label (repeatable)"`
 }
 
+type WatchCommand struct {
+	BaseCommand
+	Interval time.Duration `long:"interval" short:"i" value-name:"DURATION" default:"5m" description:"Time between pulls"`
+	Debounce time.Duration `long:"debounce" value-name:"DURATION" default:"2s" description:"Wait this long after the last local edit before pushing"`
+	Confirm  bool          `long:"confirm" description:"Ask before pushing local changes"`
+	NoPush   bool          `long:"no-push" description:"Only pull, never push"`
+}
+
 type StatusCommand struct {
 	BaseCommand
 }
@@ -90,11 +121,27 @@ type ListCommand struct {
 	Search    string   `long:"search" short:"S" value-name:"QUERY" description:"Search with GitHub-style query (e.g. 'error no:assignee sort:created-asc')"`
 }
 
+type BoardCommand struct {
+	BaseCommand
+	By      string `long:"by" short:"b" value-name:"GROUPING" default:"status" description:"Group by status, field:NAME, label:PREFIX, milestone or assignee"`
+	Project string `long:"project" short:"p" value-name:"NAME" description:"Project to read status and fields from (default: the most used one)"`
+	All     bool   `long:"all" short:"a" description:"Include closed issues"`
+	Search  string `long:"search" short:"S" value-name:"QUERY" description:"Only show issues matching a GitHub-style query"`
+}
+
+type TUICommand struct {
+	BaseCommand
+	All    bool   `long:"all" short:"a" description:"Include closed issues"`
+	Search string `long:"search" short:"S" value-name:"QUERY" description:"Initial filter as a GitHub-style query"`
+}
+
 type NewCommand struct {
 	BaseCommand
-	Edit   bool     `long:"edit" description:"Open in $EDITOR before creating the file"`
-	Labels []string `long:"label" value-name:"LABEL" description:"Add label (repeatable)"`
-	Args   struct {
+	Edit          bool     `long:"edit" description:"Open in $EDITOR before creating the file"`
+	Labels        []string `long:"label" value-name:"LABEL" description:"Add label (repeatable)"`
+	Template      string   `long:"template" short:"t" value-name:"NAME" description:"Start from an issue template in .github/ISSUE_TEMPLATE"`
+	ListTemplates bool     `long:"list-templates" description:"List available issue templates"`
+	Args          struct {
 		Title string `positional-arg-name:"title" description:"Issue title (optional with --edit)"`
 	} `positional-args:"yes"`
 }
@@ -102,10 +149,29 @@ type NewCommand struct {
 type EditCommand struct {
 	BaseCommand
 	Args struct {
-		Number string `positional-arg-name:"issue" description:"Issue number or local ID" required:"yes"`
+		Number string `positional-arg-name:"issue" description:"Issue number or local ID (default: issue of the current branch)"`
 	} `positional-args:"yes"`
 }
 
+type CommentCommand struct {
+	BaseCommand
+	Body string `long:"body" short:"m" value-name:"TEXT" description:"Comment text (appended to a pending comment)"`
+	Args struct {
+		Issue string `positional-arg-name:"issue" description:"Issue number, local ID, or path (default: issue of the current branch)"`
+	} `positional-args:"yes"`
+}
+
+type StartCommand struct {
+	BaseCommand
+	Args struct {
+		Issue string `positional-arg-name:"issue" description:"Issue number, local ID, or path" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+type CurrentCommand struct {
+	BaseCommand
+}
+
 type CloseCommand struct {
 	BaseCommand
 	Reason string `long:"reason" choice:"completed" choice:"not_planned" value-name:"REASON" description:"Close reason (completed or not_planned)"`
@@ -125,18 +191,109 @@ type ViewCommand struct {
 	BaseCommand
 	Raw  bool `long:"raw" description:"Show raw file content"`
 	Args struct {
-		Issue string `positional-arg-name:"issue" description:"Issue number, local ID, or path" required:"yes"`
+		Issue string `positional-arg-name:"issue" description:"Issue number, local ID, or path (default: issue of the current branch)"`
 	} `positional-args:"yes"`
 }
 
 type DiffCommand struct {
 	BaseCommand
-	Remote bool `long:"remote" description:"Diff against current remote state instead of last synced original"`
+	Remote bool   `long:"remote" description:"Diff against current remote state instead of last synced original"`
+	Since  string `long:"since" value-name:"TIME" description:"Diff against the synced version at a date (2006-01-02), timestamp or age (7d)"`
 	Args   struct {
 		Number string `positional-arg-name:"issue" description:"Issue number or local ID (omit to diff all)"`
 	} `positional-args:"yes"`
 }
 
+type LogCommand struct {
+	BaseCommand
+	Args struct {
+		Issue string `positional-arg-name:"issue" description:"Issue number, local ID, or path" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+type TimelineCommand struct {
+	BaseCommand
+	Args struct {
+		Issue string `positional-arg-name:"issue" description:"Issue number or path" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+type LockCommand struct {
+	Status LockStatusCommand `command:"status" description:"Show who holds the sync lock"`
+	Break  LockBreakCommand  `command:"break" description:"Remove the sync lock" long-description:"Remove the sync lock file so new commands stop waiting. Refuses while the lock is held unless --force is given."`
+}
+
+type LockStatusCommand struct {
+	BaseCommand
+}
+
+type LockBreakCommand struct {
+	BaseCommand
+	Force bool `long:"force" short:"f" description:"Break the lock even if it is held"`
+}
+
+type GitScanCommand struct {
+	BaseCommand
+	MaxCount    int  `long:"max-count" short:"n" value-name:"N" description:"Only scan the last N commits of the range"`
+	InstallHook bool `long:"install-hook" description:"Install a post-commit hook that scans each new commit"`
+	Args        struct {
+		Range string `positional-arg-name:"range" description:"Revision range passed to git log (default: @{upstream}..HEAD)"`
+	} `positional-args:"yes"`
+}
+
+type MilestonesCommand struct {
+	BaseCommand
+	Progress bool `long:"progress" short:"p" description:"Show percentage complete and a burndown chart"`
+	Mermaid  bool `long:"mermaid" description:"Print a Mermaid gantt chart of milestones with due dates"`
+	All      bool `long:"all" description:"Include closed milestones"`
+}
+
+type StatsCommand struct {
+	BaseCommand
+	Format string `long:"format" choice:"text" choice:"json" default:"text" description:"Output format (text or json)"`
+	Weeks  int    `long:"weeks" value-name:"N" default:"8" description:"Number of weeks of throughput to show"`
+}
+
+type ChangelogCommand struct {
+	BaseCommand
+	Milestone string `long:"milestone" short:"M" value-name:"NAME" description:"Only issues in this milestone"`
+	Since     string `long:"since" value-name:"TIME" description:"Only issues closed since a date (2006-01-02), timestamp or age (30d)"`
+	Until     string `long:"until" value-name:"TIME" description:"Only issues closed until a date (inclusive), timestamp or age"`
+	Format    string `long:"format" choice:"changelog" choice:"release" default:"changelog" description:"Output format (changelog for CHANGELOG.md, release for a GitHub release)"`
+	Title     string `long:"title" value-name:"TITLE" description:"Heading of the changelog entry (default: milestone or Unreleased)"`
+}
+
+type StaleCommand struct {
+	BaseCommand
+	DryRun bool `long:"dry-run" description:"Show the changes without staging them"`
+}
+
+type TodoScanCommand struct {
+	BaseCommand
+	Labels     []string `long:"label" value-name:"LABEL" description:"Add label to created issues (repeatable)"`
+	InsertRefs bool     `long:"insert-refs" description:"Add the new issue reference to each comment, like TODO(#T1a2b)"`
+	DryRun     bool     `long:"dry-run" description:"Show the issues that would be created"`
+	Args       struct {
+		Globs []string `positional-arg-name:"glob" description:"Only scan files matching these globs (e.g. '*.go' 'src/**')"`
+	} `positional-args:"yes"`
+}
+
+type UndoCommand struct {
+	BaseCommand
+	List      bool `long:"list" description:"List recorded operations instead of undoing"`
+	Force     bool `long:"force" short:"f" description:"Undo even if local files or remote issues changed since"`
+	LocalOnly bool `long:"local-only" description:"Only restore local files, leave GitHub as it is"`
+}
+
+type LintCommand struct {
+	BaseCommand
+	Format string `long:"format" choice:"text" choice:"json" choice:"github" default:"text" description:"Output format (text, json, or github for Actions annotations)"`
+	Strict bool   `long:"strict" description:"Exit with an error on warnings too"`
+	Args   struct {
+		Files []string `positional-arg-name:"file" description:"Issue files to report on (default: all)"`
+	} `positional-args:"yes"`
+}
+
 type WriteSkillCommand struct {
 	Output string `long:"output" short:"o" value-name:"DIR" description:"Output directory (overrides --agent)"`
 	Agent  string `long:"agent" short:"a" value-name:"AGENT" description:"Target agent (codex, pi, claude, amp, opencode, generic)"`
@@ -159,6 +316,10 @@ func (c *SyncCommand) Usage() string {
 	return "[OPTIONS]"
 }
 
+func (c *WatchCommand) Usage() string {
+	return "[OPTIONS]"
+}
+
 func (c *StatusCommand) Usage() string {
 	return "[OPTIONS]"
 }
@@ -167,11 +328,27 @@ func (c *ListCommand) Usage() string {
 	return "[OPTIONS]"
 }
 
+func (c *BoardCommand) Usage() string {
+	return "[OPTIONS]"
+}
+
+func (c *TUICommand) Usage() string {
+	return "[OPTIONS]"
+}
+
 func (c *NewCommand) Usage() string {
 	return "[OPTIONS]"
 }
 
 func (c *EditCommand) Usage() string {
+	return "[issue]"
+}
+
+func (c *CommentCommand) Usage() string {
+	return "[OPTIONS] [issue]"
+}
+
+func (c *StartCommand) Usage() string {
 	return "<issue>"
 }
 
@@ -184,13 +361,33 @@ func (c *ReopenCommand) Usage() string {
 }
 
 func (c *ViewCommand) Usage() string {
-	return "[OPTIONS] <issue>"
+	return "[OPTIONS] [issue]"
 }
 
 func (c *DiffCommand) Usage() string {
 	return "[OPTIONS] <issue>"
 }
 
+func (c *LogCommand) Usage() string {
+	return "<issue>"
+}
+
+func (c *TimelineCommand) Usage() string {
+	return "<issue>"
+}
+
+func (c *LintCommand) Usage() string {
+	return "[OPTIONS] [file...]"
+}
+
+func (c *LockBreakCommand) Usage() string {
+	return "[--force]"
+}
+
+func (c *UndoCommand) Usage() string {
+	return "[--list] [--force] [--local-only]"
+}
+
 func (c *WriteSkillCommand) Usage() string {
 	return "[OPTIONS]"
 }
@@ -200,7 +397,7 @@ func (c *InitCommand) Execute(_ []string) error {
 }
 
 func (c *PullCommand) Execute(args []string) error {
-	opts := app.PullOptions{All: c.All, Force: c.Force, Full: c.Full, Label: c.Label}
+	opts := app.PullOptions{All: c.All, Force: c.Force, Full: c.Full, Label: c.Label, Attachments: c.Attachments, Timeline: c.Timeline}
 	if len(c.Args.Issues) > 0 {
 		return c.App.Pull(context.Background(), opts, c.Args.Issues)
 	}
@@ -208,7 +405,7 @@ func (c *PullCommand) Execute(args []string) error {
 }
 
 func (c *PushCommand) Execute(args []string) error {
-	opts := app.PushOptions{DryRun: c.DryRun, NoComments: c.NoComments, Force: c.Force}
+	opts := app.PushOptions{DryRun: c.DryRun, NoComments: c.NoComments, Force: c.Force, RewriteRefs: c.RewriteRefs, NoUploads: c.NoUploads}
 	if len(c.Args.Issues) > 0 {
 		return c.App.Push(context.Background(), opts, c.Args.Issues)
 	}
@@ -223,6 +420,17 @@ func (c *SyncCommand) Execute(_ []string) error {
 	return c.App.Pull(ctx, app.PullOptions{All: c.All, Force: true, Full: c.Full, Label: c.Label}, nil)
 }
 
+func (c *WatchCommand) Execute(_ []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return c.App.Watch(ctx, app.WatchOptions{
+		Interval: c.Interval,
+		Debounce: c.Debounce,
+		Confirm:  c.Confirm,
+		NoPush:   c.NoPush,
+	})
+}
+
 func (c *StatusCommand) Execute(_ []string) error {
 	return c.App.Status(context.Background())
 }
@@ -244,12 +452,33 @@ func (c *ListCommand) Execute(_ []string) error {
 	return c.App.List(context.Background(), opts)
 }
 
+func (c *BoardCommand) Execute(_ []string) error {
+	return c.App.Board(context.Background(), app.BoardOptions{
+		By:      c.By,
+		Project: c.Project,
+		All:     c.All,
+		Search:  c.Search,
+	})
+}
+
+func (c *TUICommand) Execute(_ []string) error {
+	return c.App.TUI(context.Background(), app.TUIOptions{
+		All:    c.All,
+		Search: c.Search,
+	})
+}
+
 func (c *NewCommand) Execute(args []string) error {
 	title := c.Args.Title
 	if title == "" && len(args) > 0 {
 		title = args[0]
 	}
-	return c.App.NewIssue(context.Background(), title, app.NewOptions{Edit: c.Edit, Labels: c.Labels})
+	return c.App.NewIssue(context.Background(), title, app.NewOptions{
+		Edit:          c.Edit,
+		Labels:        c.Labels,
+		Template:      c.Template,
+		ListTemplates: c.ListTemplates,
+	})
 }
 
 func (c *EditCommand) Execute(args []string) error {
@@ -258,11 +487,84 @@ func (c *EditCommand) Execute(args []string) error {
 		number = args[0]
 	}
 	if strings.TrimSpace(number) == "" {
-		return fmt.Errorf("issue number is required")
+		current, err := c.App.CurrentRef(context.Background())
+		if err != nil {
+			return fmt.Errorf("issue number is required: %w", err)
+		}
+		number = current
 	}
 	return c.App.Edit(context.Background(), number)
 }
 
+func (c *CommentCommand) Execute(args []string) error {
+	issue := c.Args.Issue
+	if issue == "" && len(args) > 0 {
+		issue = args[0]
+	}
+	if strings.TrimSpace(issue) == "" {
+		current, err := c.App.CurrentRef(context.Background())
+		if err != nil {
+			return fmt.Errorf("issue is required: %w", err)
+		}
+		issue = current
+	}
+	return c.App.Comment(context.Background(), issue, c.Body)
+}
+
+func (c *StartCommand) Execute(args []string) error {
+	issue := c.Args.Issue
+	if issue == "" && len(args) > 0 {
+		issue = args[0]
+	}
+	if strings.TrimSpace(issue) == "" {
+		return fmt.Errorf("issue is required")
+	}
+	return c.App.Start(context.Background(), issue)
+}
+
+func (c *MilestonesCommand) Execute(args []string) error {
+	return c.App.Milestones(context.Background(), app.MilestonesOptions{Progress: c.Progress, Mermaid: c.Mermaid, All: c.All})
+}
+
+func (c *StatsCommand) Execute(args []string) error {
+	return c.App.Stats(context.Background(), app.StatsOptions{Format: c.Format, Weeks: c.Weeks})
+}
+
+func (c *ChangelogCommand) Execute(args []string) error {
+	opts := app.ChangelogOptions{Milestone: c.Milestone, Since: c.Since, Until: c.Until, Format: c.Format, Title: c.Title}
+	return c.App.Changelog(context.Background(), opts)
+}
+
+func (c *StaleCommand) Execute(args []string) error {
+	return c.App.Stale(context.Background(), app.StaleOptions{DryRun: c.DryRun})
+}
+
+func (c *TodoScanCommand) Usage() string {
+	return "[OPTIONS] [glob...]"
+}
+
+func (c *TodoScanCommand) Execute(args []string) error {
+	globs := append(append([]string(nil), c.Args.Globs...), args...)
+	opts := app.TodoScanOptions{Labels: c.Labels, InsertRefs: c.InsertRefs, DryRun: c.DryRun}
+	return c.App.TodoScan(context.Background(), globs, opts)
+}
+
+func (c *GitScanCommand) Usage() string {
+	return "[OPTIONS] [range]"
+}
+
+func (c *GitScanCommand) Execute(args []string) error {
+	rev := c.Args.Range
+	if rev == "" && len(args) > 0 {
+		rev = args[0]
+	}
+	return c.App.GitScan(context.Background(), rev, app.GitScanOptions{MaxCount: c.MaxCount, InstallHook: c.InstallHook})
+}
+
+func (c *CurrentCommand) Execute(args []string) error {
+	return c.App.Current(context.Background())
+}
+
 func (c *CloseCommand) Execute(args []string) error {
 	number := c.Args.Number
 	if number == "" && len(args) > 0 {
@@ -291,7 +593,11 @@ func (c *ViewCommand) Execute(args []string) error {
 		issue = args[0]
 	}
 	if strings.TrimSpace(issue) == "" {
-		return fmt.Errorf("issue is required")
+		current, err := c.App.CurrentRef(context.Background())
+		if err != nil {
+			return fmt.Errorf("issue is required: %w", err)
+		}
+		issue = current
 	}
 	return c.App.View(context.Background(), issue, app.ViewOptions{Raw: c.Raw})
 }
@@ -301,10 +607,58 @@ func (c *DiffCommand) Execute(args []string) error {
 	if number == "" && len(args) > 0 {
 		number = args[0]
 	}
+	if c.Remote && c.Since != "" {
+		return fmt.Errorf("--remote and --since cannot be combined")
+	}
 	if strings.TrimSpace(number) == "" {
+		if c.Since != "" {
+			return fmt.Errorf("--since requires an issue")
+		}
 		return c.App.DiffAll(context.Background(), app.DiffOptions{Remote: c.Remote})
 	}
-	return c.App.Diff(context.Background(), number, app.DiffOptions{Remote: c.Remote})
+	return c.App.Diff(context.Background(), number, app.DiffOptions{Remote: c.Remote, Since: c.Since})
+}
+
+func (c *LogCommand) Execute(args []string) error {
+	issue := c.Args.Issue
+	if issue == "" && len(args) > 0 {
+		issue = args[0]
+	}
+	if strings.TrimSpace(issue) == "" {
+		return fmt.Errorf("issue is required")
+	}
+	return c.App.Log(context.Background(), issue)
+}
+
+func (c *TimelineCommand) Execute(args []string) error {
+	issue := c.Args.Issue
+	if issue == "" && len(args) > 0 {
+		issue = args[0]
+	}
+	if strings.TrimSpace(issue) == "" {
+		return fmt.Errorf("issue is required")
+	}
+	return c.App.Timeline(context.Background(), issue)
+}
+
+func (c *LockStatusCommand) Execute(_ []string) error {
+	return c.App.LockStatus(context.Background())
+}
+
+func (c *LockBreakCommand) Execute(_ []string) error {
+	return c.App.LockBreak(context.Background(), c.Force)
+}
+
+func (c *UndoCommand) Execute(_ []string) error {
+	return c.App.Undo(context.Background(), app.UndoOptions{List: c.List, Force: c.Force, LocalOnly: c.LocalOnly})
+}
+
+func (c *LintCommand) Execute(args []string) error {
+	opts := app.LintOptions{Format: c.Format, Strict: c.Strict}
+	if len(c.Args.Files) > 0 {
+		return c.App.Lint(context.Background(), opts, c.Args.Files)
+	}
+	return c.App.Lint(context.Background(), opts, args)
 }
 
 func (c *WriteSkillCommand) Execute(args []string) error {
@@ -394,14 +748,32 @@ func main() {
 	opts.Pull.App = application
 	opts.Push.App = application
 	opts.Sync.App = application
+	opts.Watch.App = application
 	opts.Status.App = application
 	opts.List.App = application
+	opts.Board.App = application
+	opts.TUI.App = application
 	opts.New.App = application
 	opts.Edit.App = application
 	opts.View.App = application
 	opts.Close.App = application
 	opts.Reopen.App = application
 	opts.Diff.App = application
+	opts.Log.App = application
+	opts.Timeline.App = application
+	opts.Comment.App = application
+	opts.Start.App = application
+	opts.Current.App = application
+	opts.Milestones.App = application
+	opts.Stats.App = application
+	opts.Changelog.App = application
+	opts.Stale.App = application
+	opts.TodoScan.App = application
+	opts.GitScan.App = application
+	opts.Lint.App = application
+	opts.Undo.App = application
+	opts.Lock.Status.App = application
+	opts.Lock.Break.App = application
 
 	parser := flags.NewParser(&opts, flags.HelpFlag|flags.PassDoubleDash)
 	parser.ShortDescription = "Sync GitHub issues to local Markdown files."
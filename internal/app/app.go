@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"time"
 
@@ -18,27 +19,82 @@ type App struct {
 	Root   string
 	Runner ghcli.Runner
 	Now    func() time.Time
+	In     io.Reader
 	Out    io.Writer
 	Err    io.Writer
 	Theme  *theme.Theme
+
+	// HTTPClient is used to download attachments (defaults to a client with a 30s timeout)
+	HTTPClient *http.Client
+
+	// markdownStyle and markdownWidth override the glamour style and wrap
+	// width used when rendering issue bodies (defaults: auto and 80).
+	markdownStyle string
+	markdownWidth int
 }
 
 type PullOptions struct {
-	All   bool
-	Force bool
-	Full  bool // Force full sync, bypassing incremental
-	Label []string
+	All         bool
+	Force       bool
+	Full        bool // Force full sync, bypassing incremental
+	Label       []string
+	Attachments string // "download" or "rewrite"; empty uses the configured mode
+	Timeline    bool   // Also fetch the timeline of each pulled issue
 }
 
 type PushOptions struct {
+	DryRun      bool
+	NoComments  bool
+	Force       bool
+	RewriteRefs bool
+	NoUploads   bool // Push images referenced by local path without uploading them
+}
+
+type MilestonesOptions struct {
+	Progress bool
+	Mermaid  bool
+	All      bool
+}
+
+type StaleOptions struct {
+	DryRun bool
+}
+
+type StatsOptions struct {
+	Format string // text or json
+	Weeks  int
+}
+
+type ChangelogOptions struct {
+	Milestone string
+	Since     string
+	Until     string
+	Format    string // changelog or release
+	Title     string
+}
+
+type TodoScanOptions struct {
+	Labels     []string
+	InsertRefs bool
 	DryRun     bool
-	NoComments bool
-	Force      bool
+}
+
+type GitScanOptions struct {
+	MaxCount    int
+	InstallHook bool
+}
+
+type UndoOptions struct {
+	List      bool
+	Force     bool
+	LocalOnly bool // Only restore local files, leave GitHub as it is
 }
 
 type NewOptions struct {
-	Labels []string
-	Edit   bool
+	Labels        []string
+	Edit          bool
+	Template      string
+	ListTemplates bool
 }
 
 type CloseOptions struct {
@@ -47,12 +103,18 @@ type CloseOptions struct {
 
 type DiffOptions struct {
 	Remote bool
+	Since  string // Diff against the synced version current at this time
 }
 
 type ViewOptions struct {
 	Raw bool
 }
 
+type LintOptions struct {
+	Format string // "text", "json", or "github"
+	Strict bool   // Treat warnings as errors
+}
+
 type ListOptions struct {
 	All       bool
 	State     string
@@ -67,11 +129,31 @@ type ListOptions struct {
 	Search    string
 }
 
+type BoardOptions struct {
+	By      string
+	Project string
+	All     bool
+	Search  string
+}
+
+type WatchOptions struct {
+	Interval time.Duration // time between pulls
+	Debounce time.Duration // quiet period after a local edit before pushing
+	Confirm  bool          // ask before each push
+	NoPush   bool          // only pull
+}
+
+type TUIOptions struct {
+	All    bool
+	Search string
+}
+
 func New(root string, runner ghcli.Runner, out io.Writer, errOut io.Writer) *App {
 	return &App{
 		Root:   root,
 		Runner: runner,
 		Now:    time.Now,
+		In:     os.Stdin,
 		Out:    out,
 		Err:    errOut,
 		Theme:  theme.Default(),
@@ -0,0 +1,274 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/assets"
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// Attachment modes
+const (
+	AttachmentsDownload = "download"
+	AttachmentsRewrite  = "rewrite"
+)
+
+// defaultAttachmentBranch hosts images uploaded from local issue bodies.
+const defaultAttachmentBranch = "issue-attachments"
+
+// maxUploadSize is the largest local file uploaded on push.
+const maxUploadSize = 25 << 20
+
+// downloadTimeout bounds a single attachment download so a stalled
+// server cannot hang a pull.
+const downloadTimeout = 30 * time.Second
+
+// attachmentRef is how rewritten bodies reference stored attachments. Issue
+// files live in open/ or closed/, so the path is relative to those.
+const attachmentRef = "../" + paths.SyncDirName + "/" + paths.AssetsDirName + "/"
+
+// attachments translates issue bodies between their GitHub form and their
+// local form: images uploaded from local files are referenced by their local
+// path, and in rewrite mode downloaded attachments point at the stored copies.
+type attachments struct {
+	mode        string // Download mode, empty if downloads are disabled
+	store       *assets.Store
+	token       string
+	upload      bool // Local images may be uploaded to the attachment branch
+	branch      string
+	branchReady bool
+	downloaded  int
+	uploaded    int
+	recorded    bool // Uploads changed and the manifest needs saving
+	failed      int
+}
+
+// loadAttachments opens the attachment store for the configured mode.
+func loadAttachments(p paths.Paths, cfg config.Config) (*attachments, error) {
+	store, err := assets.Load(p.AssetsDir)
+	if err != nil {
+		return nil, err
+	}
+	branch := cfg.Sync.AttachmentBranch
+	if branch == "" {
+		branch = defaultAttachmentBranch
+	}
+	upload := cfg.Sync.UploadImages == nil || *cfg.Sync.UploadImages
+	return &attachments{mode: cfg.Sync.Attachments, store: store, upload: upload, branch: branch}, nil
+}
+
+// localBody returns the body of issue number as it is stored in local issue
+// files, without the hidden creation marker.
+func (att *attachments) localBody(number, body string) string {
+	body = issue.StripCreationMarker(body)
+	if att.mode == AttachmentsRewrite {
+		body = att.store.Localize(body, attachmentRef)
+	}
+	return att.store.UnmapUploads(number, body)
+}
+
+// remoteBody returns the body of issue number as it should be sent to
+// GitHub, without uploading anything. Local images are replaced with their
+// upload if the file was uploaded for the issue with its current content;
+// others are left as they are. Without issuePath references are not
+// resolved and map to their latest upload.
+func (a *App) remoteBody(att *attachments, number, issuePath, body string) string {
+	if att.mode == AttachmentsRewrite {
+		body = att.store.Restore(body, attachmentRef)
+	}
+	return assets.ReplaceLocalImages(body, func(ref string) (string, bool) {
+		upload, ok := a.hostedImage(att, number, issuePath, ref)
+		return upload.URL, ok
+	})
+}
+
+// hostedImage returns the recorded upload of a local image reference. If
+// the file cannot be found (or issuePath is empty) the latest upload of the
+// reference is used, so bodies keep their images on machines without it.
+func (a *App) hostedImage(att *attachments, number, issuePath, ref string) (assets.Upload, bool) {
+	if issuePath != "" {
+		if path, ok := a.resolveLocalImage(issuePath, ref); ok {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return assets.Upload{}, false
+			}
+			return att.store.IssueUpload(number, filepath.ToSlash(relPath(a.Root, path)), assets.Hash(data))
+		}
+	}
+	return att.store.LatestUpload(number, ref)
+}
+
+// resolveLocalImage finds the file a local image reference in the issue at
+// issuePath points to. References are relative to the issue file, falling
+// back to the repository root.
+func (a *App) resolveLocalImage(issuePath, ref string) (string, bool) {
+	candidates := []string{ref}
+	if unescaped, err := url.PathUnescape(ref); err == nil && unescaped != ref {
+		candidates = append(candidates, unescaped)
+	}
+	for _, candidate := range candidates {
+		for _, dir := range []string{filepath.Dir(issuePath), a.Root} {
+			path := filepath.Join(dir, filepath.FromSlash(candidate))
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
+
+// pendingUploads returns the local files referenced by body that push would
+// upload.
+func (a *App) pendingUploads(att *attachments, number, issuePath, body string) []string {
+	if !att.upload {
+		return nil
+	}
+	var files []string
+	for _, ref := range assets.FindLocalImages(a.remoteBody(att, number, issuePath, body)) {
+		if path, ok := a.resolveLocalImage(issuePath, ref); ok {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// pushBody returns the body of issue number as it should be sent to GitHub.
+// If uploads are enabled, images the body references by local path that were
+// not uploaded with their current content are uploaded to the attachment
+// branch first.
+func (a *App) pushBody(ctx context.Context, att *attachments, client *ghcli.Client, number, issuePath, body string) (string, error) {
+	body = a.remoteBody(att, number, issuePath, body)
+	if !att.upload {
+		return body, nil
+	}
+	var uploadErr error
+	body = assets.ReplaceLocalImages(body, func(ref string) (string, bool) {
+		if uploadErr != nil {
+			return "", false
+		}
+		path, ok := a.resolveLocalImage(issuePath, ref)
+		if !ok {
+			return "", false
+		}
+		hosted, err := a.uploadLocalImage(ctx, att, client, number, ref, path)
+		if err != nil {
+			uploadErr = fmt.Errorf("uploading %s: %w", relPath(a.Root, path), err)
+			return "", false
+		}
+		return hosted, true
+	})
+	if uploadErr != nil {
+		return "", uploadErr
+	}
+	return body, nil
+}
+
+// uploadLocalImage uploads the file at path (unless the same content was
+// uploaded before) and records which issue and reference it came from.
+func (a *App) uploadLocalImage(ctx context.Context, att *attachments, client *ghcli.Client, number, ref, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if len(data) > maxUploadSize {
+		return "", fmt.Errorf("file exceeds %d MB", maxUploadSize>>20)
+	}
+	sha := assets.Hash(data)
+	upload, found := att.store.FindUpload(sha)
+	if !found {
+		if !att.branchReady {
+			readme := "Files referenced from issues, uploaded by gh-issue-sync.\n"
+			if err := client.EnsureBranch(ctx, att.branch, readme); err != nil {
+				return "", err
+			}
+			att.branchReady = true
+		}
+		name := sha + strings.ToLower(filepath.Ext(path))
+		hosted, err := client.UploadFile(ctx, att.branch, name, data, "Upload "+filepath.Base(path))
+		if err != nil {
+			return "", err
+		}
+		upload = assets.Upload{URL: hosted, SHA256: sha}
+		att.uploaded++
+	}
+	upload.Issue = number
+	upload.Ref = ref
+	upload.Path = filepath.ToSlash(relPath(a.Root, path))
+	upload.UploadedAt = a.Now().UTC()
+	att.store.RecordUpload(upload)
+	att.recorded = true
+	return upload.URL, nil
+}
+
+// fetchAttachments downloads every attachment in body that is not stored yet.
+// Failures are reported as warnings and do not stop the pull.
+func (a *App) fetchAttachments(ctx context.Context, att *attachments, number, body string) {
+	for _, u := range assets.FindURLs(body) {
+		if _, ok := att.store.Lookup(u); ok {
+			continue
+		}
+		data, contentType, err := assets.Download(ctx, a.httpClient(), u, att.token)
+		if err == nil {
+			_, err = att.store.Add(u, data, contentType, a.Now().UTC())
+		}
+		if err != nil {
+			fmt.Fprintf(a.Err, "%s downloading attachment for #%s: %v\n", a.Theme.WarningText("Warning:"), number, err)
+			att.failed++
+			continue
+		}
+		att.downloaded++
+	}
+}
+
+// resolveAttachmentMode persists the attachment mode for a pull when it was
+// given explicitly. It reports whether the mode changed, in which case all
+// issues need to be processed again.
+func resolveAttachmentMode(p paths.Paths, cfg *config.Config, requested string) (bool, error) {
+	if requested == "" || requested == cfg.Sync.Attachments {
+		return false, nil
+	}
+	cfg.Sync.Attachments = requested
+	if err := config.Save(p.ConfigPath, *cfg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// prepareAttachmentDownloads looks up the token used to download private
+// attachments. Without one, only public attachments can be fetched.
+func (a *App) prepareAttachmentDownloads(ctx context.Context, att *attachments, client *ghcli.Client) {
+	token, err := client.AuthToken(ctx)
+	if err != nil {
+		fmt.Fprintf(a.Err, "%s reading gh auth token: %v\n", a.Theme.WarningText("Warning:"), err)
+		return
+	}
+	att.token = token
+}
+
+// displayAttachments replaces attachment URLs and rewritten references in
+// body with paths to the stored copies, relative to the repository root.
+func (a *App) displayAttachments(p paths.Paths, body string) string {
+	store, err := assets.Load(p.AssetsDir)
+	if err != nil || len(store.Manifest.Assets) == 0 {
+		return body
+	}
+	display := relPath(a.Root, p.AssetsDir) + string(filepath.Separator)
+	return store.Localize(store.Restore(body, attachmentRef), display)
+}
+
+func (a *App) httpClient() *http.Client {
+	if a.HTTPClient != nil {
+		return a.HTTPClient
+	}
+	return &http.Client{Timeout: downloadTimeout}
+}
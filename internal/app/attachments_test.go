@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// uploadRunner pretends the attachment branch exists and no file has been
+// uploaded yet, and records PUT requests.
+type uploadRunner struct {
+	puts int
+}
+
+func (r *uploadRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	switch {
+	case strings.Contains(joined, "/branches/"):
+		return "issue-attachments", nil
+	case strings.Contains(joined, "-X PUT"):
+		r.puts++
+		return "{}", nil
+	case strings.Contains(joined, "/contents/"):
+		return "", errors.New("gh: Not Found (HTTP 404)")
+	}
+	return "", errors.New("unexpected call: " + joined)
+}
+
+func TestPushBodyUploadsLocalImages(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "docs"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "docs", "shot.png"), []byte("png"), 0o644); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
+
+	runner := &uploadRunner{}
+	application := New(root, runner, io.Discard, io.Discard)
+	client := ghcli.NewClient(runner, "owner/repo")
+	att, err := loadAttachments(p, config.Default("owner", "repo"))
+	if err != nil {
+		t.Fatalf("load attachments: %v", err)
+	}
+
+	issuePath := filepath.Join(p.OpenDir, "1-test.md")
+	body := "![shot](docs/shot.png) ![missing](docs/missing.png)"
+
+	if !att.upload {
+		t.Fatalf("expected uploads to be enabled by default")
+	}
+
+	// With uploads disabled nothing is uploaded
+	att.upload = false
+	pushed, err := application.pushBody(context.Background(), att, client, "1", issuePath, body)
+	if err != nil {
+		t.Fatalf("push body: %v", err)
+	}
+	if pushed != body || runner.puts != 0 {
+		t.Fatalf("expected body to be left alone without uploads, got %q (%d puts)", pushed, runner.puts)
+	}
+
+	att.upload = true
+	pushed, err = application.pushBody(context.Background(), att, client, "1", issuePath, body)
+	if err != nil {
+		t.Fatalf("push body: %v", err)
+	}
+	if strings.Contains(pushed, "(docs/shot.png)") || !strings.Contains(pushed, "https://github.com/owner/repo/raw/issue-attachments/") {
+		t.Fatalf("expected image to be replaced with uploaded URL, got %q", pushed)
+	}
+	if !strings.Contains(pushed, "![missing](docs/missing.png)") {
+		t.Fatalf("expected missing file to be left alone, got %q", pushed)
+	}
+
+	// Pushing again reuses the upload, and pulling maps the URL back.
+	if _, err := application.pushBody(context.Background(), att, client, "1", issuePath, body); err != nil {
+		t.Fatalf("push body again: %v", err)
+	}
+	if runner.puts != 1 || att.uploaded != 1 {
+		t.Fatalf("expected a single upload, got %d puts and %d uploads", runner.puts, att.uploaded)
+	}
+	if local := att.localBody("1", pushed); local != body {
+		t.Fatalf("expected pulled body to match local body, got %q", local)
+	}
+	if local := att.localBody("2", pushed); local == body {
+		t.Fatalf("expected uploads of issue 1 to stay in its body, got %q", local)
+	}
+
+	// Editing the image uploads the new content
+	if err := os.WriteFile(filepath.Join(root, "docs", "shot.png"), []byte("png v2"), 0o644); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
+	updated, err := application.pushBody(context.Background(), att, client, "1", issuePath, body)
+	if err != nil {
+		t.Fatalf("push body: %v", err)
+	}
+	if runner.puts != 2 || updated == pushed {
+		t.Fatalf("expected changed image to be uploaded again, got %d puts and %q", runner.puts, updated)
+	}
+	if local := att.localBody("1", updated); local != body {
+		t.Fatalf("expected pulled body to match local body, got %q", local)
+	}
+}
@@ -0,0 +1,370 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+	"github.com/mitsuhiko/gh-issue-sync/internal/search"
+)
+
+// Board layout limits
+const (
+	boardMinColumnWidth = 18
+	boardMaxColumnWidth = 40
+	boardColumnGap      = 2
+)
+
+// boardColumn is one column of the board with the issues it holds.
+type boardColumn struct {
+	Name   string
+	Issues []IssueFile
+}
+
+// boardGrouping describes how issues are assigned to columns.
+type boardGrouping struct {
+	// keys returns the columns an issue belongs to; none means the empty column
+	keys func(item IssueFile) []string
+	// order lists known column names in display order (may be empty)
+	order []string
+	// empty names the column for issues without a value
+	empty string
+}
+
+// Board renders local issues as a kanban board grouped by a project field,
+// label prefix, milestone or assignee.
+func (a *App) Board(ctx context.Context, opts BoardOptions) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+	t := a.Theme
+
+	result := loadLocalIssuesWithErrors(p)
+	for _, parseErr := range result.Errors {
+		fmt.Fprintf(a.Err, "%s %v\n", t.WarningText("Warning:"), parseErr)
+	}
+
+	var searchQuery *search.Query
+	if opts.Search != "" {
+		q := search.Parse(opts.Search)
+		searchQuery = &q
+	}
+	var issues []IssueFile
+	for _, item := range result.Issues {
+		if !opts.All && item.State != "open" && (searchQuery == nil || searchQuery.State == "") {
+			continue
+		}
+		if searchQuery != nil && !searchQuery.Match(searchData(item)) {
+			continue
+		}
+		issues = append(issues, item)
+	}
+
+	grouping, err := boardGroupingFor(p, opts, issues)
+	if err != nil {
+		return err
+	}
+	columns := buildBoardColumns(issues, grouping)
+	if len(columns) == 0 {
+		fmt.Fprintln(a.Out, t.MutedText("No issues found"))
+		return nil
+	}
+
+	width := getTerminalWidth(a.Out)
+	if width > 0 && len(columns)*(boardMinColumnWidth+boardColumnGap) <= width+boardColumnGap {
+		a.printBoardColumns(columns, width)
+	} else {
+		a.printBoardList(columns)
+	}
+	return nil
+}
+
+// boardGroupingFor parses the --by option. Supported values are "status",
+// "field:NAME", "label:PREFIX", "milestone" and "assignee".
+func boardGroupingFor(p paths.Paths, opts BoardOptions, issues []IssueFile) (boardGrouping, error) {
+	by := opts.By
+	if by == "" {
+		by = "status"
+	}
+	kind, arg, _ := strings.Cut(by, ":")
+	switch strings.ToLower(kind) {
+	case "status":
+		return projectFieldGrouping(p, opts.Project, "Status", issues), nil
+	case "field":
+		if arg == "" {
+			return boardGrouping{}, fmt.Errorf("--by field:NAME requires a field name")
+		}
+		return projectFieldGrouping(p, opts.Project, arg, issues), nil
+	case "label":
+		if arg == "" {
+			return boardGrouping{}, fmt.Errorf("--by label:PREFIX requires a label prefix (e.g. label:status:)")
+		}
+		return labelPrefixGrouping(arg), nil
+	case "milestone":
+		return boardGrouping{
+			keys: func(item IssueFile) []string {
+				if item.Issue.Milestone == "" {
+					return nil
+				}
+				return []string{item.Issue.Milestone}
+			},
+			order: milestoneOrder(p),
+			empty: "No milestone",
+		}, nil
+	case "assignee":
+		return boardGrouping{
+			keys:  func(item IssueFile) []string { return item.Issue.Assignees },
+			empty: "Unassigned",
+		}, nil
+	}
+	return boardGrouping{}, fmt.Errorf("unknown grouping %q (use status, field:NAME, label:PREFIX, milestone or assignee)", by)
+}
+
+// projectFieldGrouping groups by a project field. Without an explicit project
+// the project most issues have the field set in is used. Columns follow the
+// option order cached in project_fields.json when available.
+func projectFieldGrouping(p paths.Paths, project, field string, issues []IssueFile) boardGrouping {
+	if project == "" {
+		counts := make(map[string]int)
+		for _, item := range issues {
+			for title, fields := range item.Issue.ProjectFields {
+				if fieldValue(fields, field) != "" {
+					counts[title]++
+				}
+			}
+		}
+		for title, count := range counts {
+			if project == "" || count > counts[project] || (count == counts[project] && title < project) {
+				project = title
+			}
+		}
+	}
+
+	var order []string
+	if cache, err := loadProjectFieldCache(p); err == nil {
+		for _, entry := range cache.Projects {
+			if !strings.EqualFold(entry.Title, project) {
+				continue
+			}
+			if def, ok := findProjectField(entry.Fields, field); ok {
+				for _, opt := range def.Options {
+					order = append(order, opt.Name)
+				}
+			}
+		}
+	}
+
+	return boardGrouping{
+		keys: func(item IssueFile) []string {
+			for title, fields := range item.Issue.ProjectFields {
+				if !strings.EqualFold(title, project) {
+					continue
+				}
+				if value := fieldValue(fields, field); value != "" {
+					return []string{value}
+				}
+			}
+			return nil
+		},
+		order: order,
+		empty: "No " + strings.ToLower(field),
+	}
+}
+
+// fieldValue looks up a field value by name, ignoring case.
+func fieldValue(fields map[string]string, name string) string {
+	if value, ok := fields[name]; ok {
+		return value
+	}
+	for key, value := range fields {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// labelPrefixGrouping groups by labels such as "status:todo", using the part
+// after the prefix as the column name.
+func labelPrefixGrouping(prefix string) boardGrouping {
+	lowerPrefix := strings.ToLower(prefix)
+	return boardGrouping{
+		keys: func(item IssueFile) []string {
+			var keys []string
+			for _, label := range item.Issue.Labels {
+				if strings.HasPrefix(strings.ToLower(label), lowerPrefix) && len(label) > len(prefix) {
+					keys = append(keys, strings.TrimSpace(label[len(prefix):]))
+				}
+			}
+			return keys
+		},
+		empty: "No " + strings.TrimRight(prefix, ":/ "),
+	}
+}
+
+// milestoneOrder orders milestones by due date, as cached on the last pull.
+func milestoneOrder(p paths.Paths) []string {
+	cache, err := loadMilestoneCache(p)
+	if err != nil {
+		return nil
+	}
+	milestones := append([]MilestoneEntry(nil), cache.Milestones...)
+	sort.SliceStable(milestones, func(i, j int) bool {
+		di, dj := milestones[i].DueOn, milestones[j].DueOn
+		if (di == nil) != (dj == nil) {
+			return di != nil
+		}
+		return di != nil && *di < *dj
+	})
+	order := make([]string, 0, len(milestones))
+	for _, m := range milestones {
+		order = append(order, m.Title)
+	}
+	return order
+}
+
+// buildBoardColumns assigns issues to columns. Known columns come first in
+// their configured order (empty ones included, so the board shape is stable),
+// then other values alphabetically, then the column for issues without a
+// value.
+func buildBoardColumns(issues []IssueFile, grouping boardGrouping) []boardColumn {
+	if len(issues) == 0 {
+		return nil
+	}
+	sortBoardIssues(issues)
+	byName := make(map[string]*boardColumn)
+	var columns []*boardColumn
+	column := func(name string) *boardColumn {
+		key := strings.ToLower(name)
+		if col, ok := byName[key]; ok {
+			return col
+		}
+		col := &boardColumn{Name: name}
+		byName[key] = col
+		columns = append(columns, col)
+		return col
+	}
+	for _, name := range grouping.order {
+		column(name)
+	}
+	known := len(columns)
+
+	var empty []IssueFile
+	for _, item := range issues {
+		keys := grouping.keys(item)
+		if len(keys) == 0 {
+			empty = append(empty, item)
+			continue
+		}
+		for _, key := range keys {
+			col := column(key)
+			col.Issues = append(col.Issues, item)
+		}
+	}
+	extra := columns[known:]
+	sort.SliceStable(extra, func(i, j int) bool {
+		return strings.ToLower(extra[i].Name) < strings.ToLower(extra[j].Name)
+	})
+
+	result := make([]boardColumn, 0, len(columns)+1)
+	for _, col := range columns {
+		result = append(result, *col)
+	}
+	if len(empty) > 0 {
+		result = append(result, boardColumn{Name: grouping.empty, Issues: empty})
+	}
+	return result
+}
+
+// sortBoardIssues orders remote issues by number, then local issues.
+func sortBoardIssues(issues []IssueFile) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		ni, nj := issues[i].Issue.Number, issues[j].Issue.Number
+		if ni.IsLocal() != nj.IsLocal() {
+			return !ni.IsLocal()
+		}
+		ai, erri := strconv.Atoi(ni.String())
+		aj, errj := strconv.Atoi(nj.String())
+		if erri == nil && errj == nil {
+			return ai < aj
+		}
+		return ni.String() < nj.String()
+	})
+}
+
+// printBoardColumns renders columns side by side.
+func (a *App) printBoardColumns(columns []boardColumn, width int) {
+	t := a.Theme
+	colWidth := (width+boardColumnGap)/len(columns) - boardColumnGap
+	colWidth = min(max(colWidth, boardMinColumnWidth), boardMaxColumnWidth)
+	gap := strings.Repeat(" ", boardColumnGap)
+	reset := t.Styler().Reset()
+
+	cell := func(s string) string {
+		s = truncateAnsi(s, colWidth, reset)
+		if n := utf8.RuneCountInString(stripAnsi(s)); n < colWidth {
+			s += strings.Repeat(" ", colWidth-n)
+		}
+		return s
+	}
+
+	var headers, rules []string
+	rows := 0
+	for _, col := range columns {
+		headers = append(headers, cell(t.Bold(col.Name)+" "+t.MutedText(fmt.Sprintf("(%d)", len(col.Issues)))))
+		rules = append(rules, t.MutedText(strings.Repeat("─", colWidth)))
+		rows = max(rows, len(col.Issues))
+	}
+	fmt.Fprintln(a.Out, strings.TrimRight(strings.Join(headers, gap), " "))
+	fmt.Fprintln(a.Out, strings.Join(rules, gap))
+	for row := 0; row < rows; row++ {
+		cells := make([]string, 0, len(columns))
+		for _, col := range columns {
+			if row < len(col.Issues) {
+				cells = append(cells, cell(a.boardCard(col.Issues[row])))
+			} else {
+				cells = append(cells, strings.Repeat(" ", colWidth))
+			}
+		}
+		fmt.Fprintln(a.Out, strings.TrimRight(strings.Join(cells, gap), " "))
+	}
+}
+
+// printBoardList renders columns one below the other, used when the output
+// is not a terminal or the terminal is too narrow.
+func (a *App) printBoardList(columns []boardColumn) {
+	t := a.Theme
+	for i, col := range columns {
+		if i > 0 {
+			fmt.Fprintln(a.Out)
+		}
+		fmt.Fprintf(a.Out, "%s %s\n", t.Bold(col.Name), t.MutedText(fmt.Sprintf("(%d)", len(col.Issues))))
+		for _, item := range col.Issues {
+			fmt.Fprintf(a.Out, "  %s\n", a.boardCard(item))
+		}
+	}
+}
+
+func (a *App) boardCard(item IssueFile) string {
+	t := a.Theme
+	number := item.Issue.Number.String()
+	if item.Issue.Number.IsLocal() {
+		number = t.WarningText(number)
+	} else {
+		number = t.AccentText("#" + number)
+	}
+	return number + " " + item.Issue.Title
+}
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestBoard(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	issues := []issue.Issue{
+		{Number: "1", Title: "Done thing", State: "open", Labels: []string{"status:done"},
+			ProjectFields: issue.ProjectFields{"Roadmap": {"Status": "Done"}}},
+		{Number: "2", Title: "Todo thing", State: "open", Labels: []string{"status:todo"},
+			ProjectFields: issue.ProjectFields{"Roadmap": {"Status": "Todo"}}},
+		{Number: "3", Title: "Loose thing", State: "open"},
+		{Number: "4", Title: "Closed thing", State: "closed",
+			ProjectFields: issue.ProjectFields{"Roadmap": {"Status": "Done"}}},
+	}
+	for _, iss := range issues {
+		dir := p.OpenDir
+		if iss.State == "closed" {
+			dir = p.ClosedDir
+		}
+		if err := issue.WriteFile(filepath.Join(dir, iss.Number.String()+".md"), iss); err != nil {
+			t.Fatalf("write issue: %v", err)
+		}
+	}
+	cache := ProjectFieldCache{Projects: []ProjectFieldsEntry{{
+		ProjectID: "P1",
+		Title:     "Roadmap",
+		Fields: []ProjectFieldEntry{{
+			ID:       "F1",
+			Name:     "Status",
+			DataType: "SINGLE_SELECT",
+			Options:  []ProjectFieldOptionEntry{{ID: "a", Name: "Todo"}, {ID: "b", Name: "In Progress"}, {ID: "c", Name: "Done"}},
+		}},
+	}}}
+	if err := saveProjectFieldCache(p, cache); err != nil {
+		t.Fatalf("save cache: %v", err)
+	}
+
+	var out strings.Builder
+	application := New(root, ghcli.ExecRunner{}, &out, io.Discard)
+	if err := application.Board(context.Background(), BoardOptions{}); err != nil {
+		t.Fatalf("board: %v", err)
+	}
+	expected := "Todo (1)\n  #2 Todo thing\n\nIn Progress (0)\n\nDone (1)\n  #1 Done thing\n\nNo status (1)\n  #3 Loose thing\n"
+	if stripAnsi(out.String()) != expected {
+		t.Fatalf("unexpected board:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := application.Board(context.Background(), BoardOptions{By: "label:status:", All: true}); err != nil {
+		t.Fatalf("board by label: %v", err)
+	}
+	expected = "done (1)\n  #1 Done thing\n\ntodo (1)\n  #2 Todo thing\n\nNo status (2)\n  #3 Loose thing\n  #4 Closed thing\n"
+	if stripAnsi(out.String()) != expected {
+		t.Fatalf("unexpected label board:\n%s", out.String())
+	}
+
+	if err := application.Board(context.Background(), BoardOptions{By: "bogus"}); err == nil {
+		t.Fatalf("expected error for unknown grouping")
+	}
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+const (
+	defaultBranchPattern = "{number}-{slug}"
+	defaultProgressLabel = "in-progress"
+)
+
+func branchPattern(cfg config.Config) string {
+	if pattern := strings.TrimSpace(cfg.Branch.Pattern); pattern != "" {
+		return pattern
+	}
+	return defaultBranchPattern
+}
+
+func progressLabel(cfg config.Config) string {
+	if label := strings.TrimSpace(cfg.Branch.Label); label != "" {
+		return label
+	}
+	return defaultProgressLabel
+}
+
+// branchName returns the branch for an issue following pattern.
+func branchName(pattern string, iss issue.Issue) string {
+	name := strings.ReplaceAll(pattern, "{number}", iss.Number.String())
+	name = strings.ReplaceAll(name, "{slug}", issue.Slugify(iss.Title))
+	return strings.Trim(name, "-/")
+}
+
+// issueFromBranch extracts the issue number from a branch named following
+// pattern.
+func issueFromBranch(pattern, branch string) (string, bool) {
+	if !strings.Contains(pattern, "{number}") {
+		return "", false
+	}
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.Replace(expr, regexp.QuoteMeta("{number}"), `(?P<number>\d+|T[a-zA-Z0-9]+)`, 1)
+	expr = strings.ReplaceAll(expr, regexp.QuoteMeta("{number}"), `[^/]*`)
+	// The title may have changed since the branch was created
+	expr = strings.ReplaceAll(expr, regexp.QuoteMeta("{slug}"), `.*?`)
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return "", false
+	}
+	match := re.FindStringSubmatch(branch)
+	if match == nil {
+		// A slug that ended up empty leaves a trailing separator out
+		match = re.FindStringSubmatch(branch + "-")
+	}
+	if match == nil {
+		return "", false
+	}
+	return match[re.SubexpIndex("number")], true
+}
+
+// currentBranch returns the checked-out git branch.
+func (a *App) currentBranch(ctx context.Context) (string, error) {
+	out, err := a.Runner.Run(ctx, "git", "branch", "--show-current")
+	if err != nil {
+		return "", err
+	}
+	branch := strings.TrimSpace(out)
+	if branch == "" {
+		return "", fmt.Errorf("not on a branch (detached HEAD)")
+	}
+	return branch, nil
+}
+
+// CurrentRef returns the number of the issue the checked-out branch belongs
+// to. Commands taking an issue use it when none is given.
+func (a *App) CurrentRef(ctx context.Context) (string, error) {
+	p := paths.New(a.Root)
+	cfg, err := loadConfig(p.ConfigPath)
+	if err != nil {
+		return "", err
+	}
+	branch, err := a.currentBranch(ctx)
+	if err != nil {
+		return "", err
+	}
+	number, ok := issueFromBranch(branchPattern(cfg), branch)
+	if !ok {
+		return "", fmt.Errorf("branch %q does not match the pattern %q; pass an issue", branch, branchPattern(cfg))
+	}
+	return number, nil
+}
+
+// Current shows the issue the checked-out branch belongs to.
+func (a *App) Current(ctx context.Context) error {
+	number, err := a.CurrentRef(ctx)
+	if err != nil {
+		return err
+	}
+	file, err := findIssueByNumber(paths.New(a.Root), number)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Out, "%s %s\n", a.Theme.AccentText("#"+file.Issue.Number.String()), a.Theme.Bold(file.Issue.Title))
+	fmt.Fprintf(a.Out, "  %s\n", a.Theme.MutedText(relPath(a.Root, file.Path)))
+	return nil
+}
+
+// Start checks out the branch for an issue, creating it if needed, and marks
+// the issue as being worked on: it is assigned to the current user and gets
+// the progress label. Both changes are local until pushed.
+func (a *App) Start(ctx context.Context, ref string) error {
+	p := paths.New(a.Root)
+	cfg, err := loadConfig(p.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lck, err := lock.Acquire(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+	t := a.Theme
+
+	file, err := findIssueByRef(a.Root, p, ref)
+	if err != nil {
+		return err
+	}
+	if file.State == "closed" {
+		return fmt.Errorf("issue %s is closed; reopen it first", file.Issue.Number)
+	}
+
+	branch := branchName(branchPattern(cfg), file.Issue)
+	if _, err := a.Runner.Run(ctx, "git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		if _, err := a.Runner.Run(ctx, "git", "checkout", branch); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.Out, "Switched to branch %s\n", t.AccentText(branch))
+	} else {
+		if _, err := a.Runner.Run(ctx, "git", "checkout", "-b", branch); err != nil {
+			return err
+		}
+		fmt.Fprintf(a.Out, "Switched to a new branch %s\n", t.AccentText(branch))
+	}
+
+	updated := file.Issue
+	label := progressLabel(cfg)
+	if !containsIgnoreCase(updated.Labels, label) {
+		updated.Labels = append(append([]string(nil), updated.Labels...), label)
+	}
+	client := ghcli.NewClient(a.Runner, repoSlug(cfg))
+	if user, err := client.CurrentUser(ctx); err != nil {
+		fmt.Fprintf(a.Err, "%s looking up current user, not assigning: %v\n", t.WarningText("Warning:"), err)
+	} else if user != "" && !containsIgnoreCase(updated.Assignees, user) {
+		updated.Assignees = append(append([]string(nil), updated.Assignees...), user)
+	}
+	if issue.EqualIgnoringSyncedAt(file.Issue, updated) {
+		return nil
+	}
+	if err := issue.WriteFile(file.Path, updated); err != nil {
+		return err
+	}
+
+	labelCache, _ := loadLabelCache(p)
+	fmt.Fprintln(a.Out, t.FormatIssueHeader("M", file.Issue.Number.String(), file.Issue.Title))
+	for _, line := range a.formatChangeLines(file.Issue, updated, labelCacheToColorMap(labelCache)) {
+		fmt.Fprintln(a.Out, line)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// gitRunner fakes git with a single checked-out branch and gh with a user.
+type gitRunner struct {
+	branch   string
+	branches map[string]bool
+	calls    []string
+}
+
+func (r *gitRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := name + " " + strings.Join(args, " ")
+	r.calls = append(r.calls, joined)
+	switch {
+	case joined == "git branch --show-current":
+		return r.branch + "\n", nil
+	case strings.HasPrefix(joined, "git rev-parse --verify --quiet refs/heads/"):
+		if r.branches[strings.TrimPrefix(joined, "git rev-parse --verify --quiet refs/heads/")] {
+			return "", nil
+		}
+		return "", errors.New("exit status 1")
+	case strings.HasPrefix(joined, "git checkout -b "):
+		r.branch = strings.TrimPrefix(joined, "git checkout -b ")
+		r.branches[r.branch] = true
+		return "", nil
+	case strings.HasPrefix(joined, "git checkout "):
+		r.branch = strings.TrimPrefix(joined, "git checkout ")
+		return "", nil
+	case joined == "gh api user -q .login":
+		return "alice\n", nil
+	}
+	return "", errors.New("unexpected call: " + joined)
+}
+
+func TestIssueFromBranch(t *testing.T) {
+	tests := []struct {
+		pattern, branch, want string
+	}{
+		{"{number}-{slug}", "42-fix-login", "42"},
+		{"{number}-{slug}", "42", "42"},
+		{"{number}-{slug}", "T1abc-new-feature", "T1abc"},
+		{"feature/{number}-{slug}", "feature/7-dark-mode", "7"},
+		{"{slug}-gh{number}", "dark-mode-gh7", "7"},
+		{"{number}-{slug}", "main", ""},
+		{"feature/{number}-{slug}", "fix/7-dark-mode", ""},
+	}
+	for _, tt := range tests {
+		got, _ := issueFromBranch(tt.pattern, tt.branch)
+		if got != tt.want {
+			t.Errorf("issueFromBranch(%q, %q) = %q, want %q", tt.pattern, tt.branch, got, tt.want)
+		}
+	}
+	iss := issue.Issue{Number: "42", Title: "Fix login on Safari!"}
+	if got := branchName("feature/{number}-{slug}", iss); got != "feature/42-fix-login-on-safari" {
+		t.Errorf("unexpected branch name %q", got)
+	}
+}
+
+func TestStartAndCurrent(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	path := filepath.Join(p.OpenDir, "42-fix-login.md")
+	if err := issue.WriteFile(path, issue.Issue{Number: "42", Title: "Fix login", Labels: []string{"bug"}, State: "open"}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+
+	runner := &gitRunner{branch: "main", branches: map[string]bool{"main": true}}
+	var out bytes.Buffer
+	application := New(root, runner, &out, &out)
+
+	if err := application.Start(context.Background(), "42"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if runner.branch != "42-fix-login" {
+		t.Fatalf("expected new branch to be checked out, got %q (calls %v)", runner.branch, runner.calls)
+	}
+	started, err := issue.ParseFile(path)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if strings.Join(started.Labels, ",") != "bug,in-progress" || strings.Join(started.Assignees, ",") != "alice" {
+		t.Fatalf("expected issue to be labeled and assigned, got %+v", started)
+	}
+
+	ref, err := application.CurrentRef(context.Background())
+	if err != nil || ref != "42" {
+		t.Fatalf("expected current issue 42, got %q, %v", ref, err)
+	}
+
+	// Starting again switches to the existing branch without further edits
+	runner.branch = "main"
+	out.Reset()
+	if err := application.Start(context.Background(), "42"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if !strings.Contains(out.String(), "Switched to branch") || strings.Contains(out.String(), "Issue #42") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	if err := application.Comment(context.Background(), ref, "Working on it."); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+	if err := application.Comment(context.Background(), ref, "Found the cause."); err != nil {
+		t.Fatalf("comment: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(p.OpenDir, "42.comment.md"))
+	if err != nil || string(content) != "Working on it.\n\nFound the cause.\n" {
+		t.Fatalf("unexpected pending comment %q, %v", content, err)
+	}
+}
@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// defaultChangelogSections are used when the config has none.
+var defaultChangelogSections = []config.ChangelogSection{
+	{Title: "Features", Labels: []string{"enhancement", "feature"}, Types: []string{"Feature"}},
+	{Title: "Fixes", Labels: []string{"bug"}, Types: []string{"Bug"}},
+	{Title: "Documentation", Labels: []string{"documentation", "docs"}},
+}
+
+const otherChangelogSection = "Other"
+
+// changelogSection returns the title of the section an issue belongs to.
+func changelogSection(sections []config.ChangelogSection, iss issue.Issue) string {
+	for _, section := range sections {
+		for _, label := range section.Labels {
+			if containsIgnoreCase(iss.Labels, label) {
+				return section.Title
+			}
+		}
+		if iss.IssueType != "" && containsIgnoreCase(section.Types, iss.IssueType) {
+			return section.Title
+		}
+	}
+	return otherChangelogSection
+}
+
+// closedTime returns when an issue was closed. Issues synced before the
+// closing time was recorded fall back to their last update.
+func closedTime(iss issue.Issue) *time.Time {
+	if iss.ClosedAt != nil {
+		return iss.ClosedAt
+	}
+	return iss.UpdatedAt
+}
+
+// completedIssues returns the issues closed as completed that match opts,
+// ordered by number.
+func completedIssues(issues []IssueFile, cfg config.Config, opts ChangelogOptions, since, until time.Time) []issue.Issue {
+	var completed []issue.Issue
+	for _, item := range issues {
+		iss := item.Issue
+		if item.State != "closed" || iss.Number.IsLocal() {
+			continue
+		}
+		// Issues closed before GitHub recorded reasons have none
+		if iss.StateReason != nil && *iss.StateReason != "completed" {
+			continue
+		}
+		if opts.Milestone != "" && !strings.EqualFold(iss.Milestone, opts.Milestone) {
+			continue
+		}
+		if !since.IsZero() || !until.IsZero() {
+			closed := closedTime(iss)
+			if closed == nil || closed.Before(since) || (!until.IsZero() && !closed.Before(until)) {
+				continue
+			}
+		}
+		excluded := false
+		for _, label := range cfg.Changelog.Exclude {
+			if containsIgnoreCase(iss.Labels, label) {
+				excluded = true
+			}
+		}
+		if !excluded {
+			completed = append(completed, iss)
+		}
+	}
+	sort.Slice(completed, func(i, j int) bool {
+		a, _ := strconv.Atoi(completed[i].Number.String())
+		b, _ := strconv.Atoi(completed[j].Number.String())
+		return a < b
+	})
+	return completed
+}
+
+// renderChangelog renders issues as Markdown grouped into sections. The
+// release format omits the heading and relies on GitHub linking #123.
+func renderChangelog(issues []issue.Issue, cfg config.Config, opts ChangelogOptions, heading string) string {
+	sections := cfg.Changelog.Sections
+	if len(sections) == 0 {
+		sections = defaultChangelogSections
+	}
+	grouped := map[string][]issue.Issue{}
+	for _, iss := range issues {
+		title := changelogSection(sections, iss)
+		grouped[title] = append(grouped[title], iss)
+	}
+	var order []string
+	for _, section := range sections {
+		if !slices.Contains(order, section.Title) {
+			order = append(order, section.Title)
+		}
+	}
+	order = append(order, otherChangelogSection)
+
+	release := opts.Format == "release"
+	level := "###"
+	var out strings.Builder
+	if release {
+		level = "##"
+	} else {
+		fmt.Fprintf(&out, "## %s\n", heading)
+	}
+	for _, title := range order {
+		items := grouped[title]
+		if len(items) == 0 {
+			continue
+		}
+		if out.Len() > 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "%s %s\n\n", level, title)
+		for _, iss := range items {
+			ref := "#" + iss.Number.String()
+			if !release {
+				ref = fmt.Sprintf("[%s](https://github.com/%s/issues/%s)", ref, repoSlug(cfg), iss.Number)
+			}
+			line := fmt.Sprintf("* %s (%s)", strings.TrimSpace(iss.Title), ref)
+			if iss.Author != "" {
+				line += " by @" + iss.Author
+			}
+			out.WriteString(line + "\n")
+		}
+	}
+	return out.String()
+}
+
+// Changelog prints release notes for the issues closed as completed in a
+// milestone or time range, grouped by label or issue type.
+func (a *App) Changelog(ctx context.Context, opts ChangelogOptions) error {
+	p := paths.New(a.Root)
+	cfg, err := loadConfig(p.ConfigPath)
+	if err != nil {
+		return err
+	}
+	now := a.Now()
+	var since, until time.Time
+	if opts.Since != "" {
+		if since, err = parseSince(opts.Since, now); err != nil {
+			return err
+		}
+	}
+	if opts.Until != "" {
+		if until, err = parseSince(opts.Until, now); err != nil {
+			return err
+		}
+		// A date includes the whole day
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(opts.Until)); err == nil {
+			until = until.AddDate(0, 0, 1)
+		}
+	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+
+	issues, err := loadLocalIssues(p)
+	if err != nil {
+		return err
+	}
+	completed := completedIssues(issues, cfg, opts, since, until)
+	if len(completed) == 0 {
+		fmt.Fprintf(a.Err, "%s\n", a.Theme.MutedText("No issues closed as completed match; pull --all to sync closed issues"))
+		return nil
+	}
+
+	heading := strings.TrimSpace(opts.Title)
+	if heading == "" {
+		heading = opts.Milestone
+	}
+	if heading == "" {
+		heading = "Unreleased"
+	}
+	fmt.Fprint(a.Out, renderChangelog(completed, cfg, opts, heading))
+	return nil
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestChangelog(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	cfg := config.Default("owner", "repo")
+	cfg.Changelog.Sections = []config.ChangelogSection{
+		{Title: "Fixes", Labels: []string{"bug"}},
+		{Title: "Features", Labels: []string{"enhancement"}, Types: []string{"Feature"}},
+	}
+	cfg.Changelog.Exclude = []string{"internal"}
+	if err := config.Save(p.ConfigPath, cfg); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	completed, notPlanned := "completed", "not_planned"
+	day := func(d int) *time.Time {
+		ts := time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC)
+		return &ts
+	}
+	closed := []issue.Issue{
+		{Number: "12", Title: "Crash on startup", Labels: []string{"bug"}, StateReason: &completed, Author: "alice", ClosedAt: day(3)},
+		{Number: "7", Title: "Dark mode", IssueType: "Feature", StateReason: &completed, Author: "bob", ClosedAt: day(5)},
+		{Number: "9", Title: "Faster sync", StateReason: &completed, Milestone: "v1.0", ClosedAt: day(5)},
+		{Number: "10", Title: "Won't do", Labels: []string{"enhancement"}, StateReason: &notPlanned, ClosedAt: day(5)},
+		{Number: "11", Title: "Refactor CI", Labels: []string{"bug", "internal"}, StateReason: &completed, ClosedAt: day(5)},
+		{Number: "3", Title: "Old bug", Labels: []string{"bug"}, StateReason: &completed, ClosedAt: day(1)},
+	}
+	for _, iss := range closed {
+		iss.State = "closed"
+		if err := issue.WriteFile(issue.PathFor(p.ClosedDir, iss.Number, iss.Title), iss); err != nil {
+			t.Fatalf("write issue: %v", err)
+		}
+	}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "20-open.md"), issue.Issue{Number: "20", Title: "Open", Labels: []string{"bug"}, State: "open"}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	application := New(root, nil, &out, &errOut)
+	application.Now = func() time.Time { return time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC) }
+
+	if err := application.Changelog(context.Background(), ChangelogOptions{Since: "2026-10-02", Until: "2026-10-05", Title: "1.0.0"}); err != nil {
+		t.Fatalf("changelog: %v", err)
+	}
+	want := `## 1.0.0
+
+### Fixes
+
+* Crash on startup ([#12](https://github.com/owner/repo/issues/12)) by @alice
+
+### Features
+
+* Dark mode ([#7](https://github.com/owner/repo/issues/7)) by @bob
+
+### Other
+
+* Faster sync ([#9](https://github.com/owner/repo/issues/9))
+`
+	if out.String() != want {
+		t.Fatalf("unexpected changelog:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := application.Changelog(context.Background(), ChangelogOptions{Milestone: "V1.0", Format: "release"}); err != nil {
+		t.Fatalf("changelog: %v", err)
+	}
+	if out.String() != "## Other\n\n* Faster sync (#9)\n" {
+		t.Fatalf("unexpected release notes:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := application.Changelog(context.Background(), ChangelogOptions{Milestone: "v2.0"}); err != nil {
+		t.Fatalf("changelog: %v", err)
+	}
+	if out.Len() != 0 || errOut.Len() == 0 {
+		t.Fatalf("expected only a note on stderr, got %q / %q", out.String(), errOut.String())
+	}
+}
@@ -0,0 +1,286 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// codeRefChange is a file whose local issue references were rewritten.
+type codeRefChange struct {
+	Path  string
+	Count int
+}
+
+// rewriteRefGlobs returns the globs of files push rewrites local references
+// in, and whether rewriting is enabled at all.
+func rewriteRefGlobs(cfg config.Config, opts PushOptions) ([]string, bool) {
+	if len(cfg.Sync.RewriteRefs) > 0 {
+		return cfg.Sync.RewriteRefs, true
+	}
+	return nil, opts.RewriteRefs
+}
+
+// replaceLocalRefs replaces #T references with the numbers in mapping and
+// returns how many were replaced.
+func replaceLocalRefs(text string, mapping map[string]string) (string, int) {
+	count := 0
+	text = localRefPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if real, ok := mapping[strings.TrimPrefix(match, "#")]; ok {
+			count++
+			return "#" + real
+		}
+		return match
+	})
+	return text, count
+}
+
+// globRegexp translates a glob to a regular expression. "*" and "?" stop at
+// slashes, "**" does not. Like in .gitignore, a glob without a slash matches
+// the file name in any directory.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimPrefix(filepath.ToSlash(glob), "/")
+	if !strings.Contains(glob, "/") {
+		glob = "**/" + glob
+	}
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			expr.WriteString("[^/]*")
+		case glob[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// workingTreeFiles lists the files of the git working tree that are not
+// ignored, relative to the top level directory it also returns.
+func (a *App) workingTreeFiles(ctx context.Context) (string, []string, error) {
+	top, err := a.workingTreeTop(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	out, err := a.Runner.Run(ctx, "git", "-C", top, "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+	if err != nil {
+		return "", nil, err
+	}
+	var files []string
+	seen := map[string]struct{}{}
+	for _, name := range strings.Split(out, "\x00") {
+		if name == "" {
+			continue
+		}
+		// Unmerged files are listed once per stage
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		files = append(files, name)
+	}
+	return top, files, nil
+}
+
+// workingTreeTop returns the top level directory of the git working tree.
+func (a *App) workingTreeTop(ctx context.Context) (string, error) {
+	out, err := a.Runner.Run(ctx, "git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// globMatcher returns a function matching working tree paths against globs.
+// Without globs every path matches.
+func globMatcher(globs []string) (func(string) bool, error) {
+	var patterns []*regexp.Regexp
+	for _, glob := range globs {
+		re, err := globRegexp(glob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return func(name string) bool {
+		return len(patterns) == 0 || matchesAny(patterns, name)
+	}, nil
+}
+
+// textFile reads a regular, non-binary file of the working tree. The boolean
+// is false for anything else.
+func textFile(path string) ([]byte, os.FileMode, bool, error) {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		// Deleted in the working tree, or a symlink
+		return nil, 0, false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		// Binary file
+		return nil, 0, false, nil
+	}
+	return data, info.Mode().Perm(), true, nil
+}
+
+// isIssuesPath reports whether path lies in the issues directory.
+func isIssuesPath(p paths.Paths, path string) bool {
+	issuesDir, err := filepath.Abs(p.IssuesDir)
+	if err != nil {
+		return false
+	}
+	return path == issuesDir || strings.HasPrefix(path, issuesDir+string(filepath.Separator))
+}
+
+// rewriteCodeRefs rewrites local issue references like TODO(#T1a2b) in the
+// files of the git working tree accepted by match. The issue files
+// themselves are left to applyMapping.
+func (a *App) rewriteCodeRefs(ctx context.Context, p paths.Paths, mapping map[string]string, match func(string) bool) ([]codeRefChange, error) {
+	if len(mapping) == 0 {
+		return nil, nil
+	}
+	top, files, err := a.workingTreeFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []codeRefChange
+	for _, name := range files {
+		if !match(name) {
+			continue
+		}
+		path := filepath.Join(top, filepath.FromSlash(name))
+		if isIssuesPath(p, path) {
+			continue
+		}
+		data, perm, ok, err := textFile(path)
+		if err != nil {
+			return changes, err
+		}
+		if !ok {
+			continue
+		}
+		updated, count := replaceLocalRefs(string(data), mapping)
+		if count == 0 {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(updated), perm); err != nil {
+			return changes, err
+		}
+		changes = append(changes, codeRefChange{Path: path, Count: count})
+	}
+	return changes, nil
+}
+
+// loadCodeRefs reads the working tree files todo-scan inserted local
+// references into. Push rewrites them even when rewriting is not enabled.
+func loadCodeRefs(p paths.Paths) ([]string, error) {
+	var names []string
+	data, err := os.ReadFile(p.CodeRefsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", paths.CodeRefsFileName, err)
+	}
+	return names, nil
+}
+
+func saveCodeRefs(p paths.Paths, names []string) error {
+	if len(names) == 0 {
+		if err := os.Remove(p.CodeRefsPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	sort.Strings(names)
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(p.CodeRefsPath, data, 0o644)
+}
+
+// pushCodeRefs rewrites local references in source files after a push: in
+// the files matching the configured globs if enabled and the push created
+// issues (mapping), and otherwise in the files todo-scan inserted references
+// into. The whole ID map is used so references missed by earlier pushes are
+// caught too.
+func (a *App) pushCodeRefs(ctx context.Context, p paths.Paths, cfg config.Config, opts PushOptions, mapping map[string]string) ([]codeRefChange, error) {
+	pending, err := loadCodeRefs(p)
+	if err != nil {
+		return nil, err
+	}
+	globs, enabled := rewriteRefGlobs(cfg, opts)
+	// Pushes that create nothing do not scan the working tree
+	enabled = enabled && len(mapping) > 0
+	if !enabled && len(pending) == 0 {
+		return nil, nil
+	}
+	match, err := globMatcher(globs)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		match = func(name string) bool { return slices.Contains(pending, name) }
+	}
+	ids, err := loadIDMap(p)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := a.rewriteCodeRefs(ctx, p, ids, match)
+	if err != nil || len(pending) == 0 {
+		return changes, err
+	}
+
+	// Files stay pending while they reference issues that were not pushed
+	top, err := a.workingTreeTop(ctx)
+	if err != nil {
+		return changes, err
+	}
+	var remaining []string
+	for _, name := range pending {
+		data, _, ok, err := textFile(filepath.Join(top, filepath.FromSlash(name)))
+		if err == nil && ok && localRefPattern.Match(data) {
+			remaining = append(remaining, name)
+		}
+	}
+	return changes, saveCodeRefs(p, remaining)
+}
+
+func matchesAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// lsFilesRunner fakes git for a working tree rooted at top.
+type lsFilesRunner struct {
+	top   string
+	head  string
+	files []string
+}
+
+func (r *lsFilesRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	switch {
+	case joined == "rev-parse --show-toplevel":
+		return r.top + "\n", nil
+	case strings.HasSuffix(joined, "ls-files -z --cached --others --exclude-standard"):
+		return strings.Join(r.files, "\x00") + "\x00", nil
+	case strings.HasSuffix(joined, "rev-parse HEAD") && r.head != "":
+		return r.head + "\n", nil
+	}
+	return "", errors.New("unexpected call: " + name + " " + joined)
+}
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		glob, name string
+		want       bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/app/app.go", true},
+		{"*.go", "main.go.orig", false},
+		{"docs/**", "docs/guide/intro.md", true},
+		{"docs/*.md", "docs/guide/intro.md", false},
+		{"internal/**/*.go", "internal/app.go", true},
+		{"/README.md", "README.md", true},
+	}
+	for _, tt := range tests {
+		re, err := globRegexp(tt.glob)
+		if err != nil {
+			t.Fatalf("globRegexp(%q): %v", tt.glob, err)
+		}
+		if got := re.MatchString(tt.name); got != tt.want {
+			t.Errorf("glob %q on %q = %v, want %v", tt.glob, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteCodeRefs(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	files := map[string]string{
+		"main.go":            "// TODO(#T1abc): handle errors\n// See #T9zzz and #T1abcdef.\n",
+		"docs/notes.md":      "Tracked in #T1abc.\n",
+		"data.bin":           "#T1abc\x00",
+		".issues/open/T1.md": "#T1abc\n",
+		"scripts/build.sh":   "# #T1abc\n",
+	}
+	var names []string
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		names = append(names, name)
+	}
+	application := New(root, &lsFilesRunner{top: root, files: names}, nil, nil)
+	mapping := map[string]string{"T1abc": "42"}
+
+	match, err := globMatcher([]string{"*.go", "docs/**", "*.bin", ".issues/**"})
+	if err != nil {
+		t.Fatalf("globs: %v", err)
+	}
+	changes, err := application.rewriteCodeRefs(context.Background(), p, mapping, match)
+	if err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changed files, got %+v", changes)
+	}
+	read := func(name string) string {
+		data, _ := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		return string(data)
+	}
+	if got := read("main.go"); got != "// TODO(#42): handle errors\n// See #T9zzz and #T1abcdef.\n" {
+		t.Fatalf("unexpected main.go:\n%s", got)
+	}
+	if got := read("docs/notes.md"); got != "Tracked in #42.\n" {
+		t.Fatalf("unexpected notes:\n%s", got)
+	}
+	for _, name := range []string{"data.bin", ".issues/open/T1.md", "scripts/build.sh"} {
+		if read(name) != files[name] {
+			t.Fatalf("expected %s to be left alone, got %q", name, read(name))
+		}
+	}
+}
+
+func TestPushCodeRefsSkipsScanWithoutNewIssues(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := recordIDMapping(p, map[string]string{"T1abc": "42"}); err != nil {
+		t.Fatalf("id map: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte("// TODO(#T1abc)\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// Scanning the working tree would fail on the git calls
+	application := New(root, offlineRunner{}, nil, nil)
+	opts := PushOptions{RewriteRefs: true}
+
+	changes, err := application.pushCodeRefs(context.Background(), p, config.Default("owner", "repo"), opts, nil)
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("expected no scan, got %+v, %v", changes, err)
+	}
+
+	application.Runner = &lsFilesRunner{top: root, files: []string{"main.go"}}
+	changes, err = application.pushCodeRefs(context.Background(), p, config.Default("owner", "repo"), opts, map[string]string{"T1abc": "42"})
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("expected main.go to be rewritten, got %+v, %v", changes, err)
+	}
+}
@@ -3,14 +3,17 @@ package app
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
 	"github.com/google/shlex"
 	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
 	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
@@ -26,6 +29,13 @@ func (a *App) Status(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
 	t := a.Theme
 
 	fmt.Fprintf(a.Out, "%s %s\n", t.MutedText("Repository:"), t.AccentText(cfg.Repository.Owner+"/"+cfg.Repository.Repo))
@@ -156,6 +166,13 @@ func (a *App) List(ctx context.Context, opts ListOptions) error {
 	if _, err := loadConfig(p.ConfigPath); err != nil {
 		return err
 	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
 	t := a.Theme
 
 	// Load label colors for display
@@ -265,34 +282,7 @@ func (a *App) List(ctx context.Context, opts ListOptions) error {
 
 		// Apply search query filters
 		if searchQuery != nil {
-			var syncedAt, createdAt, updatedAt *int64
-			if item.Issue.SyncedAt != nil {
-				ts := item.Issue.SyncedAt.Unix()
-				syncedAt = &ts
-			}
-			if item.Issue.CreatedAt != nil {
-				ts := item.Issue.CreatedAt.Unix()
-				createdAt = &ts
-			}
-			if item.Issue.UpdatedAt != nil {
-				ts := item.Issue.UpdatedAt.Unix()
-				updatedAt = &ts
-			}
-			issueData := search.IssueData{
-				Number:    item.Issue.Number,
-				Title:     item.Issue.Title,
-				Body:      item.Issue.Body,
-				State:     item.State,
-				Labels:    item.Issue.Labels,
-				Assignees: item.Issue.Assignees,
-				Author:    item.Issue.Author,
-				Milestone: item.Issue.Milestone,
-				IssueType: item.Issue.IssueType,
-				Projects:  item.Issue.Projects,
-				SyncedAt:  syncedAt,
-				CreatedAt: createdAt,
-				UpdatedAt: updatedAt,
-			}
+			issueData := searchData(item)
 			// Skip state check in Match since we already handled it above
 			queryForMatch := *searchQuery
 			queryForMatch.State = ""
@@ -464,10 +454,26 @@ func (a *App) NewIssue(ctx context.Context, title string, opts NewOptions) error
 		return err
 	}
 
+	if opts.ListTemplates {
+		return a.listTemplates()
+	}
 	if strings.TrimSpace(title) == "" && !opts.Edit {
 		return fmt.Errorf("title is required (provide a title or use --edit)")
 	}
 
+	base := issue.Issue{
+		Title:  strings.TrimSpace(title),
+		Labels: opts.Labels,
+		State:  "open",
+	}
+	if opts.Template != "" {
+		tmpl, err := a.findTemplate(opts.Template)
+		if err != nil {
+			return err
+		}
+		base = applyTemplate(base, tmpl)
+	}
+
 	// Acquire lock
 	lck, err := lock.Acquire(p.SyncDir, lock.DefaultTimeout)
 	if err != nil {
@@ -484,19 +490,14 @@ func (a *App) NewIssue(ctx context.Context, title string, opts NewOptions) error
 	localNumber := issue.IssueNumber(fmt.Sprintf("T%s", id))
 	var newIssue issue.Issue
 	if strings.TrimSpace(title) == "" && opts.Edit {
-		edited, err := issueFromEditor(ctx, localNumber, opts.Labels)
+		edited, err := issueFromEditor(ctx, localNumber, base)
 		if err != nil {
 			return err
 		}
 		newIssue = edited
 	} else {
-		newIssue = issue.Issue{
-			Number: localNumber,
-			Title:  strings.TrimSpace(title),
-			Labels: opts.Labels,
-			State:  "open",
-			Body:   "",
-		}
+		newIssue = base
+		newIssue.Number = localNumber
 	}
 	newIssue.Number = localNumber
 	if strings.TrimSpace(newIssue.Title) == "" {
@@ -524,7 +525,9 @@ func (a *App) NewIssue(ctx context.Context, title string, opts NewOptions) error
 	return nil
 }
 
-func issueFromEditor(ctx context.Context, number issue.IssueNumber, labels []string) (issue.Issue, error) {
+// issueFromEditor lets the user fill in a new issue in their editor, starting
+// from base.
+func issueFromEditor(ctx context.Context, number issue.IssueNumber, base issue.Issue) (issue.Issue, error) {
 	tempFile, err := os.CreateTemp("", "gh-issue-sync-issue-*.md")
 	if err != nil {
 		return issue.Issue{}, err
@@ -535,13 +538,8 @@ func issueFromEditor(ctx context.Context, number issue.IssueNumber, labels []str
 	}
 	defer os.Remove(tempPath)
 
-	template := issue.Issue{
-		Number: number,
-		Title:  "",
-		Labels: labels,
-		State:  "open",
-		Body:   "",
-	}
+	template := base
+	template.Number = number
 	if err := issue.WriteFile(tempPath, template); err != nil {
 		return issue.Issue{}, err
 	}
@@ -697,6 +695,13 @@ func (a *App) Edit(ctx context.Context, number string) error {
 func (a *App) View(ctx context.Context, ref string, opts ViewOptions) error {
 	p := paths.New(a.Root)
 
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+
 	file, err := findIssueByRef(a.Root, p, ref)
 	if err != nil {
 		return err
@@ -751,6 +756,14 @@ func (a *App) View(ctx context.Context, ref string, opts ViewOptions) error {
 	if len(iss.Projects) > 0 {
 		fmt.Fprintf(a.Out, "%s\t%s\n", t.MutedText("projects:"), strings.Join(iss.Projects, ", "))
 	}
+	for _, project := range slices.Sorted(maps.Keys(iss.ProjectFields)) {
+		fields := iss.ProjectFields[project]
+		for _, name := range slices.Sorted(maps.Keys(fields)) {
+			if fields[name] != "" {
+				fmt.Fprintf(a.Out, "%s\t%s\n", t.MutedText(project+" / "+name+":"), fields[name])
+			}
+		}
+	}
 
 	// Parent
 	if iss.Parent != nil {
@@ -775,6 +788,15 @@ func (a *App) View(ctx context.Context, ref string, opts ViewOptions) error {
 		fmt.Fprintf(a.Out, "%s\t%s\n", t.MutedText("blocks:"), strings.Join(refs, ", "))
 	}
 
+	// Linked pull requests, one per line
+	for i, pr := range iss.LinkedPRs {
+		label := ""
+		if i == 0 {
+			label = "linked_prs:"
+		}
+		fmt.Fprintf(a.Out, "%s\t%s\n", t.MutedText(label), a.formatLinkedPR(pr))
+	}
+
 	// Synced at with relative time
 	if iss.SyncedAt != nil {
 		relTime := formatRelativeTime(a.Now(), *iss.SyncedAt)
@@ -784,10 +806,12 @@ func (a *App) View(ctx context.Context, ref string, opts ViewOptions) error {
 	// Separator and body
 	fmt.Fprintln(a.Out, "--")
 	if strings.TrimSpace(iss.Body) != "" {
-		rendered, err := renderMarkdown(iss.Body)
+		// Show downloaded attachments by their local path
+		body := a.displayAttachments(p, iss.Body)
+		rendered, err := a.renderMarkdown(body)
 		if err != nil {
 			// Fall back to plain text on error
-			fmt.Fprintln(a.Out, iss.Body)
+			fmt.Fprintln(a.Out, body)
 		} else {
 			fmt.Fprint(a.Out, rendered)
 		}
@@ -797,7 +821,7 @@ func (a *App) View(ctx context.Context, ref string, opts ViewOptions) error {
 	if comment, found := findPendingCommentForIssue(p, iss.Number, file.State); found {
 		fmt.Fprintln(a.Out)
 		fmt.Fprintf(a.Out, "%s\n", t.WarningText("--- Pending Comment ---"))
-		rendered, err := renderMarkdown(comment.Body)
+		rendered, err := a.renderMarkdown(comment.Body)
 		if err != nil {
 			fmt.Fprintln(a.Out, comment.Body)
 		} else {
@@ -809,10 +833,17 @@ func (a *App) View(ctx context.Context, ref string, opts ViewOptions) error {
 }
 
 // renderMarkdown renders markdown text for terminal output using glamour
-func renderMarkdown(text string) (string, error) {
+func (a *App) renderMarkdown(text string) (string, error) {
+	style, width := styles.AutoStyle, 80
+	if a.markdownStyle != "" {
+		style = a.markdownStyle
+	}
+	if a.markdownWidth > 0 {
+		width = a.markdownWidth
+	}
 	renderer, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(80),
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(width),
 	)
 	if err != nil {
 		return "", err
@@ -826,6 +857,13 @@ func (a *App) DiffAll(ctx context.Context, opts DiffOptions) error {
 	if err != nil {
 		return err
 	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
 	t := a.Theme
 
 	// Load label cache for colored output
@@ -844,8 +882,12 @@ func (a *App) DiffAll(ctx context.Context, opts DiffOptions) error {
 	}
 
 	var client *ghcli.Client
+	var att *attachments
 	if opts.Remote {
 		client = ghcli.NewClient(a.Runner, repoSlug(cfg))
+		if att, err = loadAttachments(p, cfg); err != nil {
+			return err
+		}
 	}
 
 	count := 0
@@ -862,6 +904,7 @@ func (a *App) DiffAll(ctx context.Context, opts DiffOptions) error {
 				fmt.Fprintf(a.Out, "%s %s: %v\n", t.ErrorText("!"), local.Number, err)
 				continue
 			}
+			remote.Body = att.localBody(remote.Number.String(), remote.Body)
 			base = issue.Normalize(remote)
 		} else {
 			original, hasOriginal := readOriginalIssue(p, local.Number.String())
@@ -922,6 +965,13 @@ func (a *App) Diff(ctx context.Context, number string, opts DiffOptions) error {
 	if err != nil {
 		return err
 	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
 	t := a.Theme
 
 	// Load label cache for colored output
@@ -946,8 +996,28 @@ func (a *App) Diff(ctx context.Context, number string, opts DiffOptions) error {
 		if err != nil {
 			return err
 		}
+		att, err := loadAttachments(p, cfg)
+		if err != nil {
+			return err
+		}
+		remote.Body = att.localBody(remote.Number.String(), remote.Body)
 		base = remote
 		baseLabel = "remote"
+	} else if opts.Since != "" {
+		since, err := parseSince(opts.Since, a.Now())
+		if err != nil {
+			return err
+		}
+		versions, err := issueVersions(p, local.Number.String())
+		if err != nil {
+			return err
+		}
+		version, ok := versionAt(versions, since)
+		if !ok {
+			return fmt.Errorf("no synced version found for issue %s", local.Number)
+		}
+		base = version
+		baseLabel = "version from " + since.Local().Format("2006-01-02 15:04")
 	} else {
 		original, hasOriginal := readOriginalIssue(p, local.Number.String())
 		if !hasOriginal {
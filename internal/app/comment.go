@@ -1,12 +1,15 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
 	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
 )
 
@@ -132,3 +135,58 @@ func loadAllPendingComments(p paths.Paths) map[string]PendingComment {
 func deletePendingComment(comment PendingComment) error {
 	return os.Remove(comment.Path)
 }
+
+// pendingCommentPath returns the pending comment file of an issue: the
+// existing one if there is one, otherwise "NUMBER.comment.md" next to it.
+func pendingCommentPath(p paths.Paths, item IssueFile) string {
+	if existing, found := findPendingCommentForIssue(p, item.Issue.Number, item.State); found {
+		return existing.Path
+	}
+	return filepath.Join(filepath.Dir(item.Path), item.Issue.Number.String()+".comment.md")
+}
+
+// Comment queues a comment to be posted on the next push. The body is
+// appended to an already pending comment; without a body the pending
+// comment is opened in the editor and removed again if left empty.
+func (a *App) Comment(ctx context.Context, ref string, body string) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+	file, err := findIssueByRef(a.Root, p, ref)
+	if err != nil {
+		return err
+	}
+	path := pendingCommentPath(p, file)
+
+	if strings.TrimSpace(body) == "" {
+		if err := openEditor(ctx, path); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		if strings.TrimSpace(string(content)) == "" {
+			fmt.Fprintf(a.Out, "%s\n", a.Theme.MutedText("Discarded empty comment"))
+			return os.Remove(path)
+		}
+	} else {
+		// Acquire lock
+		lck, err := lock.Acquire(p.SyncDir, lock.DefaultTimeout)
+		if err != nil {
+			return err
+		}
+		defer lck.Release()
+
+		content := strings.TrimSpace(body) + "\n"
+		if existing, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(existing)) != "" {
+			content = strings.TrimSpace(string(existing)) + "\n\n" + content
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(a.Out, "Queued comment on %s %s\n", formatIssueRef(file.Issue.Number), a.Theme.MutedText("("+relPath(a.Root, path)+")"))
+	return nil
+}
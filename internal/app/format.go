@@ -65,6 +65,10 @@ func (a *App) formatChangeLines(oldIssue, newIssue issue.Issue, labelColors map[
 	if !stringSlicesEqual(oldIssue.Projects, newIssue.Projects) {
 		lines = append(lines, t.FormatChange("projects", formatStringList(oldIssue.Projects), formatStringList(newIssue.Projects)))
 	}
+	for _, change := range diffProjectFields(oldIssue.ProjectFields, newIssue.ProjectFields) {
+		field := change.Project + "." + change.Field
+		lines = append(lines, t.FormatChange(field, formatOptionalString(oldIssue.ProjectFields[change.Project][change.Field]), formatOptionalString(change.Value)))
+	}
 	if oldIssue.State != newIssue.State {
 		lines = append(lines, t.FormatChange("state", formatOptionalString(oldIssue.State), formatOptionalString(newIssue.State)))
 	}
@@ -281,3 +285,49 @@ func (a *App) formatConflictLines(local, remote issue.Issue, fields []string, la
 	}
 	return lines
 }
+
+// formatLinkedPR formats a linked pull request like
+// "#12 Fix crash (merged, closes, branch fix-crash)".
+func (a *App) formatLinkedPR(pr issue.LinkedPR) string {
+	t := a.Theme
+	var state string
+	switch {
+	case pr.Merged:
+		state = t.AccentText("merged")
+	case pr.State == "open":
+		state = t.SuccessText("open")
+	default:
+		state = t.ErrorText(pr.State)
+	}
+	details := []string{state}
+	if pr.Closes {
+		details = append(details, "closes")
+	}
+	if pr.Branch != "" {
+		details = append(details, "branch "+pr.Branch)
+	}
+	return pr.Ref() + " " + pr.Title + " " + t.MutedText("(") + strings.Join(details, t.MutedText(", ")) + t.MutedText(")")
+}
+
+// formatLinkedPRRefs formats linked pull requests as a list of references.
+func formatLinkedPRRefs(prs []issue.LinkedPR) string {
+	if len(prs) == 0 {
+		return "none"
+	}
+	refs := make([]string, len(prs))
+	for i, pr := range prs {
+		refs[i] = pr.Ref()
+		if pr.Merged {
+			refs[i] += " (merged)"
+		}
+	}
+	return strings.Join(refs, ", ")
+}
+
+// formatIssueRef formats an issue number as "#42", or "T1" for local issues.
+func formatIssueRef(number issue.IssueNumber) string {
+	if number.IsLocal() {
+		return number.String()
+	}
+	return "#" + number.String()
+}
@@ -0,0 +1,227 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// closingRefPattern matches GitHub's closing keywords followed by an issue
+// reference, like "Fixes #123" or "closes: #T1a2b".
+var closingRefPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+#(\d+|T[a-zA-Z0-9]+)\b`)
+
+// postCommitHook is installed by git-scan --install-hook.
+const postCommitHook = `#!/bin/sh
+# Installed by gh-issue-sync: close issues referenced by "Fixes #N" in commits.
+gh-issue-sync git-scan --max-count 1 HEAD || true
+`
+
+type gitCommit struct {
+	SHA     string
+	Subject string
+	Message string
+}
+
+// closingRefs returns the issues a commit message closes, in order.
+func closingRefs(message string) []string {
+	var refs []string
+	for _, match := range closingRefPattern.FindAllStringSubmatch(message, -1) {
+		if !slices.Contains(refs, match[1]) {
+			refs = append(refs, match[1])
+		}
+	}
+	return refs
+}
+
+// gitScanState maps the commits git-scan handled to the issue references it
+// closed in them. Pending comments naming a commit are deleted once pushed,
+// so this is what keeps a later scan from queueing them again.
+type gitScanState map[string][]string
+
+func loadGitScanState(p paths.Paths) (gitScanState, error) {
+	state := gitScanState{}
+	data, err := os.ReadFile(p.GitScanPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", paths.GitScanFileName, err)
+	}
+	return state, nil
+}
+
+func saveGitScanState(p paths.Paths, state gitScanState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(p.GitScanPath, data, 0o644)
+}
+
+// gitLog returns the commits in rev, oldest first.
+func (a *App) gitLog(ctx context.Context, rev string, maxCount int) ([]gitCommit, error) {
+	args := []string{"log", "--reverse", "--format=%H%x00%s%x00%B%x1e"}
+	if maxCount > 0 {
+		args = append(args, "--max-count", strconv.Itoa(maxCount))
+	}
+	args = append(args, rev, "--")
+	out, err := a.Runner.Run(ctx, "git", args...)
+	if err != nil {
+		return nil, err
+	}
+	var commits []gitCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		commits = append(commits, gitCommit{SHA: fields[0], Subject: fields[1], Message: fields[2]})
+	}
+	return commits, nil
+}
+
+// GitScan closes issues referenced with closing keywords in the commits of
+// rev (default: commits not pushed upstream yet) and queues a comment naming
+// the commit on each. References handled by an earlier scan are recorded in
+// the sync directory and skipped, so scanning the same range twice is
+// harmless, even after the comments were pushed.
+func (a *App) GitScan(ctx context.Context, rev string, opts GitScanOptions) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+	if opts.InstallHook {
+		return a.installPostCommitHook(ctx)
+	}
+	if strings.TrimSpace(rev) == "" {
+		rev = "@{upstream}..HEAD"
+	}
+
+	// Acquire lock
+	lck, err := lock.Acquire(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+	t := a.Theme
+
+	commits, err := a.gitLog(ctx, rev, opts.MaxCount)
+	if err != nil {
+		return err
+	}
+	scanned, err := loadGitScanState(p)
+	if err != nil {
+		return err
+	}
+
+	closed, commented := 0, 0
+	for _, commit := range commits {
+		for _, ref := range closingRefs(commit.Message) {
+			if slices.Contains(scanned[commit.SHA], ref) {
+				continue
+			}
+			file, err := findIssueByNumber(p, ref)
+			if err != nil {
+				fmt.Fprintf(a.Err, "%s %s references unknown issue #%s\n", t.WarningText("Warning:"), shortCommit(commit.SHA), ref)
+				continue
+			}
+			commentPath := pendingCommentPath(p, file)
+			pending, _ := os.ReadFile(commentPath)
+			if strings.Contains(string(pending), commit.SHA) {
+				continue
+			}
+
+			if file.State != "closed" {
+				reason := "completed"
+				file.Issue.State = "closed"
+				file.Issue.StateReason = &reason
+				newPath := issue.PathFor(p.ClosedDir, file.Issue.Number, file.Issue.Title)
+				if err := os.Rename(file.Path, newPath); err != nil {
+					return err
+				}
+				file.Path = newPath
+				file.State = "closed"
+				if err := issue.WriteFile(file.Path, file.Issue); err != nil {
+					return err
+				}
+				// Keep the pending comment next to the issue
+				if len(pending) > 0 {
+					moved := filepath.Join(p.ClosedDir, filepath.Base(commentPath))
+					if err := os.Rename(commentPath, moved); err != nil {
+						return err
+					}
+					commentPath = moved
+				} else {
+					commentPath = pendingCommentPath(p, file)
+				}
+				closed++
+			}
+
+			body := fmt.Sprintf("Closed by commit %s: %s\n", commit.SHA, commit.Subject)
+			if strings.TrimSpace(string(pending)) != "" {
+				body = strings.TrimSpace(string(pending)) + "\n\n" + body
+			}
+			if err := os.WriteFile(commentPath, []byte(body), 0o644); err != nil {
+				return err
+			}
+			scanned[commit.SHA] = append(scanned[commit.SHA], ref)
+			commented++
+			fmt.Fprintf(a.Out, "%s %s\n", t.FormatIssueHeader("M", file.Issue.Number.String(), file.Issue.Title), t.MutedText("("+shortCommit(commit.SHA)+")"))
+		}
+	}
+
+	if commented == 0 {
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText(fmt.Sprintf("No new closing references in %s", pluralize(len(commits), "commit"))))
+		return nil
+	}
+	if err := saveGitScanState(p, scanned); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Out, "%s\n", t.MutedText(fmt.Sprintf("Closed %s and queued %s; push to sync", pluralize(closed, "issue"), pluralize(commented, "comment"))))
+	return nil
+}
+
+// installPostCommitHook installs a post-commit hook running git-scan on each
+// new commit. An existing hook is left alone.
+func (a *App) installPostCommitHook(ctx context.Context) error {
+	out, err := a.Runner.Run(ctx, "git", "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return err
+	}
+	// The path is relative to the working directory git ran in
+	dir, err := filepath.Abs(strings.TrimSpace(out))
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "post-commit")
+	if existing, err := os.ReadFile(path); err == nil {
+		if strings.Contains(string(existing), "gh-issue-sync git-scan") {
+			fmt.Fprintf(a.Out, "%s\n", a.Theme.MutedText("Hook already installed: "+relPath(a.Root, path)))
+			return nil
+		}
+		return fmt.Errorf("%s already exists; add this line to it:\n  gh-issue-sync git-scan --max-count 1 HEAD", relPath(a.Root, path))
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(postCommitHook), 0o755); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Out, "Installed %s\n", relPath(a.Root, path))
+	return nil
+}
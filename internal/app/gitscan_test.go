@@ -0,0 +1,111 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// logRunner fakes git log with a fixed list of commits.
+type logRunner struct {
+	commits []gitCommit
+}
+
+func (r *logRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	if name != "git" || !strings.HasPrefix(joined, "log --reverse") {
+		return "", errors.New("unexpected call: " + name + " " + joined)
+	}
+	var out strings.Builder
+	for _, commit := range r.commits {
+		out.WriteString(commit.SHA + "\x00" + commit.Subject + "\x00" + commit.Message + "\n\x1e\n")
+	}
+	return out.String(), nil
+}
+
+func TestClosingRefs(t *testing.T) {
+	message := "Fix login\n\nFixes #12, closes: #T1abc and resolves #12.\nSee #99 and prefix#3."
+	got := closingRefs(message)
+	if strings.Join(got, ",") != "12,T1abc" {
+		t.Fatalf("unexpected refs %v", got)
+	}
+}
+
+func TestGitScan(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "12-fix-login.md"), issue.Issue{Number: "12", Title: "Fix login", State: "open"}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "40-dark-mode.md"), issue.Issue{Number: "40", Title: "Dark mode", State: "open"}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	// T1abc was pushed as #40
+	if err := recordIDMapping(p, map[string]string{"T1abc": "40"}); err != nil {
+		t.Fatalf("id map: %v", err)
+	}
+
+	runner := &logRunner{commits: []gitCommit{
+		{SHA: "aaaaaaa1111111", Subject: "Fix login", Message: "Fix login\n\nFixes #12"},
+		{SHA: "bbbbbbb2222222", Subject: "Add dark mode", Message: "Add dark mode\n\nCloses #T1abc, fixes #77"},
+	}}
+	var out, errOut bytes.Buffer
+	application := New(root, runner, &out, &errOut)
+
+	if err := application.GitScan(context.Background(), "", GitScanOptions{}); err != nil {
+		t.Fatalf("git-scan: %v", err)
+	}
+	closed, err := issue.ParseFile(filepath.Join(p.ClosedDir, "12-fix-login.md"))
+	if err != nil {
+		t.Fatalf("expected issue to move to closed: %v", err)
+	}
+	if closed.State != "closed" || closed.StateReason == nil || *closed.StateReason != "completed" {
+		t.Fatalf("expected issue closed as completed, got %+v", closed)
+	}
+	if _, err := os.Stat(filepath.Join(p.ClosedDir, "40-dark-mode.md")); err != nil {
+		t.Fatalf("expected local ID to resolve through the ID map: %v", err)
+	}
+	comment, err := os.ReadFile(filepath.Join(p.ClosedDir, "12.comment.md"))
+	if err != nil || string(comment) != "Closed by commit aaaaaaa1111111: Fix login\n" {
+		t.Fatalf("unexpected pending comment %q, %v", comment, err)
+	}
+	if !strings.Contains(errOut.String(), "unknown issue #77") {
+		t.Fatalf("expected warning about unknown issue, got %q", errOut.String())
+	}
+	if !strings.Contains(stripAnsi(out.String()), "Closed 2 issues and queued 2 comments") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	// Scanning again changes nothing, also after push posted the comments
+	if err := os.Remove(filepath.Join(p.ClosedDir, "40.comment.md")); err != nil {
+		t.Fatalf("remove comment: %v", err)
+	}
+	out.Reset()
+	if err := application.GitScan(context.Background(), "", GitScanOptions{}); err != nil {
+		t.Fatalf("git-scan: %v", err)
+	}
+	if !strings.Contains(stripAnsi(out.String()), "No new closing references in 2 commits") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+	comment, _ = os.ReadFile(filepath.Join(p.ClosedDir, "12.comment.md"))
+	if strings.Count(string(comment), "Closed by commit") != 1 {
+		t.Fatalf("expected comment to be queued once, got %q", comment)
+	}
+	if _, err := os.Stat(filepath.Join(p.ClosedDir, "40.comment.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected posted comment not to be queued again, got %v", err)
+	}
+}
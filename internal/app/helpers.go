@@ -16,6 +16,7 @@ import (
 	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
 	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
 	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+	"github.com/mitsuhiko/gh-issue-sync/internal/search"
 )
 
 // localRefPattern matches local issue references like #T1, #T42, #Tabc123 (T followed by alphanumerics)
@@ -152,6 +153,48 @@ func applyMappingToRefs(refs []issue.IssueRef, mapping map[string]string, change
 	return updated, changed
 }
 
+// withLocalState returns remote carrying the local-only state of local (extra
+// front matter fields and file layout), so rewriting a file from remote data
+// does not drop custom fields or reformat it.
+func withLocalState(remote, local issue.Issue) issue.Issue {
+	remote.KeepLocal(local)
+	return remote
+}
+
+// searchData returns the fields of a local issue that search queries match on.
+func searchData(item IssueFile) search.IssueData {
+	var syncedAt, createdAt, updatedAt *int64
+	if item.Issue.SyncedAt != nil {
+		ts := item.Issue.SyncedAt.Unix()
+		syncedAt = &ts
+	}
+	if item.Issue.CreatedAt != nil {
+		ts := item.Issue.CreatedAt.Unix()
+		createdAt = &ts
+	}
+	if item.Issue.UpdatedAt != nil {
+		ts := item.Issue.UpdatedAt.Unix()
+		updatedAt = &ts
+	}
+	return search.IssueData{
+		Number:    item.Issue.Number,
+		Title:     item.Issue.Title,
+		Body:      item.Issue.Body,
+		State:     item.State,
+		Labels:    item.Issue.Labels,
+		Assignees: item.Issue.Assignees,
+		Author:    item.Issue.Author,
+		Milestone: item.Issue.Milestone,
+		IssueType: item.Issue.IssueType,
+		Projects:  item.Issue.Projects,
+		LinkedPRs: len(item.Issue.LinkedPRs),
+		SyncedAt:  syncedAt,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		Extra:     item.Issue.ExtraValues(),
+	}
+}
+
 func filterIssuesByArgs(root string, issues []IssueFile, args []string) ([]IssueFile, error) {
 	if len(args) == 0 {
 		return issues, nil
@@ -285,3 +328,13 @@ func repoSlug(cfg config.Config) string {
 	}
 	return owner + "/" + repo
 }
+
+// containsIgnoreCase reports whether items contains value, ignoring case.
+func containsIgnoreCase(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/history"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// replaceOriginal writes a new original for an issue, first moving the one it
+// replaces into the issue's history. sync is the kind of sync ("pull" or
+// "push") doing the replacing. Failing to record history is only a warning.
+func (a *App) replaceOriginal(p paths.Paths, next issue.Issue, sync string) error {
+	number := next.Number.String()
+	if previous, ok := readOriginalIssue(p, number); ok && !issue.EqualIgnoringSyncedAt(previous, next) {
+		data, err := os.ReadFile(filepath.Join(p.OriginalsDir, number+".md"))
+		if err == nil {
+			v := history.Version{ReplacedAt: a.Now().UTC(), ReplacedBy: sync}
+			if previous.SyncedAt != nil {
+				v.SyncedAt = previous.SyncedAt.UTC()
+			}
+			err = history.New(p.HistoryDir).Append(number, data, v)
+		}
+		if err != nil {
+			fmt.Fprintf(a.Err, "%s recording history of #%s: %v\n", a.Theme.WarningText("Warning:"), number, err)
+		}
+	}
+	return writeOriginalIssue(p, next)
+}
+
+// issueVersion is a synced version of an issue and how it ended.
+type issueVersion struct {
+	Issue issue.Issue
+	// ReplacedAt and ReplacedBy are unset for the current original.
+	ReplacedAt time.Time
+	ReplacedBy string
+}
+
+// issueVersions returns the synced versions of an issue, oldest first, ending
+// with the current original.
+func issueVersions(p paths.Paths, number string) ([]issueVersion, error) {
+	store := history.New(p.HistoryDir)
+	recorded, err := store.Versions(number)
+	if err != nil {
+		return nil, err
+	}
+	var versions []issueVersion
+	for _, v := range recorded {
+		data, err := store.Load(v.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("loading history of #%s: %w", number, err)
+		}
+		parsed, err := issue.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("loading history of #%s: %w", number, err)
+		}
+		parsed.Number = issue.IssueNumber(number)
+		versions = append(versions, issueVersion{Issue: parsed, ReplacedAt: v.ReplacedAt, ReplacedBy: v.ReplacedBy})
+	}
+	if original, ok := readOriginalIssue(p, number); ok {
+		versions = append(versions, issueVersion{Issue: original})
+	}
+	return versions, nil
+}
+
+// versionAt returns the synced version of an issue that was current at the
+// given time. If the issue was first synced later, the oldest version is
+// returned.
+func versionAt(versions []issueVersion, at time.Time) (issue.Issue, bool) {
+	for _, v := range versions {
+		if v.ReplacedAt.IsZero() || v.ReplacedAt.After(at) {
+			return v.Issue, true
+		}
+	}
+	return issue.Issue{}, false
+}
+
+// parseSince parses a point in time given as a date (2006-01-02), a
+// timestamp (RFC 3339) or an age such as 36h, 7d or 2w.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.Atoi(strings.TrimRight(value, "dw")); err == nil && len(value) > 1 {
+		switch value[len(value)-1] {
+		case 'd':
+			return now.AddDate(0, 0, -n), nil
+		case 'w':
+			return now.AddDate(0, 0, -7*n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use a date like 2006-01-02, a timestamp or an age like 7d)", value)
+}
+
+// Log shows how an issue changed over the syncs recorded in its history,
+// newest first, starting with local changes that have not been pushed.
+func (a *App) Log(ctx context.Context, ref string) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+	t := a.Theme
+
+	labelCache, _ := loadLabelCache(p)
+	labelColors := labelCacheToColorMap(labelCache)
+
+	file, err := findIssueByRef(a.Root, p, ref)
+	if err != nil {
+		return err
+	}
+	number := file.Issue.Number.String()
+	versions, err := issueVersions(p, number)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.Out, t.FormatIssueHeader("M", number, file.Issue.Title))
+	if len(versions) == 0 {
+		fmt.Fprintf(a.Out, "\n  %s\n", t.MutedText("Never synced"))
+		return nil
+	}
+
+	printEntry := func(at time.Time, label string, lines []string) {
+		fmt.Fprintln(a.Out)
+		fmt.Fprintf(a.Out, "  %s %s\n", t.AccentText(label), t.MutedText(formatOptionalTime(a.Now(), at)))
+		if lines != nil && len(lines) == 0 {
+			fmt.Fprintf(a.Out, "    %s\n", t.MutedText("relationships changed"))
+		}
+		for _, line := range lines {
+			fmt.Fprintln(a.Out, line)
+		}
+	}
+
+	current := versions[len(versions)-1].Issue
+	if !issue.EqualIgnoringSyncedAt(current, file.Issue) {
+		fmt.Fprintln(a.Out)
+		fmt.Fprintf(a.Out, "  %s\n", t.WarningText("local changes (not pushed)"))
+		for _, line := range a.formatChangeLines(current, file.Issue, labelColors) {
+			fmt.Fprintln(a.Out, line)
+		}
+	}
+	for i := len(versions) - 1; i > 0; i-- {
+		older := versions[i-1]
+		printEntry(older.ReplacedAt, older.ReplacedBy, a.formatChangeLines(older.Issue, versions[i].Issue, labelColors))
+	}
+	first := time.Time{}
+	if syncedAt := versions[0].Issue.SyncedAt; syncedAt != nil {
+		first = *syncedAt
+	}
+	printEntry(first, "first synced", nil)
+	return nil
+}
+
+// formatOptionalTime formats a time for log output, or "unknown time".
+func formatOptionalTime(now, at time.Time) string {
+	if at.IsZero() {
+		return "unknown time"
+	}
+	return at.Local().Format("2006-01-02 15:04") + " (" + formatRelativeTime(now, at) + ")"
+}
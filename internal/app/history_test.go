@@ -0,0 +1,103 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestIssueHistory(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	var out bytes.Buffer
+	application := New(root, offlineRunner{}, &out, &out)
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	application.Now = func() time.Time { return now }
+
+	// Three pulls over three days; the second one changes nothing
+	v1 := issue.Issue{Number: "4", Title: "Crash", Body: "It crashes.\n", State: "open", SyncedAt: ptrTime(now)}
+	if err := application.replaceOriginal(p, v1, "pull"); err != nil {
+		t.Fatalf("original: %v", err)
+	}
+	now = now.Add(24 * time.Hour)
+	if err := application.replaceOriginal(p, v1, "pull"); err != nil {
+		t.Fatalf("original: %v", err)
+	}
+	now = now.Add(24 * time.Hour)
+	v2 := v1
+	v2.Title = "Crash on start"
+	v2.Labels = []string{"bug"}
+	v2.Body = "It crashes on start.\n"
+	if err := application.replaceOriginal(p, v2, "pull"); err != nil {
+		t.Fatalf("original: %v", err)
+	}
+	local := v2
+	local.State = "closed"
+	if err := issue.WriteFile(filepath.Join(p.ClosedDir, "4-crash-on-start.md"), local); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+
+	versions, err := issueVersions(p, "4")
+	if err != nil {
+		t.Fatalf("versions: %v", err)
+	}
+	if len(versions) != 2 || versions[0].Issue.Title != "Crash" || versions[0].ReplacedBy != "pull" {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+
+	if err := application.Log(context.Background(), "4"); err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	log := stripAnsi(out.String())
+	pulled := "pull " + now.Local().Format("2006-01-02 15:04")
+	first := "first synced " + now.Add(-48*time.Hour).Local().Format("2006-01-02 15:04")
+	for _, want := range []string{"local changes (not pushed)", "state: \"open\" -> \"closed\"", pulled, "labels: + bug", first} {
+		if !strings.Contains(log, want) {
+			t.Fatalf("expected %q in log:\n%s", want, log)
+		}
+	}
+
+	// The first version was current until the last pull
+	out.Reset()
+	if err := application.Diff(context.Background(), "4", DiffOptions{Since: "2025-05-02"}); err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	diff := stripAnsi(out.String())
+	if !strings.Contains(diff, "title:") || !strings.Contains(diff, "on start") {
+		t.Fatalf("expected diff against the first version, got:\n%s", diff)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2025-05-01":           time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		"2025-05-01T08:00:00Z": time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC),
+		"3d":                   now.AddDate(0, 0, -3),
+		"2w":                   now.AddDate(0, 0, -14),
+		"36h":                  now.Add(-36 * time.Hour),
+	}
+	for input, want := range tests {
+		got, err := parseSince(input, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Errorf("expected error for unsupported input")
+	}
+}
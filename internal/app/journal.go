@@ -0,0 +1,249 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/assets"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// Push journal operations
+const (
+	journalCreate  = "create"
+	journalComment = "comment"
+)
+
+// Push journal entry states
+const (
+	// journalPending is recorded right before calling GitHub. If the push
+	// dies in this state the outcome is unknown and has to be looked up.
+	journalPending = "pending"
+	// journalApplied is recorded once GitHub accepted the operation but the
+	// local files have not been updated yet.
+	journalApplied = "applied"
+)
+
+// pushJournal is a write-ahead log of the remote operations push performs
+// that are not safe to repeat (creating issues and posting comments). Entries
+// are removed once the local files reflect the outcome, so a journal left on
+// disk means a push was interrupted.
+type pushJournal struct {
+	path      string
+	StartedAt time.Time      `json:"started_at"`
+	Entries   []journalEntry `json:"entries"`
+}
+
+type journalEntry struct {
+	Op string `json:"op"`
+	// Issue is the local ID for creates and the issue number for comments.
+	Issue string `json:"issue"`
+	// Path is the issue or comment file, relative to the issues directory.
+	// Together with Op it identifies the entry.
+	Path     string `json:"path"`
+	Title    string `json:"title,omitempty"`
+	BodyHash string `json:"body_hash"`
+	Status   string `json:"status"`
+	// Number is the issue created on GitHub.
+	Number string `json:"number,omitempty"`
+}
+
+// loadPushJournal reads the push journal, returning an empty one if there is
+// none.
+func loadPushJournal(p paths.Paths) (*pushJournal, error) {
+	j := &pushJournal{path: p.JournalPath}
+	data, err := os.ReadFile(p.JournalPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return j, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("invalid push journal %s: %w", p.JournalPath, err)
+	}
+	return j, nil
+}
+
+// save writes the journal durably, or removes it once it is empty.
+func (j *pushJournal) save() error {
+	if len(j.Entries) == 0 {
+		err := os.Remove(j.path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	tmp := j.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+// begin records an operation as pending before it is sent to GitHub.
+func (j *pushJournal) begin(entry journalEntry, now time.Time) error {
+	if len(j.Entries) == 0 {
+		j.StartedAt = now
+	}
+	entry.Status = journalPending
+	j.Entries = append(j.Entries, entry)
+	return j.save()
+}
+
+// applied records that GitHub accepted an operation.
+func (j *pushJournal) applied(op, path, number string) error {
+	for i := range j.Entries {
+		if j.Entries[i].Op == op && j.Entries[i].Path == path {
+			j.Entries[i].Status = journalApplied
+			j.Entries[i].Number = number
+		}
+	}
+	return j.save()
+}
+
+// finish drops an operation whose outcome is reflected in the local files.
+func (j *pushJournal) finish(op, path string) error {
+	entries := j.Entries[:0]
+	for _, entry := range j.Entries {
+		if entry.Op != op || entry.Path != path {
+			entries = append(entries, entry)
+		}
+	}
+	j.Entries = entries
+	return j.save()
+}
+
+// journalPath returns path relative to the issues directory, as stored in
+// the journal.
+func journalPath(p paths.Paths, path string) string {
+	if rel, err := filepath.Rel(p.IssuesDir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// journalBodyHash hashes a body as sent to GitHub, ignoring differences
+// GitHub introduces when storing it.
+func journalBodyHash(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	return assets.Hash([]byte(strings.TrimSpace(body)))
+}
+
+// finalizeCreatedIssue gives a local issue the number GitHub assigned to it:
+// the file is renamed and rewritten and the original is recorded.
+func (a *App) finalizeCreatedIssue(p paths.Paths, item *IssueFile, number string) error {
+	item.Issue.Number = issue.IssueNumber(number)
+	item.Issue.SyncedAt = ptrTime(a.Now().UTC())
+	newPath := issue.PathFor(dirForState(p, item.State), item.Issue.Number, item.Issue.Title)
+	if item.Path != newPath {
+		if err := os.Rename(item.Path, newPath); err != nil {
+			return err
+		}
+		item.Path = newPath
+	}
+	if err := issue.WriteFile(item.Path, item.Issue); err != nil {
+		return err
+	}
+	return writeOriginalIssue(p, item.Issue)
+}
+
+// recoverPush completes the operations of an interrupted push. Issues that
+// were created on GitHub get their local files renamed, whether the push
+// recorded the new number or it has to be found by its creation marker or
+// title and body; comments that were posted have their files removed.
+// Operations that never reached GitHub are dropped so this push performs them
+// again. It returns the local IDs of recovered issues mapped to their numbers.
+func (a *App) recoverPush(ctx context.Context, client *ghcli.Client, p paths.Paths, j *pushJournal) (map[string]string, error) {
+	t := a.Theme
+	mapping := map[string]string{}
+	if len(j.Entries) == 0 {
+		return mapping, nil
+	}
+	fmt.Fprintf(a.Out, "%s %s\n", t.WarningText("Resuming interrupted push"),
+		t.MutedText(fmt.Sprintf("(%s from %s)", pluralize(len(j.Entries), "operation"), formatRelativeTime(a.Now(), j.StartedAt))))
+
+	var remoteIssues []issue.Issue
+	remoteLoaded := false
+	for _, entry := range append([]journalEntry(nil), j.Entries...) {
+		switch entry.Op {
+		case journalCreate:
+			number := entry.Number
+			if entry.Status == journalPending {
+				if !remoteLoaded {
+					var err error
+					remoteIssues, err = client.ListIssues(ctx, "all", nil)
+					if err != nil {
+						return nil, fmt.Errorf("cannot tell whether the interrupted push created %q: %w", entry.Title, err)
+					}
+					remoteLoaded = true
+				}
+				for _, remote := range remoteIssues {
+					id, _ := issue.CreationMarkerID(remote.Body)
+					if id.String() == entry.Issue || (remote.Title == entry.Title && journalBodyHash(remote.Body) == entry.BodyHash) {
+						number = remote.Number.String()
+						break
+					}
+				}
+			}
+			if number != "" {
+				if item, err := findIssueByNumber(p, entry.Issue); err == nil {
+					if err := a.finalizeCreatedIssue(p, &item, number); err != nil {
+						return nil, err
+					}
+				}
+				mapping[entry.Issue] = number
+				fmt.Fprintf(a.Out, "%s\n", t.FormatIssueHeader("A", number, entry.Title))
+			}
+		case journalComment:
+			posted := entry.Status == journalApplied
+			if !posted {
+				bodies, err := client.ListCommentBodies(ctx, entry.Issue)
+				if err != nil {
+					return nil, fmt.Errorf("cannot tell whether the interrupted push posted a comment to #%s: %w", entry.Issue, err)
+				}
+				for _, body := range bodies {
+					if journalBodyHash(body) == entry.BodyHash {
+						posted = true
+						break
+					}
+				}
+			}
+			if posted {
+				path := filepath.Join(p.IssuesDir, filepath.FromSlash(entry.Path))
+				if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+					return nil, err
+				}
+				fmt.Fprintf(a.Out, "%s #%s\n", t.SuccessText("Posted comment to"), entry.Issue)
+			}
+		}
+		if err := j.finish(entry.Op, entry.Path); err != nil {
+			return nil, err
+		}
+	}
+	return mapping, nil
+}
@@ -0,0 +1,192 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// resumeRunner serves a repository that already has the issue and comment an
+// interrupted push created, and records calls that would create them again.
+type resumeRunner struct {
+	creates []string
+}
+
+func (r *resumeRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	switch {
+	case strings.HasPrefix(joined, "issue list"):
+		return `[{"number":7,"title":"New bug","body":"Details\r\n","state":"OPEN"}]`, nil
+	case strings.HasPrefix(joined, "issue view 3 --json comments"):
+		return `{"comments":[{"body":"Already posted"}]}`, nil
+	case strings.HasPrefix(joined, "issue create"), strings.HasPrefix(joined, "issue comment"):
+		r.creates = append(r.creates, joined)
+		return "https://github.com/owner/repo/issues/8", nil
+	}
+	return "", errors.New("unexpected call: " + joined)
+}
+
+func TestPushResumesFromJournal(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if err := saveLabelCache(p, LabelCache{Labels: []LabelEntry{{Name: "bug", Color: "ff0000"}}}); err != nil {
+		t.Fatalf("label cache: %v", err)
+	}
+
+	created := issue.Issue{Number: "T1", Title: "New bug", Body: "Details", State: "open"}
+	createdPath := filepath.Join(p.OpenDir, "T1-new-bug.md")
+	if err := issue.WriteFile(createdPath, created); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	commented := issue.Issue{Number: "3", Title: "Old", State: "open"}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "3-old.md"), commented); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	if err := writeOriginalIssue(p, commented); err != nil {
+		t.Fatalf("write original: %v", err)
+	}
+	commentPath := filepath.Join(p.OpenDir, "3.comment.md")
+	if err := os.WriteFile(commentPath, []byte("Already posted\n"), 0o644); err != nil {
+		t.Fatalf("write comment: %v", err)
+	}
+
+	// The push died right after GitHub created both
+	journal, err := loadPushJournal(p)
+	if err != nil {
+		t.Fatalf("load journal: %v", err)
+	}
+	now := time.Now().UTC()
+	if err := journal.begin(journalEntry{Op: journalCreate, Issue: "T1", Path: journalPath(p, createdPath), Title: "New bug", BodyHash: journalBodyHash("Details")}, now); err != nil {
+		t.Fatalf("journal: %v", err)
+	}
+	if err := journal.begin(journalEntry{Op: journalComment, Issue: "3", Path: journalPath(p, commentPath), BodyHash: journalBodyHash("Already posted")}, now); err != nil {
+		t.Fatalf("journal: %v", err)
+	}
+
+	runner := &resumeRunner{}
+	var out bytes.Buffer
+	application := New(root, runner, &out, &out)
+	if err := application.Push(context.Background(), PushOptions{}, nil); err != nil {
+		t.Fatalf("push: %v\n%s", err, out.String())
+	}
+
+	if len(runner.creates) != 0 {
+		t.Fatalf("expected nothing to be created again, got %v", runner.creates)
+	}
+	if !strings.Contains(stripAnsi(out.String()), "Resuming interrupted push (2 operations") {
+		t.Fatalf("expected resume message, got %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(p.OpenDir, "7-new-bug.md")); err != nil {
+		t.Fatalf("expected created issue to be renamed: %v", err)
+	}
+	if _, ok := readOriginalIssue(p, "7"); !ok {
+		t.Fatalf("expected original for created issue")
+	}
+	if _, err := os.Stat(commentPath); !os.IsNotExist(err) {
+		t.Fatalf("expected posted comment file to be removed, got %v", err)
+	}
+	if _, err := os.Stat(p.JournalPath); !os.IsNotExist(err) {
+		t.Fatalf("expected journal to be removed, got %v", err)
+	}
+}
+
+func TestPushJournalDropsOperationsThatNeverHappened(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	path := filepath.Join(p.OpenDir, "T2-other.md")
+	if err := issue.WriteFile(path, issue.Issue{Number: "T2", Title: "Other", Body: "Never sent", State: "open"}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	journal, err := loadPushJournal(p)
+	if err != nil {
+		t.Fatalf("load journal: %v", err)
+	}
+	if err := journal.begin(journalEntry{Op: journalCreate, Issue: "T2", Path: journalPath(p, path), Title: "Other", BodyHash: journalBodyHash("Never sent")}, time.Now()); err != nil {
+		t.Fatalf("journal: %v", err)
+	}
+
+	runner := &resumeRunner{}
+	application := New(root, runner, &bytes.Buffer{}, &bytes.Buffer{})
+	mapping, err := application.recoverPush(context.Background(), ghcli.NewClient(runner, "owner/repo"), p, journal)
+	if err != nil {
+		t.Fatalf("recover: %v", err)
+	}
+	if len(mapping) != 0 || len(journal.Entries) != 0 {
+		t.Fatalf("expected the create to be dropped, got %v and %v", mapping, journal.Entries)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected local issue to stay for the next create: %v", err)
+	}
+}
+
+// rateLimitedRunner fails issue searches and records created issues.
+type rateLimitedRunner struct {
+	creates []string
+}
+
+func (r *rateLimitedRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	switch {
+	case strings.Contains(joined, "--search"):
+		return "", errors.New("API rate limit exceeded")
+	case strings.HasPrefix(joined, "issue create"):
+		r.creates = append(r.creates, joined)
+		return "https://github.com/owner/repo/issues/8", nil
+	}
+	return "", errors.New("unexpected call: " + joined)
+}
+
+func TestPushCreatesIssueWhenSearchFails(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if err := saveLabelCache(p, LabelCache{Labels: []LabelEntry{{Name: "bug", Color: "ff0000"}}}); err != nil {
+		t.Fatalf("label cache: %v", err)
+	}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "T1-new-bug.md"), issue.Issue{Number: "T1", Title: "New bug", State: "open"}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+
+	runner := &rateLimitedRunner{}
+	var out bytes.Buffer
+	application := New(root, runner, &out, &out)
+	if err := application.Push(context.Background(), PushOptions{}, nil); err != nil {
+		t.Fatalf("push: %v\n%s", err, out.String())
+	}
+	if len(runner.creates) != 1 {
+		t.Fatalf("expected the issue to be created, got %v", runner.creates)
+	}
+	if !strings.Contains(stripAnsi(out.String()), "Warning: looking for an existing issue for T1: API rate limit exceeded") {
+		t.Fatalf("expected search warning, got %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(p.OpenDir, "8-new-bug.md")); err != nil {
+		t.Fatalf("expected created issue to be renamed: %v", err)
+	}
+}
@@ -0,0 +1,360 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// Lint severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// localNumberPattern matches a complete local issue ID like T1a2b
+var localNumberPattern = regexp.MustCompile(`^T[a-zA-Z0-9]+$`)
+
+// validStateReasons lists the state reasons GitHub accepts (lowercased).
+var validStateReasons = map[string]struct{}{
+	"completed":   {},
+	"not_planned": {},
+	"reopened":    {},
+	"duplicate":   {},
+}
+
+// LintDiagnostic is a single problem found in an issue file.
+type LintDiagnostic struct {
+	Path     string `json:"path"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+// lintContext holds the caches issue files are validated against.
+type lintContext struct {
+	labels     map[string]string
+	milestones map[string]struct{}
+	issueTypes map[string]IssueTypeEntry
+}
+
+func (a *App) Lint(ctx context.Context, opts LintOptions, args []string) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+
+	labelCache, _ := loadLabelCache(p)
+	milestoneCache, _ := loadMilestoneCache(p)
+	issueTypeCache, _ := loadIssueTypeCache(p)
+	lc := lintContext{
+		labels:     labelCacheToColorMap(labelCache),
+		milestones: milestoneNames(milestoneCache),
+		issueTypes: issueTypeByName(issueTypeCache),
+	}
+
+	diagnostics, err := lintIssueFiles(p, lc)
+	if err != nil {
+		return err
+	}
+
+	for i := range diagnostics {
+		diagnostics[i].Path = relPath(a.Root, diagnostics[i].Path)
+	}
+
+	// Restrict output to the requested files, but lint everything so that
+	// cross-file checks like duplicate numbers still work.
+	if len(args) > 0 {
+		cwd, _ := os.Getwd()
+		wanted := make(map[string]struct{}, len(args))
+		for _, arg := range args {
+			path := arg
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(cwd, path)
+			}
+			wanted[relPath(a.Root, filepath.Clean(path))] = struct{}{}
+		}
+		var filtered []LintDiagnostic
+		for _, d := range diagnostics {
+			if _, ok := wanted[d.Path]; ok {
+				filtered = append(filtered, d)
+			}
+		}
+		diagnostics = filtered
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			errorCount++
+		} else {
+			warningCount++
+		}
+	}
+
+	switch opts.Format {
+	case "json":
+		if diagnostics == nil {
+			diagnostics = []LintDiagnostic{}
+		}
+		data, err := json.MarshalIndent(diagnostics, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(a.Out, string(data))
+	case "github":
+		for _, d := range diagnostics {
+			fmt.Fprintln(a.Out, formatGitHubAnnotation(d))
+		}
+	default:
+		a.printLintText(diagnostics, errorCount, warningCount)
+	}
+
+	if errorCount > 0 || (opts.Strict && warningCount > 0) {
+		return fmt.Errorf("lint failed: %s, %s", pluralize(errorCount, "error"), pluralize(warningCount, "warning"))
+	}
+	return nil
+}
+
+func (a *App) printLintText(diagnostics []LintDiagnostic, errorCount, warningCount int) {
+	t := a.Theme
+	for _, d := range diagnostics {
+		location := d.Path
+		if d.Line > 0 {
+			location = fmt.Sprintf("%s:%d", d.Path, d.Line)
+		}
+		severity := t.WarningText(d.Severity + ":")
+		if d.Severity == SeverityError {
+			severity = t.ErrorText(d.Severity + ":")
+		}
+		fmt.Fprintf(a.Out, "%s: %s %s %s\n", location, severity, d.Message, t.MutedText("["+d.Rule+"]"))
+	}
+	if len(diagnostics) == 0 {
+		fmt.Fprintln(a.Out, t.MutedText("No problems found"))
+		return
+	}
+	fmt.Fprintf(a.Out, "\n%s\n", t.MutedText(fmt.Sprintf("%s, %s", pluralize(errorCount, "error"), pluralize(warningCount, "warning"))))
+}
+
+// formatGitHubAnnotation formats a diagnostic as a GitHub Actions workflow command.
+func formatGitHubAnnotation(d LintDiagnostic) string {
+	props := "file=" + d.Path
+	if d.Line > 0 {
+		props += fmt.Sprintf(",line=%d", d.Line)
+	}
+	props += ",title=" + d.Rule
+	message := strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(d.Message)
+	return fmt.Sprintf("::%s %s::%s", d.Severity, props, message)
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// lintIssueFiles validates every issue file in the open and closed directories.
+// Diagnostics carry absolute paths and are sorted by path and line.
+func lintIssueFiles(p paths.Paths, lc lintContext) ([]LintDiagnostic, error) {
+	var diagnostics []LintDiagnostic
+	byNumber := make(map[string][]string)
+
+	for _, dir := range []struct {
+		Path  string
+		State string
+	}{{p.OpenDir, "open"}, {p.ClosedDir, "closed"}} {
+		entries, err := os.ReadDir(dir.Path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+				continue
+			}
+			if strings.HasSuffix(entry.Name(), ".comment.md") {
+				continue
+			}
+			path := filepath.Join(dir.Path, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			found, number := lintIssueFile(path, data, dir.State, lc)
+			diagnostics = append(diagnostics, found...)
+			if number != "" {
+				byNumber[number] = append(byNumber[number], path)
+			}
+		}
+	}
+
+	for number, files := range byNumber {
+		if len(files) < 2 {
+			continue
+		}
+		sort.Strings(files)
+		for i, path := range files {
+			others := make([]string, 0, len(files)-1)
+			for j, other := range files {
+				if i != j {
+					others = append(others, filepath.Base(other))
+				}
+			}
+			diagnostics = append(diagnostics, LintDiagnostic{
+				Path:     path,
+				Severity: SeverityError,
+				Rule:     "duplicate-number",
+				Message:  fmt.Sprintf("issue number %s is also used by %s", number, strings.Join(others, ", ")),
+			})
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		if diagnostics[i].Path != diagnostics[j].Path {
+			return diagnostics[i].Path < diagnostics[j].Path
+		}
+		return diagnostics[i].Line < diagnostics[j].Line
+	})
+	return diagnostics, nil
+}
+
+// lintIssueFile validates a single issue file. It returns the diagnostics and
+// the issue number derived from the filename (empty if it could not be parsed).
+func lintIssueFile(path string, data []byte, dirState string, lc lintContext) ([]LintDiagnostic, string) {
+	var diagnostics []LintDiagnostic
+	report := func(line int, severity, rule, format string, args ...any) {
+		diagnostics = append(diagnostics, LintDiagnostic{
+			Path:     path,
+			Line:     line,
+			Severity: severity,
+			Rule:     rule,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	keys, err := issue.FrontMatterKeys(data)
+	if err != nil {
+		report(1, SeverityError, "parse-error", "%v", err)
+		return diagnostics, ""
+	}
+	parsed, err := issue.Parse(data)
+	if err != nil {
+		report(1, SeverityError, "parse-error", "%v", err)
+		return diagnostics, ""
+	}
+
+	lines := make(map[string]issue.KeyInfo, len(keys))
+	for _, key := range keys {
+		lines[key.Name] = key
+		// Unknown keys are kept as local-only extra fields, so only flag
+		// the ones that look like a misspelled known key.
+		if !issue.IsKnownKey(key.Name) {
+			if suggestion := suggestKey(key.Name); suggestion != "" {
+				report(key.Line, SeverityWarning, "unknown-key", "unknown front matter key %q (did you mean %q?)", key.Name, suggestion)
+			}
+		}
+	}
+
+	base := filepath.Base(path)
+	number := strings.TrimSuffix(base, ".md")
+	if idx := strings.Index(number, "-"); idx != -1 {
+		number = number[:idx]
+	}
+	if !isValidIssueNumber(number) {
+		report(0, SeverityError, "filename", "filename %q does not start with an issue number or local ID", base)
+		number = ""
+	}
+
+	if key, ok := lines["number"]; ok && number != "" && key.Value != "" && key.Value != number {
+		report(key.Line, SeverityWarning, "number-mismatch", "number %s does not match filename (%s)", key.Value, number)
+	}
+
+	title := strings.TrimSpace(parsed.Title)
+	if title == "" {
+		report(lines["title"].Line, SeverityError, "missing-title", "title is required")
+	} else if number != "" {
+		if expected := issue.FileName(issue.IssueNumber(number), title); expected != base {
+			report(lines["title"].Line, SeverityWarning, "filename-mismatch", "filename should be %q to match the title", expected)
+		}
+	}
+
+	if parsed.State != "" && parsed.State != "open" && parsed.State != "closed" {
+		report(lines["state"].Line, SeverityError, "invalid-state", "state must be \"open\" or \"closed\", got %q", parsed.State)
+	} else if parsed.State != "" && parsed.State != dirState {
+		report(lines["state"].Line, SeverityWarning, "state-mismatch", "state is %q but the file is in the %s directory", parsed.State, dirState)
+	}
+
+	if parsed.StateReason != nil && *parsed.StateReason != "" {
+		if _, ok := validStateReasons[strings.ToLower(*parsed.StateReason)]; !ok {
+			report(lines["state_reason"].Line, SeverityError, "invalid-state-reason", "state_reason must be one of completed, not_planned, reopened, duplicate; got %q", *parsed.StateReason)
+		}
+	}
+
+	if len(lc.labels) > 0 {
+		for _, label := range parsed.Labels {
+			if _, ok := lc.labels[strings.ToLower(label)]; !ok {
+				report(lines["labels"].Line, SeverityWarning, "unknown-label", "label %q does not exist (it will be created on push)", label)
+			}
+		}
+	}
+
+	if len(lc.milestones) > 0 && parsed.Milestone != "" {
+		if _, ok := lc.milestones[strings.ToLower(parsed.Milestone)]; !ok {
+			report(lines["milestone"].Line, SeverityWarning, "unknown-milestone", "milestone %q does not exist (it will be created on push)", parsed.Milestone)
+		}
+	}
+
+	if len(lc.issueTypes) > 0 && parsed.IssueType != "" {
+		if _, ok := lc.issueTypes[strings.ToLower(parsed.IssueType)]; !ok {
+			report(lines["type"].Line, SeverityError, "unknown-type", "issue type %q is not defined for this repository", parsed.IssueType)
+		}
+	}
+
+	return diagnostics, number
+}
+
+func isValidIssueNumber(number string) bool {
+	if number == "" {
+		return false
+	}
+	if strings.HasPrefix(number, "T") {
+		return localNumberPattern.MatchString(number)
+	}
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// suggestKey returns the known key closest to key if it looks like a typo.
+// Short keys only match on a single edit so custom fields like "eta" are not
+// mistaken for typos.
+func suggestKey(key string) string {
+	maxDistance := 1
+	if len(key) >= 5 {
+		maxDistance = 2
+	}
+	best := ""
+	for _, known := range issue.KnownKeys {
+		if d := levenshteinRunes([]rune(strings.ToLower(key)), []rune(known)); d <= maxDistance {
+			best = known
+			maxDistance = d - 1
+		}
+	}
+	return best
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func lintRules(diagnostics []LintDiagnostic) []string {
+	rules := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		rules = append(rules, d.Rule)
+	}
+	return rules
+}
+
+func TestLintIssueFile(t *testing.T) {
+	lc := lintContext{
+		labels:     map[string]string{"bug": "d73a4a"},
+		milestones: map[string]struct{}{"v1": {}},
+		issueTypes: map[string]IssueTypeEntry{"bug": {Name: "Bug"}},
+	}
+
+	data := []byte(`---
+title: Fix login
+labels:
+  - bug
+  - nope
+lables:
+  - bug
+milestone: v2
+type: Feature
+state: open
+state_reason: wontfix
+---
+Body
+`)
+	diagnostics, number := lintIssueFile("/repo/.issues/open/12-fix-login.md", data, "closed", lc)
+	if number != "12" {
+		t.Fatalf("expected number 12, got %q", number)
+	}
+
+	expected := map[string]int{
+		"unknown-label":        3,
+		"unknown-key":          6,
+		"unknown-milestone":    8,
+		"unknown-type":         9,
+		"state-mismatch":       10,
+		"invalid-state-reason": 11,
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics, got %v", len(expected), lintRules(diagnostics))
+	}
+	for _, d := range diagnostics {
+		line, ok := expected[d.Rule]
+		if !ok {
+			t.Fatalf("unexpected diagnostic: %+v", d)
+		}
+		if d.Line != line {
+			t.Errorf("%s: expected line %d, got %d", d.Rule, line, d.Line)
+		}
+	}
+	for _, d := range diagnostics {
+		if d.Rule == "unknown-key" && !strings.Contains(d.Message, `did you mean "labels"`) {
+			t.Errorf("expected suggestion in message, got %q", d.Message)
+		}
+	}
+}
+
+func TestLintIssueFileClean(t *testing.T) {
+	data := []byte(`---
+title: Fix login
+state: open
+estimate: 3
+eta: next week
+---
+Body
+`)
+	diagnostics, number := lintIssueFile("/repo/.issues/open/T1a2b-fix-login.md", data, "open", lintContext{})
+	if number != "T1a2b" {
+		t.Fatalf("expected local number, got %q", number)
+	}
+	if len(diagnostics) != 0 {
+		t.Fatalf("expected no diagnostics, got %v", lintRules(diagnostics))
+	}
+}
+
+func TestLintIssueFileBadFilename(t *testing.T) {
+	data := []byte("---\ntitle: \"\"\n---\n")
+	diagnostics, number := lintIssueFile("/repo/.issues/open/notes.md", data, "open", lintContext{})
+	if number != "" {
+		t.Fatalf("expected no number, got %q", number)
+	}
+	rules := strings.Join(lintRules(diagnostics), ",")
+	if rules != "filename,missing-title" {
+		t.Fatalf("unexpected diagnostics: %s", rules)
+	}
+}
+
+func TestLintIssueFilesDuplicateNumbers(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	write := func(path, title, state string) {
+		content := "---\ntitle: " + title + "\nstate: " + state + "\n---\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	write(filepath.Join(p.OpenDir, "5-first.md"), "First", "open")
+	write(filepath.Join(p.ClosedDir, "5-second.md"), "Second", "closed")
+	write(filepath.Join(p.OpenDir, "6-third.md"), "Third", "open")
+	if err := os.WriteFile(filepath.Join(p.OpenDir, "6-third.comment.md"), []byte("comment"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	diagnostics, err := lintIssueFiles(p, lintContext{})
+	if err != nil {
+		t.Fatalf("lint failed: %v", err)
+	}
+	if len(diagnostics) != 2 {
+		t.Fatalf("expected 2 diagnostics, got %v", lintRules(diagnostics))
+	}
+	for _, d := range diagnostics {
+		if d.Rule != "duplicate-number" {
+			t.Fatalf("unexpected diagnostic: %+v", d)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// LockStatus shows whether the sync lock is held and by whom.
+func (a *App) LockStatus(ctx context.Context) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+	t := a.Theme
+
+	status, err := lock.Inspect(p.SyncDir)
+	if err != nil {
+		return err
+	}
+	switch {
+	case status.Held && status.Mode == lock.Shared:
+		fmt.Fprintf(a.Out, "%s %s\n", t.WarningText("Locked"), t.MutedText("(shared, read-only commands are running)"))
+	case status.Held:
+		fmt.Fprintf(a.Out, "%s %s\n", t.WarningText("Locked"), t.MutedText("(exclusive)"))
+	default:
+		fmt.Fprintln(a.Out, t.SuccessText("Unlocked"))
+	}
+	if info := status.Info; info != nil {
+		label := "Holder:"
+		if !status.Held {
+			label = "Last holder (exited without cleanup):"
+		}
+		fmt.Fprintln(a.Out, t.MutedText(label))
+		fmt.Fprintf(a.Out, "  %s %d\n", t.MutedText("pid:"), info.PID)
+		fmt.Fprintf(a.Out, "  %s %s\n", t.MutedText("host:"), formatOptionalString(info.Host))
+		fmt.Fprintf(a.Out, "  %s %s\n", t.MutedText("command:"), formatOptionalString(info.Command))
+		fmt.Fprintf(a.Out, "  %s %s\n", t.MutedText("since:"), formatRelativeTime(a.Now(), info.CreatedAt))
+	}
+	return nil
+}
+
+// LockBreak removes the sync lock file. Without force it refuses while the
+// lock is held; OS file locks die with their process, so a held lock means
+// the holder is still running (or hung).
+func (a *App) LockBreak(ctx context.Context, force bool) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+	t := a.Theme
+
+	status, err := lock.Inspect(p.SyncDir)
+	if err != nil {
+		return err
+	}
+	if status.Held && !force {
+		holder := status.Mode.String() + " lock"
+		if info := status.Info; info != nil {
+			holder = fmt.Sprintf("pid %d on %s running %s", info.PID, formatOptionalString(info.Host), formatOptionalString(info.Command))
+		}
+		return fmt.Errorf("lock is held by %s; use --force to break it anyway", holder)
+	}
+	if err := lock.Break(p.SyncDir); err != nil {
+		return err
+	}
+	fmt.Fprintln(a.Out, t.SuccessText("Lock removed"))
+	return nil
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestLockStatusAndBreak(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	lck, err := lock.Acquire(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer lck.Release()
+
+	var out strings.Builder
+	application := New(root, ghcli.ExecRunner{}, &out, io.Discard)
+	if err := application.LockStatus(context.Background()); err != nil {
+		t.Fatalf("lock status: %v", err)
+	}
+	status := stripAnsi(out.String())
+	if !strings.Contains(status, "Locked (exclusive)") || !strings.Contains(status, "pid:") {
+		t.Fatalf("unexpected status: %q", status)
+	}
+
+	if err := application.LockBreak(context.Background(), false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected break to refuse a held lock, got %v", err)
+	}
+	if err := application.LockBreak(context.Background(), true); err != nil {
+		t.Fatalf("forced break: %v", err)
+	}
+	if status, err := lock.Inspect(p.SyncDir); err != nil || status.Held {
+		t.Fatalf("expected lock to be gone, got %+v %v", status, err)
+	}
+}
@@ -0,0 +1,360 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+const (
+	burndownHeight    = 6
+	burndownMaxWidth  = 60
+	milestoneBarWidth = 24
+)
+
+// burndownBlocks fill a chart cell from the bottom in eighths.
+var burndownBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// milestoneProgress is a milestone with the local issues assigned to it.
+type milestoneProgress struct {
+	Title  string
+	State  string
+	DueOn  *time.Time
+	Issues []IssueFile
+}
+
+func (m milestoneProgress) counts() (open, closed int) {
+	for _, item := range m.Issues {
+		if item.State == "closed" {
+			closed++
+		} else {
+			open++
+		}
+	}
+	return open, closed
+}
+
+// start returns when work on the milestone started: the creation of its
+// oldest issue.
+func (m milestoneProgress) start() (time.Time, bool) {
+	var start time.Time
+	for _, item := range m.Issues {
+		if created := item.Issue.CreatedAt; created != nil && (start.IsZero() || created.Before(start)) {
+			start = *created
+		}
+	}
+	return start, !start.IsZero()
+}
+
+// parseDueOn parses a milestone due date as returned by GitHub.
+func parseDueOn(value *string) *time.Time {
+	if value == nil || *value == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, *value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// collectMilestones returns the cached milestones and those only known from
+// issues, open ones first by due date. Closed milestones are left out unless
+// all is set.
+func collectMilestones(cache MilestoneCache, issues []IssueFile, all bool) []milestoneProgress {
+	byTitle := map[string]*milestoneProgress{}
+	var milestones []*milestoneProgress
+	for _, entry := range cache.Milestones {
+		m := &milestoneProgress{Title: entry.Title, State: entry.State, DueOn: parseDueOn(entry.DueOn)}
+		byTitle[strings.ToLower(entry.Title)] = m
+		milestones = append(milestones, m)
+	}
+	for _, item := range issues {
+		title := item.Issue.Milestone
+		if title == "" {
+			continue
+		}
+		m, ok := byTitle[strings.ToLower(title)]
+		if !ok {
+			m = &milestoneProgress{Title: title, State: "open"}
+			byTitle[strings.ToLower(title)] = m
+			milestones = append(milestones, m)
+		}
+		m.Issues = append(m.Issues, item)
+	}
+
+	var result []milestoneProgress
+	for _, m := range milestones {
+		if all || m.State != "closed" {
+			result = append(result, *m)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.State == "closed") != (b.State == "closed") {
+			return b.State == "closed"
+		}
+		if (a.DueOn == nil) != (b.DueOn == nil) {
+			return a.DueOn != nil
+		}
+		if a.DueOn != nil && !a.DueOn.Equal(*b.DueOn) {
+			return a.DueOn.Before(*b.DueOn)
+		}
+		return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+	})
+	return result
+}
+
+// openAt counts the issues that existed and were not closed yet at t.
+// Issues without a creation time count from the start.
+func openAt(issues []IssueFile, t time.Time) int {
+	open := 0
+	for _, item := range issues {
+		if created := item.Issue.CreatedAt; created != nil && created.After(t) {
+			continue
+		}
+		if item.State == "closed" {
+			if closed := closedTime(item.Issue); closed == nil || !closed.After(t) {
+				continue
+			}
+		}
+		open++
+	}
+	return open
+}
+
+// fillClosedTimes sets the closing time of closed issues synced before it was
+// recorded, from the first synced version of them that was closed.
+func fillClosedTimes(p paths.Paths, issues []IssueFile) {
+	for i, item := range issues {
+		if item.State != "closed" || item.Issue.ClosedAt != nil || item.Issue.Number.IsLocal() {
+			continue
+		}
+		versions, err := issueVersions(p, item.Issue.Number.String())
+		if err != nil {
+			continue
+		}
+		for _, v := range versions {
+			if v.Issue.State == "closed" && v.Issue.SyncedAt != nil {
+				closed := *v.Issue.SyncedAt
+				issues[i].Issue.ClosedAt = &closed
+				break
+			}
+		}
+	}
+}
+
+// burndownSeries samples the open issues of a milestone at width points
+// from start to end. Points after now are -1.
+func burndownSeries(issues []IssueFile, start, end, now time.Time, width int) []int {
+	series := make([]int, width)
+	for i := range series {
+		t := end
+		if width > 1 {
+			t = start.Add(time.Duration(float64(end.Sub(start)) * float64(i) / float64(width-1)))
+		}
+		if t.After(now) {
+			series[i] = -1
+			continue
+		}
+		series[i] = openAt(issues, t)
+	}
+	return series
+}
+
+// renderBurndown draws a burndown chart of series, with the ideal line from
+// the first value down to zero at the due column (-1 for none), and the
+// dates of the first and last column below.
+func (a *App) renderBurndown(series []int, due int, from, to string) []string {
+	t := a.Theme
+	top := 0
+	for _, n := range series {
+		top = max(top, n)
+	}
+	if top == 0 {
+		return nil
+	}
+	ideal := func(col int) float64 {
+		if due <= 0 || col > due {
+			return -1
+		}
+		return float64(series[0]) * (1 - float64(col)/float64(due))
+	}
+
+	axisWidth := len(fmt.Sprint(top))
+	var lines []string
+	for row := burndownHeight; row >= 1; row-- {
+		label := strings.Repeat(" ", axisWidth)
+		if row == burndownHeight {
+			label = fmt.Sprintf("%*d", axisWidth, top)
+		}
+		var line strings.Builder
+		for col, n := range series {
+			// Eighths of this row the value covers
+			fill := n*burndownHeight*8/top - (row-1)*8
+			switch {
+			case n >= 0 && fill > 0:
+				line.WriteString(t.AccentText(string(burndownBlocks[min(fill, 8)])))
+			case ideal(col) >= 0 && int(ideal(col)*burndownHeight/float64(top)+0.5) == row:
+				line.WriteString(t.MutedText("·"))
+			default:
+				line.WriteString(" ")
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s %s %s", t.MutedText(label), t.MutedText("┤"), line.String()))
+	}
+	lines = append(lines, fmt.Sprintf("%s %s", t.MutedText(fmt.Sprintf("%*d", axisWidth, 0)), t.MutedText("└"+strings.Repeat("─", len(series)+1))))
+	gap := max(1, len(series)-len(from)-len(to))
+	lines = append(lines, t.MutedText(strings.Repeat(" ", axisWidth+3)+from+strings.Repeat(" ", gap)+to))
+	return lines
+}
+
+// progressBar renders the share of closed issues as a bar.
+func (a *App) progressBar(closed, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = closed * milestoneBarWidth / total
+	}
+	return a.Theme.SuccessText(strings.Repeat("█", filled)) + a.Theme.MutedText(strings.Repeat("░", milestoneBarWidth-filled))
+}
+
+// Milestones lists milestones with their open and closed issues. With
+// Progress it adds the share of closed issues and a burndown chart, and with
+// Mermaid it prints a Mermaid gantt chart of the milestones with due dates.
+func (a *App) Milestones(ctx context.Context, opts MilestonesOptions) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+	t := a.Theme
+
+	cache, err := loadMilestoneCache(p)
+	if err != nil {
+		return err
+	}
+	issues, err := loadLocalIssues(p)
+	if err != nil {
+		return err
+	}
+	if opts.Progress {
+		fillClosedTimes(p, issues)
+	}
+	milestones := collectMilestones(cache, issues, opts.All)
+	if opts.Mermaid {
+		fmt.Fprint(a.Out, a.milestonesGantt(milestones))
+		return nil
+	}
+	if len(milestones) == 0 {
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText("No milestones"))
+		return nil
+	}
+
+	now := a.Now()
+	for i, m := range milestones {
+		if i > 0 && opts.Progress {
+			fmt.Fprintln(a.Out)
+		}
+		open, closed := m.counts()
+		line := t.Bold(m.Title)
+		if m.State == "closed" {
+			line += " " + t.MutedText("(closed)")
+		}
+		if m.DueOn != nil {
+			due := "due " + m.DueOn.Format("2006-01-02")
+			if m.State != "closed" && open > 0 && m.DueOn.Before(now) {
+				line += "  " + t.ErrorText(due+" (overdue)")
+			} else {
+				line += "  " + t.MutedText(due)
+			}
+		}
+		line += "  " + t.MutedText(fmt.Sprintf("%d open, %d closed", open, closed))
+		fmt.Fprintln(a.Out, line)
+		if !opts.Progress {
+			continue
+		}
+
+		total := open + closed
+		if total == 0 {
+			fmt.Fprintf(a.Out, "  %s\n", t.MutedText("No issues"))
+			continue
+		}
+		fmt.Fprintf(a.Out, "  %s %3d%%\n", a.progressBar(closed, total), closed*100/total)
+
+		start, ok := m.start()
+		if !ok {
+			continue
+		}
+		end := now
+		if m.DueOn != nil && m.DueOn.After(end) {
+			end = *m.DueOn
+		}
+		days := int(end.Sub(start).Hours()/24) + 1
+		if days < 2 {
+			continue
+		}
+		width := min(days, burndownMaxWidth)
+		due := -1
+		if m.DueOn != nil && m.DueOn.After(start) {
+			due = int(float64(width-1) * float64(m.DueOn.Sub(start)) / float64(end.Sub(start)))
+		}
+		fmt.Fprintln(a.Out)
+		series := burndownSeries(m.Issues, start, end, now, width)
+		for _, chartLine := range a.renderBurndown(series, due, start.Format("2006-01-02"), end.Format("2006-01-02")) {
+			fmt.Fprintf(a.Out, "  %s\n", chartLine)
+		}
+	}
+	return nil
+}
+
+// milestonesGantt renders the milestones with due dates as a Mermaid gantt
+// chart, from their oldest issue to the due date.
+func (a *App) milestonesGantt(milestones []milestoneProgress) string {
+	now := a.Now()
+	var out strings.Builder
+	out.WriteString("gantt\n")
+	out.WriteString("    title Milestones\n")
+	out.WriteString("    dateFormat YYYY-MM-DD\n")
+	for i, m := range milestones {
+		if m.DueOn == nil {
+			continue
+		}
+		open, closed := m.counts()
+		start, ok := m.start()
+		if !ok || start.After(*m.DueOn) {
+			start = now
+			if start.After(*m.DueOn) {
+				start = *m.DueOn
+			}
+		}
+		status := "active"
+		switch {
+		case m.State == "closed" || (open == 0 && closed > 0):
+			status = "done"
+		case m.DueOn.Before(now):
+			status = "crit"
+		}
+		percent := 0
+		if open+closed > 0 {
+			percent = closed * 100 / (open + closed)
+		}
+		// Colons and semicolons end a task name in Mermaid
+		name := strings.NewReplacer(":", " ", ";", " ", "#", "").Replace(m.Title)
+		fmt.Fprintf(&out, "    section %s\n", name)
+		fmt.Fprintf(&out, "    %s (%d%%) :%s, m%d, %s, %s\n", name, percent, status, i+1,
+			start.Format("2006-01-02"), m.DueOn.Format("2006-01-02"))
+	}
+	return out.String()
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestBurndownSeries(t *testing.T) {
+	day := func(d int) *time.Time {
+		ts := time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+		return &ts
+	}
+	issues := []IssueFile{
+		{State: "closed", Issue: issue.Issue{CreatedAt: day(1), ClosedAt: day(3)}},
+		{State: "closed", Issue: issue.Issue{CreatedAt: day(1), ClosedAt: day(5)}},
+		{State: "open", Issue: issue.Issue{CreatedAt: day(2)}},
+	}
+	got := burndownSeries(issues, *day(1), *day(7), *day(5), 7)
+	want := []int{2, 3, 2, 2, 1, -1, -1}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("burndownSeries = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMilestones(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	due1, due2 := "2026-10-25T07:00:00Z", "2026-10-10T07:00:00Z"
+	cache := MilestoneCache{Milestones: []MilestoneEntry{
+		{Title: "v1.1: polish", State: "open", DueOn: &due1},
+		{Title: "v1.0", State: "open", DueOn: &due2},
+		{Title: "Someday", State: "open"},
+		{Title: "v0.9", State: "closed"},
+	}}
+	data, _ := json.Marshal(cache)
+	if err := os.WriteFile(p.MilestonesPath, data, 0o644); err != nil {
+		t.Fatalf("milestones: %v", err)
+	}
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	closed := time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC)
+	files := []struct {
+		dir string
+		iss issue.Issue
+	}{
+		{p.ClosedDir, issue.Issue{Number: "1", Title: "One", State: "closed", Milestone: "v1.0", CreatedAt: &created, ClosedAt: &closed}},
+		{p.OpenDir, issue.Issue{Number: "2", Title: "Two", State: "open", Milestone: "v1.0", CreatedAt: &created}},
+		{p.OpenDir, issue.Issue{Number: "3", Title: "Three", State: "open", Milestone: "v1.1: polish", CreatedAt: &closed}},
+	}
+	for _, f := range files {
+		if err := issue.WriteFile(issue.PathFor(f.dir, f.iss.Number, f.iss.Title), f.iss); err != nil {
+			t.Fatalf("write issue: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	application := New(root, nil, &out, &out)
+	application.Now = func() time.Time { return time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC) }
+
+	if err := application.Milestones(context.Background(), MilestonesOptions{}); err != nil {
+		t.Fatalf("milestones: %v", err)
+	}
+	want := "v1.0  due 2026-10-10 (overdue)  1 open, 1 closed\n" +
+		"v1.1: polish  due 2026-10-25  1 open, 0 closed\n" +
+		"Someday  0 open, 0 closed\n"
+	if got := stripAnsi(out.String()); got != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	out.Reset()
+	if err := application.Milestones(context.Background(), MilestonesOptions{Progress: true}); err != nil {
+		t.Fatalf("milestones: %v", err)
+	}
+	text := stripAnsi(out.String())
+	for _, want := range []string{"████████████░░░░░░░░░░░░  50%", "2 ┤", "2026-10-01", "2026-10-25", "No issues"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output:\n%s", want, text)
+		}
+	}
+
+	out.Reset()
+	if err := application.Milestones(context.Background(), MilestonesOptions{Mermaid: true}); err != nil {
+		t.Fatalf("milestones: %v", err)
+	}
+	gantt := "gantt\n" +
+		"    title Milestones\n" +
+		"    dateFormat YYYY-MM-DD\n" +
+		"    section v1.0\n" +
+		"    v1.0 (50%) :crit, m1, 2026-10-01, 2026-10-10\n" +
+		"    section v1.1  polish\n" +
+		"    v1.1  polish (0%) :active, m2, 2026-10-08, 2026-10-25\n"
+	if out.String() != gantt {
+		t.Fatalf("unexpected gantt:\n%s\nwant:\n%s", out.String(), gantt)
+	}
+}
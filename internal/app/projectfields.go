@@ -0,0 +1,161 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// projectFieldChange is a project field whose value differs between the
+// original and the local issue. An empty Value clears the field.
+type projectFieldChange struct {
+	Project string
+	Field   string
+	Value   string
+}
+
+// diffProjectFields returns the field values to set so that original matches
+// local, sorted by project and field name.
+func diffProjectFields(original, local issue.ProjectFields) []projectFieldChange {
+	original = original.Normalized()
+	local = local.Normalized()
+	var changes []projectFieldChange
+	for project, fields := range local {
+		for name, value := range fields {
+			if original[project][name] != value {
+				changes = append(changes, projectFieldChange{Project: project, Field: name, Value: value})
+			}
+		}
+	}
+	for project, fields := range original {
+		for name := range fields {
+			if _, ok := local[project][name]; !ok {
+				changes = append(changes, projectFieldChange{Project: project, Field: name})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Project != changes[j].Project {
+			return changes[i].Project < changes[j].Project
+		}
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+// projectFieldDefinitions looks up the field definitions of a project,
+// fetching and caching them if they are not known yet.
+func (a *App) projectFieldDefinitions(ctx context.Context, client *ghcli.Client, p paths.Paths, cache *ProjectFieldCache, project ProjectEntry) ([]ProjectFieldEntry, error) {
+	for _, entry := range cache.Projects {
+		if entry.ProjectID == project.ID {
+			return entry.Fields, nil
+		}
+	}
+	fields, err := client.ListProjectFields(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+	entry := ProjectFieldsEntry{ProjectID: project.ID, Title: project.Title, Fields: projectFieldEntries(fields)}
+	cache.Projects = append(cache.Projects, entry)
+	cache.SyncedAt = a.Now().UTC()
+	if err := saveProjectFieldCache(p, *cache); err != nil {
+		fmt.Fprintf(a.Err, "%s saving project field cache: %v\n", a.Theme.WarningText("Warning:"), err)
+	}
+	return entry.Fields, nil
+}
+
+// syncProjectFields pushes the project field values that changed locally
+// (between original and local). Fields that already have the wanted value on
+// remote are skipped, and fields only changed remotely are left alone.
+func (a *App) syncProjectFields(ctx context.Context, client *ghcli.Client, p paths.Paths, number string, original, local, remote issue.ProjectFields, knownProjects map[string]ProjectEntry, cache *ProjectFieldCache) error {
+	remote = remote.Normalized()
+	var updates []ghcli.ProjectFieldUpdate
+	for _, change := range diffProjectFields(original, local) {
+		if remote[change.Project][change.Field] == change.Value {
+			continue
+		}
+		project, ok := knownProjects[strings.ToLower(change.Project)]
+		if !ok {
+			return fmt.Errorf("unknown project %q", change.Project)
+		}
+		fields, err := a.projectFieldDefinitions(ctx, client, p, cache, project)
+		if err != nil {
+			return err
+		}
+		field, ok := findProjectField(fields, change.Field)
+		if !ok {
+			return fmt.Errorf("project %q has no field %q", change.Project, change.Field)
+		}
+		updates = append(updates, ghcli.ProjectFieldUpdate{
+			ProjectID: project.ID,
+			Field:     field,
+			Value:     change.Value,
+		})
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return client.UpdateProjectFields(ctx, number, updates)
+}
+
+// refreshProjectFieldCache refetches the field definitions of all projects
+// that local issues have field values for.
+func (a *App) refreshProjectFieldCache(ctx context.Context, client *ghcli.Client, p paths.Paths, projects []ProjectEntry, localIssues []IssueFile) error {
+	used := make(map[string]struct{})
+	for _, item := range localIssues {
+		for title := range item.Issue.ProjectFields {
+			used[strings.ToLower(title)] = struct{}{}
+		}
+	}
+	if len(used) == 0 {
+		return nil
+	}
+	cache := ProjectFieldCache{SyncedAt: a.Now().UTC()}
+	for _, project := range projects {
+		if _, ok := used[strings.ToLower(project.Title)]; !ok {
+			continue
+		}
+		fields, err := client.ListProjectFields(ctx, project.ID)
+		if err != nil {
+			return err
+		}
+		cache.Projects = append(cache.Projects, ProjectFieldsEntry{
+			ProjectID: project.ID,
+			Title:     project.Title,
+			Fields:    projectFieldEntries(fields),
+		})
+	}
+	return saveProjectFieldCache(p, cache)
+}
+
+func projectFieldEntries(fields []ghcli.ProjectField) []ProjectFieldEntry {
+	entries := make([]ProjectFieldEntry, 0, len(fields))
+	for _, field := range fields {
+		entry := ProjectFieldEntry{ID: field.ID, Name: field.Name, DataType: field.DataType}
+		for _, opt := range field.Options {
+			entry.Options = append(entry.Options, ProjectFieldOptionEntry{ID: opt.ID, Name: opt.Name})
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// findProjectField looks up a field by name, ignoring case.
+func findProjectField(fields []ProjectFieldEntry, name string) (ghcli.ProjectField, bool) {
+	for _, entry := range fields {
+		if !strings.EqualFold(entry.Name, name) {
+			continue
+		}
+		field := ghcli.ProjectField{ID: entry.ID, Name: entry.Name, DataType: entry.DataType}
+		for _, opt := range entry.Options {
+			field.Options = append(field.Options, ghcli.ProjectFieldOption{ID: opt.ID, Name: opt.Name})
+		}
+		return field, true
+	}
+	return ghcli.ProjectField{}, false
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+)
+
+func TestDiffProjectFields(t *testing.T) {
+	original := issue.ProjectFields{
+		"Roadmap": {"Status": "Todo", "Estimate": "3"},
+		"Bugs":    {"Priority": "P1"},
+	}
+	local := issue.ProjectFields{
+		"Roadmap": {"Status": "Done", "Estimate": "3", "Iteration": "Sprint 4"},
+		"Bugs":    {"Priority": ""},
+	}
+	got := fmt.Sprint(diffProjectFields(original, local))
+	expected := "[{Bugs Priority } {Roadmap Iteration Sprint 4} {Roadmap Status Done}]"
+	if got != expected {
+		t.Fatalf("unexpected changes:\n got %s\nwant %s", got, expected)
+	}
+	if changes := diffProjectFields(local, local); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %v", changes)
+	}
+}
@@ -31,9 +31,24 @@ func (a *App) Pull(ctx context.Context, opts PullOptions, args []string) error {
 	}
 	defer lck.Release()
 
+	rec := a.beginOperation(p, "pull")
+	defer a.finishOperation(p, rec)
+
 	client := ghcli.NewClient(a.Runner, repoSlug(cfg))
 	t := a.Theme
 
+	attachmentModeChanged, err := resolveAttachmentMode(p, &cfg, opts.Attachments)
+	if err != nil {
+		return err
+	}
+	att, err := loadAttachments(p, cfg)
+	if err != nil {
+		return err
+	}
+	if att.mode != "" {
+		a.prepareAttachmentDownloads(ctx, att, client)
+	}
+
 	localIssues, err := loadLocalIssues(p)
 	if err != nil {
 		return err
@@ -97,7 +112,8 @@ func (a *App) Pull(ctx context.Context, opts PullOptions, args []string) error {
 		// We use "all" state for incremental sync to catch issues that were closed
 		var since time.Time
 		isIncremental := false
-		if cfg.Sync.LastFullPull != nil && !opts.All && !opts.Full && len(opts.Label) == 0 {
+		// Switching attachment modes needs every issue to be processed again
+		if cfg.Sync.LastFullPull != nil && !opts.All && !opts.Full && len(opts.Label) == 0 && !attachmentModeChanged {
 			since = *cfg.Sync.LastFullPull
 			isIncremental = true
 		}
@@ -166,6 +182,9 @@ func (a *App) Pull(ctx context.Context, opts PullOptions, args []string) error {
 				return err
 			}
 			fmt.Fprintf(a.Out, "%s\n", t.MutedText("Nothing to pull: no issues updated since last sync"))
+			if opts.Timeline {
+				a.fetchTimelines(ctx, p, client, timelineTargets(p, nil, localIssues))
+			}
 			return nil
 		}
 
@@ -201,6 +220,10 @@ func (a *App) Pull(ctx context.Context, opts PullOptions, args []string) error {
 	for _, remote := range remoteIssues {
 		remote.State = strings.ToLower(remote.State)
 		remote.SyncedAt = ptrTime(a.Now().UTC())
+		if att.mode != "" {
+			a.fetchAttachments(ctx, att, remote.Number.String(), remote.Body)
+		}
+		remote.Body = att.localBody(remote.Number.String(), remote.Body)
 
 		local, hasLocal := localByNumber[remote.Number.String()]
 		original, hasOriginal := readOriginalIssue(p, remote.Number.String())
@@ -225,7 +248,12 @@ func (a *App) Pull(ctx context.Context, opts PullOptions, args []string) error {
 		newPath := issue.PathFor(targetDir, remote.Number, remote.Title)
 		contentChanged := !hasLocal || !issue.EqualIgnoringSyncedAt(local.Issue, remote)
 		pathChanged := hasLocal && local.Path != newPath
-		if hasOriginal && !contentChanged && !pathChanged {
+		// Linked pull requests are informational and not part of the
+		// comparison, but a new or merged PR is worth a rewrite
+		linksChanged := hasLocal && !issue.LinkedPRsEqual(local.Issue.LinkedPRs, remote.LinkedPRs)
+		// So are updated_at and closed_at; they are refreshed quietly
+		timesChanged := hasLocal && !issue.TimestampsEqual(local.Issue, remote)
+		if hasOriginal && !contentChanged && !pathChanged && !linksChanged && !timesChanged {
 			unchanged++
 			continue
 		}
@@ -235,20 +263,27 @@ func (a *App) Pull(ctx context.Context, opts PullOptions, args []string) error {
 				return err
 			}
 		}
-		if err := issue.WriteFile(newPath, remote); err != nil {
+		if err := issue.WriteFile(newPath, withLocalState(remote, local.Issue)); err != nil {
 			return err
 		}
-		if err := writeOriginalIssue(p, remote); err != nil {
+		if err := a.replaceOriginal(p, remote, "pull"); err != nil {
 			return err
 		}
 		if !hasLocal {
 			fmt.Fprintln(a.Out, t.FormatIssueHeader("A", remote.Number.String(), remote.Title))
 			continue
 		}
+		if hasOriginal && !contentChanged && !pathChanged && !linksChanged {
+			unchanged++
+			continue
+		}
 		lines := a.formatChangeLines(local.Issue, remote, labelColors)
 		if len(lines) == 0 && pathChanged {
 			lines = append(lines, t.FormatChange("file", fmt.Sprintf("%q", relPath(a.Root, local.Path)), fmt.Sprintf("%q", relPath(a.Root, newPath))))
 		}
+		if len(lines) == 0 && linksChanged {
+			lines = append(lines, t.FormatChange("linked_prs", formatLinkedPRRefs(local.Issue.LinkedPRs), formatLinkedPRRefs(remote.LinkedPRs)))
+		}
 		fmt.Fprintln(a.Out, t.FormatIssueHeader("U", remote.Number.String(), remote.Title))
 		for _, line := range lines {
 			fmt.Fprintln(a.Out, line)
@@ -384,6 +419,9 @@ func (a *App) Pull(ctx context.Context, opts PullOptions, args []string) error {
 			if err := saveProjectCache(p, projCache); err != nil {
 				fmt.Fprintf(a.Err, "%s saving project cache: %v\n", t.WarningText("Warning:"), err)
 			}
+			if err := a.refreshProjectFieldCache(ctx, client, p, entries, localIssues); err != nil {
+				fmt.Fprintf(a.Err, "%s fetching project fields: %v\n", t.WarningText("Warning:"), err)
+			}
 		}
 	}
 
@@ -401,16 +439,31 @@ func (a *App) Pull(ctx context.Context, opts PullOptions, args []string) error {
 
 	// Restore locally deleted issues (originals exist but no local file)
 	if len(args) == 0 {
-		if err := a.restoreDeletedIssues(ctx, p, client, labelColors); err != nil {
+		if err := a.restoreDeletedIssues(ctx, p, client, labelColors, att); err != nil {
+			return err
+		}
+	}
+
+	if opts.Timeline {
+		var local []IssueFile
+		if len(args) == 0 {
+			local = localIssues
+		}
+		a.fetchTimelines(ctx, p, client, timelineTargets(p, remoteIssues, local))
+	}
+
+	if att.downloaded > 0 {
+		if err := att.store.Save(); err != nil {
 			return err
 		}
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText(fmt.Sprintf("Downloaded %s to %s", pluralize(att.downloaded, "attachment"), relPath(a.Root, p.AssetsDir))))
 	}
 
 	return nil
 }
 
 // restoreDeletedIssues finds issues that have originals but no local file and restores them
-func (a *App) restoreDeletedIssues(ctx context.Context, p paths.Paths, client *ghcli.Client, labelColors map[string]string) error {
+func (a *App) restoreDeletedIssues(ctx context.Context, p paths.Paths, client *ghcli.Client, labelColors map[string]string, att *attachments) error {
 	t := a.Theme
 
 	// List all originals
@@ -465,6 +518,10 @@ func (a *App) restoreDeletedIssues(ctx context.Context, p paths.Paths, client *g
 
 		remote.State = strings.ToLower(remote.State)
 		remote.SyncedAt = ptrTime(a.Now().UTC())
+		if att.mode != "" {
+			a.fetchAttachments(ctx, att, number, remote.Body)
+		}
+		remote.Body = att.localBody(remote.Number.String(), remote.Body)
 
 		targetDir := p.OpenDir
 		if remote.State == "closed" {
@@ -475,7 +532,7 @@ func (a *App) restoreDeletedIssues(ctx context.Context, p paths.Paths, client *g
 		if err := issue.WriteFile(newPath, remote); err != nil {
 			return err
 		}
-		if err := writeOriginalIssue(p, remote); err != nil {
+		if err := a.replaceOriginal(p, remote, "pull"); err != nil {
 			return err
 		}
 
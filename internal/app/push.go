@@ -3,13 +3,13 @@ package app
 import (
 	"context"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
 
 	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
 	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
 	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/oplog"
 	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
 )
 
@@ -27,9 +27,39 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 	}
 	defer lck.Release()
 
+	var rec *opRecorder
+	if !opts.DryRun {
+		rec = a.beginOperation(p, "push")
+		defer a.finishOperation(p, rec)
+	}
+
 	client := ghcli.NewClient(a.Runner, repoSlug(cfg))
 	t := a.Theme
 
+	// An interrupted push has to be finished first, otherwise the issues it
+	// created would be created again.
+	journal, err := loadPushJournal(p)
+	if err != nil {
+		return err
+	}
+	recovered := map[string]string{}
+	if opts.DryRun {
+		if len(journal.Entries) > 0 {
+			fmt.Fprintf(a.Out, "%s %s\n", t.MutedText("Would resume interrupted push"),
+				t.MutedText(fmt.Sprintf("(%s)", pluralize(len(journal.Entries), "operation"))))
+		}
+	} else if recovered, err = a.recoverPush(ctx, client, p, journal); err != nil {
+		return err
+	}
+
+	// Local bodies may point at downloaded attachments or at local images;
+	// GitHub must see the attachment URLs and uploaded copies instead.
+	att, err := loadAttachments(p, cfg)
+	if err != nil {
+		return err
+	}
+	att.upload = att.upload && !opts.NoUploads
+
 	// Load label cache (or fetch from remote if not cached)
 	labelCache, err := loadLabelCache(p)
 	if err != nil {
@@ -119,6 +149,10 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 			projectCache.SyncedAt = a.Now().UTC()
 		}
 	}
+	projectFieldCache, err := loadProjectFieldCache(p)
+	if err != nil {
+		fmt.Fprintf(a.Err, "%s loading project field cache: %v\n", t.WarningText("Warning:"), err)
+	}
 
 	localIssues, err := loadLocalIssues(p)
 	if err != nil {
@@ -200,6 +234,9 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 		}
 		for _, item := range newIssues {
 			fmt.Fprintf(a.Out, "%s %s\n", t.MutedText("Would create issue"), item.Issue.Title)
+			for _, file := range a.pendingUploads(att, item.Issue.Number.String(), item.Path, item.Issue.Body) {
+				fmt.Fprintf(a.Out, "%s %s\n", t.MutedText("Would upload"), relPath(a.Root, file))
+			}
 		}
 		unchanged := 0
 		for i := range filteredIssues {
@@ -214,6 +251,11 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 				continue
 			}
 			fmt.Fprintf(a.Out, "%s %s\n", t.MutedText("Would push issue"), t.AccentText("#"+item.Issue.Number.String()))
+			if !hasOriginal || item.Issue.Body != original.Body {
+				for _, file := range a.pendingUploads(att, item.Issue.Number.String(), item.Path, item.Issue.Body) {
+					fmt.Fprintf(a.Out, "%s %s\n", t.MutedText("Would upload"), relPath(a.Root, file))
+				}
+			}
 		}
 		for _, comment := range commentsToPost {
 			fmt.Fprintf(a.Out, "%s #%s\n", t.MutedText("Would post comment to"), comment.IssueNumber.String())
@@ -290,37 +332,90 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 	progress.SetPhase("Creating issues")
 	mapping := map[string]string{}
 	createdNumbers := map[string]struct{}{}
+	for oldNumber, newNumber := range recovered {
+		mapping[oldNumber] = newNumber
+		createdNumbers[newNumber] = struct{}{}
+	}
 	for _, item := range newIssues {
-		newNumber, err := client.CreateIssue(ctx, item.Issue)
+		oldNumber := item.Issue.Number.String()
+
+		// The issue may exist already if a teammate pushed a copy of it
+		// (issues created by an interrupted push are recovered from the
+		// journal). The search is best effort: if it fails, for example
+		// because of rate limits, the issue is created anyway.
+		existing, found, err := findCreatedIssue(ctx, client, item.Issue.Number)
 		if err != nil {
-			progress.Done()
-			return err
+			progress.Log(fmt.Sprintf("%s looking for an existing issue for %s: %v", t.WarningText("Warning:"), oldNumber, err))
 		}
-		oldNumber := item.Issue.Number.String()
-		mapping[oldNumber] = newNumber
-		createdNumbers[newNumber] = struct{}{}
-		item.Issue.Number = issue.IssueNumber(newNumber)
-		item.Issue.SyncedAt = ptrTime(a.Now().UTC())
-		newPath := issue.PathFor(dirForState(p, item.State), item.Issue.Number, item.Issue.Title)
-		if item.Path != newPath {
-			if err := os.Rename(item.Path, newPath); err != nil {
+		if found {
+			number := existing.Number.String()
+			mapping[oldNumber] = number
+			if err := a.finalizeCreatedIssue(p, item, number); err != nil {
+				progress.Done()
+				return err
+			}
+			// The remote issue is the baseline, so local differences are
+			// pushed as regular updates.
+			existing.Body = att.localBody(oldNumber, existing.Body)
+			if err := writeOriginalIssue(p, existing); err != nil {
 				progress.Done()
 				return err
 			}
-			item.Path = newPath
+			progress.Log(fmt.Sprintf("%s %s %s", t.MutedText("Found existing issue for"), oldNumber, t.AccentText("#"+number)))
+			progress.Advance()
+			continue
+		}
+
+		outgoing := item.Issue
+		outgoing.Body, err = a.pushBody(ctx, att, client, oldNumber, item.Path, outgoing.Body)
+		if err != nil {
+			progress.Done()
+			return err
+		}
+		outgoing.Body = issue.WithCreationMarker(outgoing.Body, item.Issue.Number)
+		entryPath := journalPath(p, item.Path)
+		if err := journal.begin(journalEntry{
+			Op:       journalCreate,
+			Issue:    item.Issue.Number.String(),
+			Path:     entryPath,
+			Title:    outgoing.Title,
+			BodyHash: journalBodyHash(outgoing.Body),
+		}, a.Now().UTC()); err != nil {
+			progress.Done()
+			return fmt.Errorf("failed to write push journal: %w", err)
 		}
-		if err := issue.WriteFile(item.Path, item.Issue); err != nil {
+		newNumber, err := client.CreateIssue(ctx, outgoing)
+		if err != nil {
 			progress.Done()
 			return err
 		}
-		if err := writeOriginalIssue(p, item.Issue); err != nil {
+		if err := journal.applied(journalCreate, entryPath, newNumber); err != nil {
+			progress.Done()
+			return fmt.Errorf("failed to write push journal: %w", err)
+		}
+		rec.remote(oplog.RemoteChange{Number: newNumber, Created: true})
+		mapping[oldNumber] = newNumber
+		createdNumbers[newNumber] = struct{}{}
+		if err := a.finalizeCreatedIssue(p, item, newNumber); err != nil {
 			progress.Done()
 			return err
 		}
+		if err := journal.finish(journalCreate, entryPath); err != nil {
+			progress.Done()
+			return fmt.Errorf("failed to write push journal: %w", err)
+		}
 		progress.Log(t.FormatIssueHeader("A", newNumber, item.Issue.Title))
 		progress.Advance()
 	}
 
+	// Remember local IDs so references to them keep resolving
+	if err := recordIDMapping(p, mapping); err != nil {
+		progress.Log(fmt.Sprintf("%s saving ID map: %v", t.WarningText("Warning:"), err))
+	}
+	if att.store.RenameUploads(mapping) {
+		att.recorded = true
+	}
+
 	// Update references in all issues if we created new ones
 	if len(mapping) > 0 {
 		allIssues, err := loadLocalIssues(p)
@@ -380,12 +475,26 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 								t.WarningText("Warning:"), number, err))
 						}
 					}
+					if err := a.syncProjectFields(ctx, client, p, number, nil, item.Issue.ProjectFields, nil, knownProjects, &projectFieldCache); err != nil {
+						progress.Log(fmt.Sprintf("%s setting project fields for #%s: %v",
+							t.WarningText("Warning:"), number, err))
+					}
 					break
 				}
 			}
 		}
 	}
 
+	// Rewrite local references in the rest of the working tree
+	changes, err := a.pushCodeRefs(ctx, p, cfg, opts, mapping)
+	for _, change := range changes {
+		progress.Log(fmt.Sprintf("%s %s %s", t.MutedText("Updated references in"), relPath(a.Root, change.Path),
+			t.MutedText(fmt.Sprintf("(%s)", pluralize(change.Count, "reference")))))
+	}
+	if err != nil {
+		progress.Log(fmt.Sprintf("%s rewriting references in source files: %v", t.WarningText("Warning:"), err))
+	}
+
 	// Now count issues that need updating (after reference mapping)
 	progress.SetPhase("Updating issues")
 	type pendingUpdate struct {
@@ -425,6 +534,7 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 
 	// Batch fetch remote issues for conflict detection
 	var remoteIssues map[string]issue.Issue
+	markers := map[string]issue.IssueNumber{}
 	if len(issueNumbersToFetch) > 0 {
 		var err error
 		remoteIssues, err = client.GetIssuesBatch(ctx, issueNumbersToFetch)
@@ -432,6 +542,13 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 			progress.Done()
 			return fmt.Errorf("failed to fetch remote issues: %w", err)
 		}
+		for num, remote := range remoteIssues {
+			if id, ok := issue.CreationMarkerID(remote.Body); ok {
+				markers[num] = id
+			}
+			remote.Body = att.localBody(num, remote.Body)
+			remoteIssues[num] = remote
+		}
 	}
 
 	// Detect conflicts and compute changes
@@ -446,6 +563,7 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 	type postBatchWork struct {
 		Item     *IssueFile
 		Original issue.Issue
+		Remote   issue.Issue
 		Change   ghcli.IssueChange
 	}
 	var postBatchWorks []postBatchWork
@@ -479,12 +597,12 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 
 			if mergeResult.LocalChanges.IsEmpty() {
 				// No local changes - just update original to match remote
-				if err := writeOriginalIssue(p, remote); err != nil {
+				if err := a.replaceOriginal(p, remote, "push"); err != nil {
 					progress.Log(fmt.Sprintf("%s updating original for #%s: %v", t.WarningText("Warning:"), numStr, err))
 				}
 				// Update local file with remote changes
 				remote.SyncedAt = ptrTime(a.Now().UTC())
-				if err := issue.WriteFile(pu.Item.Path, remote); err != nil {
+				if err := issue.WriteFile(pu.Item.Path, withLocalState(remote, pu.Item.Issue)); err != nil {
 					progress.Log(fmt.Sprintf("%s updating local file for #%s: %v", t.WarningText("Warning:"), numStr, err))
 				}
 				unchanged++
@@ -529,7 +647,15 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 				update.Title = change.Title
 			}
 			if change.Body != nil {
-				update.Body = change.Body
+				body, err := a.pushBody(ctx, att, client, numStr, pu.Item.Path, *change.Body)
+				if err != nil {
+					progress.Done()
+					return err
+				}
+				if id, ok := markers[numStr]; ok {
+					body = issue.WithCreationMarker(body, id)
+				}
+				update.Body = &body
 			}
 			if change.Milestone != nil {
 				update.Milestone = change.Milestone
@@ -554,11 +680,13 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 		postBatchWorks = append(postBatchWorks, postBatchWork{
 			Item:     pu.Item,
 			Original: pu.Original,
+			Remote:   remote,
 			Change:   change,
 		})
 	}
 
 	// Execute batch update
+	batchErrors := map[string]string{}
 	if len(batchUpdates) > 0 {
 		result, err := client.BatchEditIssues(ctx, batchUpdates)
 		if err != nil {
@@ -568,11 +696,15 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 		for num, errMsg := range result.Errors {
 			progress.Log(fmt.Sprintf("%s updating #%s: %s", t.WarningText("Warning:"), num, errMsg))
 		}
+		batchErrors = result.Errors
 	}
 
 	// Handle post-batch work and finalize
 	for _, work := range postBatchWorks {
 		numStr := work.Item.Issue.Number.String()
+		if _, failed := batchErrors[numStr]; !failed {
+			rec.remote(oplog.RemoteChange{Number: numStr, Before: issueState(work.Remote), After: issueState(work.Item.Issue)})
+		}
 
 		// Sync issue type via GraphQL (if changed)
 		if work.Change.IssueType != nil {
@@ -611,12 +743,18 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 			}
 		}
 
+		// Sync project field values via GraphQL (if changed)
+		if err := a.syncProjectFields(ctx, client, p, numStr, work.Original.ProjectFields, work.Item.Issue.ProjectFields, work.Remote.ProjectFields, knownProjects, &projectFieldCache); err != nil {
+			progress.Log(fmt.Sprintf("%s setting project fields for #%s: %v",
+				t.WarningText("Warning:"), numStr, err))
+		}
+
 		work.Item.Issue.SyncedAt = ptrTime(a.Now().UTC())
 		if err := issue.WriteFile(work.Item.Path, work.Item.Issue); err != nil {
 			progress.Done()
 			return err
 		}
-		if err := writeOriginalIssue(p, work.Item.Issue); err != nil {
+		if err := a.replaceOriginal(p, work.Item.Issue, "push"); err != nil {
 			progress.Done()
 			return err
 		}
@@ -659,14 +797,33 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 			continue
 		}
 
+		// A failed post stays journaled as pending: the comment may have
+		// been posted anyway, so the next push checks before reposting.
+		entryPath := journalPath(p, comment.Path)
+		if err := journal.begin(journalEntry{
+			Op:       journalComment,
+			Issue:    numStr,
+			Path:     entryPath,
+			BodyHash: journalBodyHash(comment.Body),
+		}, a.Now().UTC()); err != nil {
+			progress.Done()
+			return fmt.Errorf("failed to write push journal: %w", err)
+		}
 		if err := client.CreateComment(ctx, numStr, comment.Body); err != nil {
 			progress.Log(fmt.Sprintf("%s posting comment to #%s: %v", t.WarningText("Warning:"), numStr, err))
 			progress.Advance()
 			continue
 		}
+		if err := journal.applied(journalComment, entryPath, ""); err != nil {
+			progress.Done()
+			return fmt.Errorf("failed to write push journal: %w", err)
+		}
 
 		if err := deletePendingComment(comment); err != nil {
 			progress.Log(fmt.Sprintf("%s removing comment file %s: %v", t.WarningText("Warning:"), relPath(a.Root, comment.Path), err))
+		} else if err := journal.finish(journalComment, entryPath); err != nil {
+			progress.Done()
+			return fmt.Errorf("failed to write push journal: %w", err)
 		}
 
 		progress.Log(fmt.Sprintf("%s #%s", t.SuccessText("Posted comment to"), numStr))
@@ -676,6 +833,15 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 	// Done with progress bar
 	progress.Done()
 
+	if att.recorded {
+		if err := att.store.Save(); err != nil {
+			fmt.Fprintf(a.Err, "%s saving asset manifest: %v\n", t.WarningText("Warning:"), err)
+		}
+	}
+	if att.uploaded > 0 {
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText(fmt.Sprintf("Uploaded %s to branch %s", pluralize(att.uploaded, "image"), att.branch)))
+	}
+
 	// Print final messages
 	if len(autoMerged) > 0 {
 		sort.Strings(autoMerged)
@@ -711,3 +877,18 @@ func (a *App) Push(ctx context.Context, opts PushOptions, args []string) error {
 
 	return nil
 }
+
+// findCreatedIssue looks for an issue on GitHub carrying the creation marker
+// of localID.
+func findCreatedIssue(ctx context.Context, client *ghcli.Client, localID issue.IssueNumber) (issue.Issue, bool, error) {
+	results, err := client.SearchIssues(ctx, localID.String()+" in:body")
+	if err != nil {
+		return issue.Issue{}, false, err
+	}
+	for _, result := range results {
+		if id, ok := issue.CreationMarkerID(result.Body); ok && id == localID {
+			return result, true, nil
+		}
+	}
+	return issue.Issue{}, false, nil
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// markerRunner serves issue search results and records created issues.
+type markerRunner struct {
+	found   string
+	creates [][]string
+}
+
+func (r *markerRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	switch {
+	case strings.HasPrefix(joined, "issue list") && strings.Contains(joined, "--search"):
+		return r.found, nil
+	case strings.HasPrefix(joined, "issue create"):
+		r.creates = append(r.creates, args)
+		return "https://github.com/owner/repo/issues/12", nil
+	}
+	return "", errors.New("unexpected call: " + joined)
+}
+
+func setupMarkerPush(t *testing.T) (string, paths.Paths) {
+	t.Helper()
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	local := issue.Issue{Number: "Tab12cd34", Title: "Shared", Body: "Details", State: "open"}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "Tab12cd34-shared.md"), local); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	return root, p
+}
+
+func TestPushEmbedsCreationMarker(t *testing.T) {
+	root, p := setupMarkerPush(t)
+	runner := &markerRunner{found: "[]"}
+	var out bytes.Buffer
+	if err := New(root, runner, &out, &out).Push(context.Background(), PushOptions{}, nil); err != nil {
+		t.Fatalf("push: %v\n%s", err, out.String())
+	}
+	if len(runner.creates) != 1 {
+		t.Fatalf("expected one create, got %v", runner.creates)
+	}
+	if !strings.Contains(strings.Join(runner.creates[0], " "), "<!-- gh-issue-sync:local-id=Tab12cd34 -->") {
+		t.Fatalf("expected creation marker in body, got %v", runner.creates[0])
+	}
+	created, err := issue.ParseFile(filepath.Join(p.OpenDir, "12-shared.md"))
+	if err != nil {
+		t.Fatalf("expected renamed issue: %v", err)
+	}
+	if strings.Contains(created.Body, "gh-issue-sync") {
+		t.Fatalf("expected local body without marker, got %q", created.Body)
+	}
+}
+
+func TestPushAdoptsIssueWithCreationMarker(t *testing.T) {
+	root, p := setupMarkerPush(t)
+	runner := &markerRunner{found: `[
+		{"number":4,"title":"Shared","body":"Mentions Tab12cd34 in passing","state":"OPEN"},
+		{"number":9,"title":"Shared","body":"Details\n\n<!-- gh-issue-sync:local-id=Tab12cd34 -->","state":"OPEN"}
+	]`}
+	var out bytes.Buffer
+	if err := New(root, runner, &out, &out).Push(context.Background(), PushOptions{}, nil); err != nil {
+		t.Fatalf("push: %v\n%s", err, out.String())
+	}
+	if len(runner.creates) != 0 {
+		t.Fatalf("expected no duplicate to be created, got %v", runner.creates)
+	}
+	if !strings.Contains(stripAnsi(out.String()), "Found existing issue for Tab12cd34 #9") {
+		t.Fatalf("expected adoption message, got %q", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(p.OpenDir, "9-shared.md")); err != nil {
+		t.Fatalf("expected issue to be renamed: %v", err)
+	}
+	original, ok := readOriginalIssue(p, "9")
+	if !ok || strings.Contains(original.Body, "gh-issue-sync") {
+		t.Fatalf("expected original without marker, got %q", original.Body)
+	}
+}
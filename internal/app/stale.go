@@ -0,0 +1,268 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+const (
+	defaultStaleLabel       = "stale"
+	defaultStaleCloseReason = "not_planned"
+)
+
+// staleAction is a change a stale policy makes to an issue: marking it
+// stale, or closing it once it stayed stale.
+type staleAction struct {
+	Item    IssueFile
+	Policy  string
+	Updated issue.Issue
+	Close   bool
+	Comment string
+}
+
+// stalePolicyMatches reports whether policy applies to iss.
+func stalePolicyMatches(policy config.StalePolicy, iss issue.Issue) bool {
+	for _, label := range iss.Labels {
+		if containsIgnoreCase(policy.ExemptLabels, label) {
+			return false
+		}
+	}
+	if len(policy.Labels) == 0 {
+		return true
+	}
+	for _, label := range iss.Labels {
+		if containsIgnoreCase(policy.Labels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastActivity returns when iss was last updated on GitHub.
+func lastActivity(iss issue.Issue) *time.Time {
+	if iss.UpdatedAt != nil {
+		return iss.UpdatedAt
+	}
+	return iss.CreatedAt
+}
+
+// labeledAt returns when label was pushed or pulled onto the issue: the sync
+// time of the oldest version in the latest run of synced versions carrying
+// it. It reports false if the synced issue does not have the label.
+func labeledAt(p paths.Paths, number, label string) (time.Time, bool) {
+	versions, err := issueVersions(p, number)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var at time.Time
+	found := false
+	for i := len(versions) - 1; i >= 0; i-- {
+		synced := versions[i].Issue
+		if !containsIgnoreCase(synced.Labels, label) {
+			break
+		}
+		found = true
+		if synced.SyncedAt != nil {
+			at = *synced.SyncedAt
+		}
+	}
+	return at, found
+}
+
+func stalePolicyName(policy config.StalePolicy, index int) string {
+	if policy.Name != "" {
+		return policy.Name
+	}
+	return fmt.Sprintf("policy %d", index+1)
+}
+
+// evaluateStale returns the actions the policies take on issues as of now.
+// Open issues inactive for a policy's days get its label and comment. Issues
+// whose label was already pushed are closed after close_after_days more days
+// without an update, counted from when the label was synced since the local
+// updated_at only moves on the next pull; issues marked stale locally wait
+// for the next push.
+func evaluateStale(p paths.Paths, policies []config.StalePolicy, issues []IssueFile, now time.Time) []staleAction {
+	var actions []staleAction
+	for _, item := range issues {
+		iss := item.Issue
+		if item.State == "closed" || iss.Number.IsLocal() {
+			continue
+		}
+		last := lastActivity(iss)
+		if last == nil {
+			continue
+		}
+		idle := now.Sub(*last)
+		for i, policy := range policies {
+			if policy.Days <= 0 || !stalePolicyMatches(policy, iss) {
+				continue
+			}
+			label := policy.Label
+			if label == "" {
+				label = defaultStaleLabel
+			}
+			action := staleAction{Item: item, Policy: stalePolicyName(policy, i), Updated: iss}
+			if !containsIgnoreCase(iss.Labels, label) {
+				if idle < time.Duration(policy.Days)*24*time.Hour {
+					break
+				}
+				action.Updated.Labels = append(append([]string(nil), iss.Labels...), label)
+				action.Comment = policy.Comment
+				if action.Comment == "" {
+					action.Comment = defaultStaleComment(policy)
+				}
+				actions = append(actions, action)
+				break
+			}
+			if policy.CloseAfterDays <= 0 {
+				break
+			}
+			since, ok := labeledAt(p, iss.Number.String(), label)
+			if !ok {
+				break
+			}
+			if last.After(since) {
+				since = *last
+			}
+			if now.Sub(since) < time.Duration(policy.CloseAfterDays)*24*time.Hour {
+				break
+			}
+			reason := policy.CloseReason
+			if reason == "" {
+				reason = defaultStaleCloseReason
+			}
+			action.Updated.State = "closed"
+			action.Updated.StateReason = &reason
+			action.Close = true
+			action.Comment = policy.CloseComment
+			actions = append(actions, action)
+			break
+		}
+	}
+	return actions
+}
+
+func defaultStaleComment(policy config.StalePolicy) string {
+	comment := fmt.Sprintf("This issue has had no activity for %d days and has been marked as stale.", policy.Days)
+	if policy.CloseAfterDays > 0 {
+		comment += fmt.Sprintf(" It will be closed in %d days unless there is new activity.", policy.CloseAfterDays)
+	}
+	return comment
+}
+
+// applyStaleAction writes the updated issue, moving it to the closed
+// directory along with its pending comment when it is closed, and appends
+// the action's comment to the pending comment.
+func applyStaleAction(p paths.Paths, action staleAction) error {
+	file := action.Item
+	commentPath := pendingCommentPath(p, file)
+	pending, _ := os.ReadFile(commentPath)
+
+	file.Issue = action.Updated
+	if action.Close {
+		newPath := issue.PathFor(p.ClosedDir, file.Issue.Number, file.Issue.Title)
+		if err := os.Rename(file.Path, newPath); err != nil {
+			return err
+		}
+		file.Path = newPath
+		file.State = "closed"
+		// Keep the pending comment next to the issue
+		if len(pending) > 0 {
+			moved := filepath.Join(p.ClosedDir, filepath.Base(commentPath))
+			if err := os.Rename(commentPath, moved); err != nil {
+				return err
+			}
+			commentPath = moved
+		} else {
+			commentPath = pendingCommentPath(p, file)
+		}
+	}
+	if err := issue.WriteFile(file.Path, file.Issue); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(action.Comment) == "" {
+		return nil
+	}
+	body := strings.TrimSpace(action.Comment) + "\n"
+	if strings.TrimSpace(string(pending)) != "" {
+		body = strings.TrimSpace(string(pending)) + "\n\n" + body
+	}
+	return os.WriteFile(commentPath, []byte(body), 0o644)
+}
+
+// Stale evaluates the stale policies from the config against the local
+// issues and stages the resulting labels, comments and closes for the next
+// push. With DryRun it only shows them.
+func (a *App) Stale(ctx context.Context, opts StaleOptions) error {
+	p := paths.New(a.Root)
+	cfg, err := loadConfig(p.ConfigPath)
+	if err != nil {
+		return err
+	}
+	t := a.Theme
+	if len(cfg.Stale.Policies) == 0 {
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText("No stale policies configured (stale.policies in .issues/.sync/config.json)"))
+		return nil
+	}
+
+	// Acquire lock
+	lck, err := lock.Acquire(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+
+	issues, err := loadLocalIssues(p)
+	if err != nil {
+		return err
+	}
+	labelCache, _ := loadLabelCache(p)
+	labelColors := labelCacheToColorMap(labelCache)
+
+	marked, closed := 0, 0
+	for _, action := range evaluateStale(p, cfg.Stale.Policies, issues, a.Now()) {
+		iss := action.Item.Issue
+		fmt.Fprintf(a.Out, "%s %s\n", t.FormatIssueHeader("M", iss.Number.String(), iss.Title), t.MutedText("("+action.Policy+")"))
+		for _, line := range a.formatChangeLines(iss, action.Updated, labelColors) {
+			fmt.Fprintln(a.Out, line)
+		}
+		if comment := strings.TrimSpace(action.Comment); comment != "" {
+			if line, _, more := strings.Cut(comment, "\n"); more || len([]rune(line)) > 60 {
+				comment = string([]rune(line)[:min(len([]rune(line)), 60)]) + "…"
+			}
+			fmt.Fprintf(a.Out, "    %s %s\n", t.MutedText("comment:"), comment)
+		}
+		if action.Close {
+			closed++
+		} else {
+			marked++
+		}
+		if opts.DryRun {
+			continue
+		}
+		if err := applyStaleAction(p, action); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case marked+closed == 0:
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText("No stale issues"))
+	case opts.DryRun:
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText(fmt.Sprintf("Would mark %s stale and close %d", pluralize(marked, "issue"), closed)))
+	default:
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText(fmt.Sprintf("Marked %s stale and closed %d; push to sync", pluralize(marked, "issue"), closed)))
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestStale(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	cfg := config.Default("owner", "repo")
+	cfg.Stale.Policies = []config.StalePolicy{
+		{Name: "old bugs", Labels: []string{"bug"}, ExemptLabels: []string{"pinned"}, Days: 90, CloseAfterDays: 14, CloseComment: "Closing as stale."},
+	}
+	if err := config.Save(p.ConfigPath, cfg); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	ago := func(days int) *time.Time {
+		ts := now.AddDate(0, 0, -days)
+		return &ts
+	}
+	issues := []struct {
+		iss    issue.Issue
+		synced []string
+	}{
+		{issue.Issue{Number: "1", Title: "Old bug", Labels: []string{"bug"}, UpdatedAt: ago(100)}, []string{"bug"}},
+		{issue.Issue{Number: "2", Title: "Stale bug", Labels: []string{"bug", "stale"}, UpdatedAt: ago(20)}, []string{"bug", "stale"}},
+		{issue.Issue{Number: "3", Title: "Marked locally", Labels: []string{"bug", "stale"}, UpdatedAt: ago(100)}, []string{"bug"}},
+		{issue.Issue{Number: "4", Title: "Recent bug", Labels: []string{"bug"}, UpdatedAt: ago(10)}, []string{"bug"}},
+		{issue.Issue{Number: "5", Title: "Old idea", Labels: []string{"enhancement"}, UpdatedAt: ago(100)}, []string{"enhancement"}},
+		{issue.Issue{Number: "6", Title: "Pinned bug", Labels: []string{"bug", "pinned"}, UpdatedAt: ago(100)}, []string{"bug", "pinned"}},
+	}
+	for _, entry := range issues {
+		entry.iss.State = "open"
+		if err := issue.WriteFile(issue.PathFor(p.OpenDir, entry.iss.Number, entry.iss.Title), entry.iss); err != nil {
+			t.Fatalf("write issue: %v", err)
+		}
+		original := entry.iss
+		original.Labels = entry.synced
+		if err := writeOriginalIssue(p, original); err != nil {
+			t.Fatalf("write original: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(p.OpenDir, "2.comment.md"), []byte("Still broken?\n"), 0o644); err != nil {
+		t.Fatalf("write comment: %v", err)
+	}
+
+	var out bytes.Buffer
+	application := New(root, nil, &out, &out)
+	application.Now = func() time.Time { return now }
+
+	if err := application.Stale(context.Background(), StaleOptions{DryRun: true}); err != nil {
+		t.Fatalf("stale: %v", err)
+	}
+	text := stripAnsi(out.String())
+	for _, want := range []string{"Issue #1: Old bug (old bugs)", "labels: + stale", "Issue #2: Stale bug", `state: "open" -> "closed"`, "Would mark 1 issue stale and close 1"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output:\n%s", want, text)
+		}
+	}
+	for _, unexpected := range []string{"#3", "#4", "#5", "#6"} {
+		if strings.Contains(text, unexpected) {
+			t.Fatalf("unexpected %q in output:\n%s", unexpected, text)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(p.OpenDir, "1.comment.md")); !os.IsNotExist(err) {
+		t.Fatalf("dry run queued a comment")
+	}
+
+	out.Reset()
+	if err := application.Stale(context.Background(), StaleOptions{}); err != nil {
+		t.Fatalf("stale: %v", err)
+	}
+	marked, err := issue.ParseFile(issue.PathFor(p.OpenDir, "1", "Old bug"))
+	if err != nil {
+		t.Fatalf("read issue: %v", err)
+	}
+	if !slices.Contains(marked.Labels, "stale") {
+		t.Fatalf("expected stale label, got %v", marked.Labels)
+	}
+	comment, err := os.ReadFile(filepath.Join(p.OpenDir, "1.comment.md"))
+	if err != nil || !strings.Contains(string(comment), "no activity for 90 days") {
+		t.Fatalf("unexpected stale comment %q (%v)", comment, err)
+	}
+
+	closed, err := issue.ParseFile(issue.PathFor(p.ClosedDir, "2", "Stale bug"))
+	if err != nil {
+		t.Fatalf("expected closed issue: %v", err)
+	}
+	if closed.State != "closed" || closed.StateReason == nil || *closed.StateReason != "not_planned" {
+		t.Fatalf("unexpected closed issue %+v", closed)
+	}
+	comment, err = os.ReadFile(filepath.Join(p.ClosedDir, "2.comment.md"))
+	if err != nil || string(comment) != "Still broken?\n\nClosing as stale.\n" {
+		t.Fatalf("unexpected close comment %q (%v)", comment, err)
+	}
+
+	// Marked issues are not closed before the label was pushed
+	out.Reset()
+	application.Now = func() time.Time { return now.AddDate(0, 0, 30) }
+	if err := application.Stale(context.Background(), StaleOptions{DryRun: true}); err != nil {
+		t.Fatalf("stale: %v", err)
+	}
+	if text := stripAnsi(out.String()); !strings.Contains(text, "No stale issues") {
+		t.Fatalf("expected no actions:\n%s", text)
+	}
+}
+
+// stalePushRunner serves issue #1, a bug last updated 100 days ago, and
+// accepts the label edit and comment a push makes.
+type stalePushRunner struct {
+	calls []string
+}
+
+func (r *stalePushRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	r.calls = append(r.calls, joined)
+	switch {
+	case strings.HasPrefix(joined, "api repos/owner/repo/labels"):
+		return `{"name":"bug","color":"d73a4a"}` + "\n" + `{"name":"stale","color":"ededed"}`, nil
+	case strings.HasPrefix(joined, "api repos/"):
+		return "", nil
+	case strings.Contains(joined, "mutation"):
+		return `{"data":{"update0":{"issue":{"number":1}}}}`, nil
+	case strings.Contains(joined, "issue0: issue(number: 1) { id number }"):
+		return `{"data":{"repository":{"issue0":{"id":"I_1","number":1},"labels":{"nodes":[{"id":"L_1","name":"bug"},{"id":"L_2","name":"stale"}]}}}}`, nil
+	case strings.Contains(joined, "stateReason"):
+		return `{"data":{"repository":{"issue0":{"number":1,"title":"Old bug","body":"","state":"OPEN","updatedAt":"2026-07-10T00:00:00Z","labels":{"nodes":[{"name":"bug"}]}}}}}`, nil
+	case strings.Contains(joined, "parent {"):
+		return `{"data":{"repository":{"issue0":{"id":"I_1","number":1}}}}`, nil
+	case strings.HasPrefix(joined, "api graphql"):
+		return `{"data":{}}`, nil
+	case strings.HasPrefix(joined, "issue comment 1"):
+		return "https://github.com/owner/repo/issues/1#issuecomment-1", nil
+	}
+	return "", errors.New("unexpected call: " + joined)
+}
+
+func TestStaleWaitsAfterPush(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	cfg := config.Default("owner", "repo")
+	cfg.Stale.Policies = []config.StalePolicy{{Labels: []string{"bug"}, Days: 90, CloseAfterDays: 14}}
+	if err := config.Save(p.ConfigPath, cfg); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	updated := now.AddDate(0, 0, -100)
+	old := issue.Issue{Number: "1", Title: "Old bug", Labels: []string{"bug"}, State: "open", UpdatedAt: &updated, SyncedAt: &updated}
+	if err := issue.WriteFile(issue.PathFor(p.OpenDir, old.Number, old.Title), old); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	if err := writeOriginalIssue(p, old); err != nil {
+		t.Fatalf("write original: %v", err)
+	}
+
+	runner := &stalePushRunner{}
+	var out bytes.Buffer
+	application := New(root, runner, &out, &out)
+	application.Now = func() time.Time { return now }
+	if err := application.Stale(context.Background(), StaleOptions{}); err != nil {
+		t.Fatalf("stale: %v", err)
+	}
+	if err := application.Push(context.Background(), PushOptions{}, nil); err != nil {
+		t.Fatalf("push: %v\n%s\n%s", err, out.String(), strings.Join(runner.calls, "\n"))
+	}
+	if !strings.Contains(strings.Join(runner.calls, "\n"), "issue comment 1") {
+		t.Fatalf("expected the stale comment to be posted:\n%s", out.String())
+	}
+
+	// Updated_at still reads 100 days ago, but the label was only just added
+	out.Reset()
+	application.Now = func() time.Time { return now.AddDate(0, 0, 1) }
+	if err := application.Stale(context.Background(), StaleOptions{}); err != nil {
+		t.Fatalf("stale: %v", err)
+	}
+	if text := stripAnsi(out.String()); !strings.Contains(text, "No stale issues") {
+		t.Fatalf("expected no actions after the push:\n%s", text)
+	}
+	if _, err := os.Stat(issue.PathFor(p.OpenDir, old.Number, old.Title)); err != nil {
+		t.Fatalf("expected issue to stay open: %v", err)
+	}
+
+	out.Reset()
+	application.Now = func() time.Time { return now.AddDate(0, 0, 15) }
+	if err := application.Stale(context.Background(), StaleOptions{DryRun: true}); err != nil {
+		t.Fatalf("stale: %v", err)
+	}
+	if text := stripAnsi(out.String()); !strings.Contains(text, "Would mark 0 issues stale and close 1") {
+		t.Fatalf("expected the issue to close after close_after_days:\n%s", text)
+	}
+}
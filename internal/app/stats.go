@@ -0,0 +1,315 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// noneGroup collects issues without a label, milestone, assignee or type.
+const noneGroup = "(none)"
+
+// IssueStats summarizes the local issues for triage reports.
+type IssueStats struct {
+	Open        int            `json:"open"`
+	Closed      int            `json:"closed"`
+	ByLabel     []GroupStats   `json:"by_label"`
+	ByMilestone []GroupStats   `json:"by_milestone"`
+	ByAssignee  []GroupStats   `json:"by_assignee"`
+	ByType      []GroupStats   `json:"by_type"`
+	TimeToClose *CloseStats    `json:"time_to_close,omitempty"`
+	OpenAge     []AgeBucket    `json:"open_age"`
+	Throughput  []WeekActivity `json:"throughput"`
+}
+
+// GroupStats counts the issues of a label, milestone, assignee or type.
+type GroupStats struct {
+	Name   string `json:"name"`
+	Open   int    `json:"open"`
+	Closed int    `json:"closed"`
+}
+
+// CloseStats is the time it took to close issues, over the closed issues
+// whose creation and closing time are known.
+type CloseStats struct {
+	Issues      int     `json:"issues"`
+	MedianHours float64 `json:"median_hours"`
+}
+
+// AgeBucket counts open issues created within an age range.
+type AgeBucket struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// WeekActivity counts the issues opened and closed in the week starting at
+// Week (a Monday).
+type WeekActivity struct {
+	Week   string `json:"week"`
+	Opened int    `json:"opened"`
+	Closed int    `json:"closed"`
+}
+
+var ageBuckets = []struct {
+	name string
+	max  time.Duration
+}{
+	{"< 1 week", 7 * 24 * time.Hour},
+	{"1-4 weeks", 28 * 24 * time.Hour},
+	{"1-3 months", 91 * 24 * time.Hour},
+	{"3-12 months", 365 * 24 * time.Hour},
+	{"> 1 year", 0},
+}
+
+// computeStats summarizes issues as of now, with throughput for the last
+// weeks weeks.
+func computeStats(issues []IssueFile, now time.Time, weeks int) IssueStats {
+	var stats IssueStats
+	labels := map[string]*GroupStats{}
+	milestones := map[string]*GroupStats{}
+	assignees := map[string]*GroupStats{}
+	types := map[string]*GroupStats{}
+	count := func(groups map[string]*GroupStats, names []string, closed bool) {
+		if len(names) == 0 {
+			names = []string{noneGroup}
+		}
+		for _, name := range names {
+			group, ok := groups[name]
+			if !ok {
+				group = &GroupStats{Name: name}
+				groups[name] = group
+			}
+			if closed {
+				group.Closed++
+			} else {
+				group.Open++
+			}
+		}
+	}
+	optional := func(name string) []string {
+		if name == "" {
+			return nil
+		}
+		return []string{name}
+	}
+
+	for _, bucket := range ageBuckets {
+		stats.OpenAge = append(stats.OpenAge, AgeBucket{Name: bucket.name})
+	}
+	start := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+	stats.Throughput = make([]WeekActivity, weeks)
+	for i := range stats.Throughput {
+		stats.Throughput[i].Week = start.AddDate(0, 0, 7*i).Format("2006-01-02")
+	}
+	week := func(ts time.Time) int {
+		ts = ts.In(now.Location())
+		if ts.Before(start) || ts.After(now) {
+			return -1
+		}
+		// Rounded, days around DST changes are not 24 hours long
+		return int(math.Round(weekStart(ts).Sub(start).Hours()/24)) / 7
+	}
+
+	var hoursToClose []float64
+	for _, item := range issues {
+		iss := item.Issue
+		closed := item.State == "closed"
+		if closed {
+			stats.Closed++
+		} else {
+			stats.Open++
+		}
+		count(labels, iss.Labels, closed)
+		count(milestones, optional(iss.Milestone), closed)
+		count(assignees, iss.Assignees, closed)
+		count(types, optional(iss.IssueType), closed)
+
+		if iss.CreatedAt != nil {
+			if i := week(*iss.CreatedAt); i >= 0 {
+				stats.Throughput[i].Opened++
+			}
+		}
+		if closed && iss.ClosedAt != nil {
+			if i := week(*iss.ClosedAt); i >= 0 {
+				stats.Throughput[i].Closed++
+			}
+			if iss.CreatedAt != nil && !iss.ClosedAt.Before(*iss.CreatedAt) {
+				hoursToClose = append(hoursToClose, iss.ClosedAt.Sub(*iss.CreatedAt).Hours())
+			}
+		}
+		if !closed && iss.CreatedAt != nil {
+			age := now.Sub(*iss.CreatedAt)
+			for i, bucket := range ageBuckets {
+				if bucket.max == 0 || age < bucket.max {
+					stats.OpenAge[i].Count++
+					break
+				}
+			}
+		}
+	}
+
+	stats.ByLabel = sortedGroups(labels)
+	stats.ByMilestone = sortedGroups(milestones)
+	stats.ByAssignee = sortedGroups(assignees)
+	stats.ByType = sortedGroups(types)
+	if len(hoursToClose) > 0 {
+		stats.TimeToClose = &CloseStats{Issues: len(hoursToClose), MedianHours: median(hoursToClose)}
+	}
+	return stats
+}
+
+// sortedGroups orders groups by open issues, then by all issues, then by
+// name, with the group of issues without a value last.
+func sortedGroups(groups map[string]*GroupStats) []GroupStats {
+	sorted := make([]GroupStats, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, *group)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.Name == noneGroup) != (b.Name == noneGroup) {
+			return b.Name == noneGroup
+		}
+		if a.Open != b.Open {
+			return a.Open > b.Open
+		}
+		if a.Open+a.Closed != b.Open+b.Closed {
+			return a.Open+a.Closed > b.Open+b.Closed
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return sorted
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// weekStart returns the Monday starting the week of ts.
+func weekStart(ts time.Time) time.Time {
+	day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, ts.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// formatSpan renders a duration compactly, like "3d 4h" or "45m".
+func formatSpan(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	days := int(d.Hours() / 24)
+	if hours := int(d.Hours()) % 24; hours > 0 && days < 10 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dd", days)
+}
+
+// Stats reports issue counts, time to close, the age of open issues and
+// weekly throughput from the local files.
+func (a *App) Stats(ctx context.Context, opts StatsOptions) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+	weeks := opts.Weeks
+	if weeks <= 0 {
+		weeks = 8
+	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+
+	issues, err := loadLocalIssues(p)
+	if err != nil {
+		return err
+	}
+	fillClosedTimes(p, issues)
+	stats := computeStats(issues, a.Now(), weeks)
+
+	if opts.Format == "json" {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(a.Out, string(data))
+		return nil
+	}
+	a.printStats(stats)
+	return nil
+}
+
+func (a *App) printStats(stats IssueStats) {
+	t := a.Theme
+	fmt.Fprintf(a.Out, "%s %s open, %s closed\n", t.Bold("Issues:"),
+		t.SuccessText(fmt.Sprint(stats.Open)), t.MutedText(fmt.Sprint(stats.Closed)))
+
+	for _, table := range []struct {
+		title  string
+		groups []GroupStats
+	}{
+		{"Label", stats.ByLabel},
+		{"Milestone", stats.ByMilestone},
+		{"Assignee", stats.ByAssignee},
+		{"Type", stats.ByType},
+	} {
+		width := len(table.title)
+		for _, group := range table.groups {
+			width = max(width, len(group.Name))
+		}
+		fmt.Fprintf(a.Out, "\n%s  %6s  %6s\n", t.Bold(fmt.Sprintf("%-*s", width, table.title)), "Open", "Closed")
+		for _, group := range table.groups {
+			name := fmt.Sprintf("%-*s", width, group.Name)
+			if group.Name == noneGroup {
+				name = t.MutedText(name)
+			}
+			fmt.Fprintf(a.Out, "%s  %6d  %6d\n", name, group.Open, group.Closed)
+		}
+	}
+
+	fmt.Fprintln(a.Out)
+	if stats.TimeToClose == nil {
+		fmt.Fprintf(a.Out, "%s %s\n", t.Bold("Time to close:"), t.MutedText("unknown (pull closed issues with --all)"))
+	} else {
+		median := time.Duration(stats.TimeToClose.MedianHours * float64(time.Hour))
+		fmt.Fprintf(a.Out, "%s median %s %s\n", t.Bold("Time to close:"), formatSpan(median),
+			t.MutedText(fmt.Sprintf("(%s)", pluralize(stats.TimeToClose.Issues, "issue"))))
+	}
+
+	most := 0
+	for _, bucket := range stats.OpenAge {
+		most = max(most, bucket.Count)
+	}
+	fmt.Fprintf(a.Out, "\n%s\n", t.Bold("Age of open issues"))
+	for _, bucket := range stats.OpenAge {
+		bar := ""
+		if most > 0 {
+			bar = strings.Repeat("█", (bucket.Count*30+most-1)/most)
+		}
+		fmt.Fprintf(a.Out, "%-12s %4d  %s\n", bucket.Name, bucket.Count, t.AccentText(bar))
+	}
+
+	fmt.Fprintf(a.Out, "\n%s  %6s  %6s\n", t.Bold(fmt.Sprintf("%-10s", "Week")), "Opened", "Closed")
+	for _, week := range stats.Throughput {
+		fmt.Fprintf(a.Out, "%-10s  %6d  %6d\n", week.Week, week.Opened, week.Closed)
+	}
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestComputeStats(t *testing.T) {
+	// Sunday, so the current week started on Monday the 12th
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		ts := now.AddDate(0, 0, -days)
+		return &ts
+	}
+	issues := []IssueFile{
+		{State: "open", Issue: issue.Issue{Number: "1", Labels: []string{"bug"}, Assignees: []string{"alice"}, CreatedAt: at(2)}},
+		{State: "open", Issue: issue.Issue{Number: "2", Labels: []string{"bug", "ui"}, Milestone: "v1", CreatedAt: at(40)}},
+		{State: "open", Issue: issue.Issue{Number: "T1abc"}},
+		{State: "closed", Issue: issue.Issue{Number: "3", Labels: []string{"ui"}, IssueType: "Bug", CreatedAt: at(10), ClosedAt: at(8)}},
+		{State: "closed", Issue: issue.Issue{Number: "4", CreatedAt: at(9), ClosedAt: at(3)}},
+		{State: "closed", Issue: issue.Issue{Number: "5", CreatedAt: at(500), ClosedAt: at(400)}},
+	}
+	stats := computeStats(issues, now, 2)
+
+	if stats.Open != 3 || stats.Closed != 3 {
+		t.Fatalf("unexpected counts %d/%d", stats.Open, stats.Closed)
+	}
+	want := []GroupStats{{"bug", 2, 0}, {"ui", 1, 1}, {noneGroup, 1, 2}}
+	if len(stats.ByLabel) != len(want) {
+		t.Fatalf("unexpected label stats %+v", stats.ByLabel)
+	}
+	for i := range want {
+		if stats.ByLabel[i] != want[i] {
+			t.Fatalf("unexpected label stats %+v", stats.ByLabel)
+		}
+	}
+	if stats.ByType[0] != (GroupStats{"Bug", 0, 1}) || stats.ByAssignee[0] != (GroupStats{"alice", 1, 0}) {
+		t.Fatalf("unexpected type/assignee stats %+v %+v", stats.ByType, stats.ByAssignee)
+	}
+	// 2, 6 and 100 days
+	if stats.TimeToClose == nil || stats.TimeToClose.Issues != 3 || stats.TimeToClose.MedianHours != 6*24 {
+		t.Fatalf("unexpected time to close %+v", stats.TimeToClose)
+	}
+	if stats.OpenAge[0].Count != 1 || stats.OpenAge[2].Count != 1 {
+		t.Fatalf("unexpected open age %+v", stats.OpenAge)
+	}
+	wantWeeks := []WeekActivity{{"2026-10-05", 2, 1}, {"2026-10-12", 1, 1}}
+	for i := range wantWeeks {
+		if stats.Throughput[i] != wantWeeks[i] {
+			t.Fatalf("unexpected throughput %+v", stats.Throughput)
+		}
+	}
+}
+
+func TestStatsOutput(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	closed := created.Add(50 * time.Hour)
+	if err := issue.WriteFile(issue.PathFor(p.ClosedDir, "7", "Done"), issue.Issue{Number: "7", Title: "Done", State: "closed", CreatedAt: &created, ClosedAt: &closed}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	// Pulled before closing dates were recorded, closed according to the
+	// sync history
+	synced := created.Add(24 * time.Hour)
+	old := issue.Issue{Number: "8", Title: "Old", State: "closed", CreatedAt: &created, SyncedAt: &synced}
+	if err := issue.WriteFile(issue.PathFor(p.ClosedDir, "8", "Old"), old); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	if err := writeOriginalIssue(p, old); err != nil {
+		t.Fatalf("write original: %v", err)
+	}
+
+	var out bytes.Buffer
+	application := New(root, nil, &out, &out)
+	application.Now = func() time.Time { return time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC) }
+
+	if err := application.Stats(context.Background(), StatsOptions{}); err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	text := stripAnsi(out.String())
+	for _, want := range []string{"Issues: 0 open, 2 closed", "Time to close: median 1d 13h (2 issues)", "2026-10-12"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output:\n%s", want, text)
+		}
+	}
+
+	out.Reset()
+	if err := application.Stats(context.Background(), StatsOptions{Format: "json", Weeks: 3}); err != nil {
+		t.Fatalf("stats: %v", err)
+	}
+	var stats IssueStats
+	if err := json.Unmarshal(out.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, out.String())
+	}
+	if stats.Closed != 2 || len(stats.Throughput) != 3 || stats.Throughput[0].Opened != 2 || stats.Throughput[0].Closed != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
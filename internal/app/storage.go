@@ -145,6 +145,16 @@ func findIssueByNumber(p paths.Paths, number string) (IssueFile, error) {
 			return item, nil
 		}
 	}
+	// Local IDs keep working after the issue was pushed
+	if issue.IssueNumber(number).IsLocal() {
+		if ids, err := loadIDMap(p); err == nil && ids[number] != "" {
+			for _, item := range issues {
+				if item.Issue.Number.String() == ids[number] {
+					return item, nil
+				}
+			}
+		}
+	}
 	return IssueFile{}, fmt.Errorf("issue %s not found", number)
 }
 
@@ -175,6 +185,47 @@ func findIssueByRef(root string, p paths.Paths, ref string) (IssueFile, error) {
 	return findIssueByNumber(p, ref)
 }
 
+// loadIDMap reads the local IDs of pushed issues and the numbers GitHub
+// assigned to them.
+func loadIDMap(p paths.Paths) (map[string]string, error) {
+	ids := map[string]string{}
+	data, err := os.ReadFile(p.IDMapPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ids, nil
+		}
+		return ids, err
+	}
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return ids, fmt.Errorf("failed to parse %s: %w", paths.IDMapFileName, err)
+	}
+	return ids, nil
+}
+
+// recordIDMapping adds the numbers assigned to pushed local IDs to the ID map.
+func recordIDMapping(p paths.Paths, mapping map[string]string) error {
+	if len(mapping) == 0 {
+		return nil
+	}
+	ids, err := loadIDMap(p)
+	if err != nil {
+		return err
+	}
+	for local, number := range mapping {
+		ids[local] = number
+	}
+	return saveIDMap(p, ids)
+}
+
+func saveIDMap(p paths.Paths, ids map[string]string) error {
+	data, err := json.MarshalIndent(ids, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(p.IDMapPath, data, 0o644)
+}
+
 func readOriginalIssue(p paths.Paths, number string) (issue.Issue, bool) {
 	path := filepath.Join(p.OriginalsDir, fmt.Sprintf("%s.md", number))
 	parsed, err := issue.ParseFile(path)
@@ -332,3 +383,55 @@ func projectByTitle(cache ProjectCache) map[string]ProjectEntry {
 	}
 	return m
 }
+
+// ProjectFieldCache stores the custom field definitions of projects
+type ProjectFieldCache struct {
+	Projects []ProjectFieldsEntry `json:"projects"`
+	SyncedAt time.Time            `json:"synced_at"`
+}
+
+// ProjectFieldsEntry holds the field definitions of a single project
+type ProjectFieldsEntry struct {
+	ProjectID string              `json:"project_id"`
+	Title     string              `json:"title"`
+	Fields    []ProjectFieldEntry `json:"fields"`
+}
+
+// ProjectFieldEntry represents a single project field. Options lists the
+// choices of single-select fields and the iterations of iteration fields.
+type ProjectFieldEntry struct {
+	ID       string                    `json:"id"`
+	Name     string                    `json:"name"`
+	DataType string                    `json:"data_type"`
+	Options  []ProjectFieldOptionEntry `json:"options,omitempty"`
+}
+
+// ProjectFieldOptionEntry represents a single-select option or an iteration
+type ProjectFieldOptionEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func loadProjectFieldCache(p paths.Paths) (ProjectFieldCache, error) {
+	var cache ProjectFieldCache
+	data, err := os.ReadFile(p.ProjectFieldsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cache, nil
+		}
+		return cache, err
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, err
+	}
+	return cache, nil
+}
+
+func saveProjectFieldCache(p paths.Paths, cache ProjectFieldCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(p.ProjectFieldsPath, data, 0o644)
+}
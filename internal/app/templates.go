@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issuetemplate"
+)
+
+// loadTemplates reads the repository's issue templates, warning about the
+// ones that cannot be parsed.
+func (a *App) loadTemplates() ([]issuetemplate.Template, error) {
+	templates, skipped, err := issuetemplate.Load(filepath.Join(a.Root, filepath.FromSlash(issuetemplate.Dir)))
+	for _, err := range skipped {
+		fmt.Fprintf(a.Err, "%s skipping issue template %v\n", a.Theme.WarningText("Warning:"), err)
+	}
+	return templates, err
+}
+
+// findTemplate looks up an issue template by file name or display name.
+func (a *App) findTemplate(name string) (issuetemplate.Template, error) {
+	templates, err := a.loadTemplates()
+	if err != nil {
+		return issuetemplate.Template{}, err
+	}
+	if tmpl, ok := issuetemplate.Find(templates, name); ok {
+		return tmpl, nil
+	}
+	if len(templates) == 0 {
+		return issuetemplate.Template{}, fmt.Errorf("unknown template %q: no templates in %s", name, issuetemplate.Dir)
+	}
+	ids := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		ids = append(ids, tmpl.ID)
+	}
+	return issuetemplate.Template{}, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(ids, ", "))
+}
+
+func (a *App) listTemplates() error {
+	templates, err := a.loadTemplates()
+	if err != nil {
+		return err
+	}
+	t := a.Theme
+	if len(templates) == 0 {
+		fmt.Fprintf(a.Out, "%s\n", t.MutedText("No issue templates in "+issuetemplate.Dir))
+		return nil
+	}
+	width := 0
+	for _, tmpl := range templates {
+		width = max(width, len(tmpl.ID))
+	}
+	for _, tmpl := range templates {
+		line := t.AccentText(fmt.Sprintf("%-*s", width, tmpl.ID)) + "  " + tmpl.Name
+		if tmpl.About != "" {
+			line += " " + t.MutedText("- "+tmpl.About)
+		}
+		fmt.Fprintln(a.Out, line)
+	}
+	return nil
+}
+
+// applyTemplate fills in base from tmpl. The template title is used as a
+// prefix for the given title; labels are merged.
+func applyTemplate(base issue.Issue, tmpl issuetemplate.Template) issue.Issue {
+	base.Title = templateTitle(tmpl.Title, base.Title)
+	for _, label := range tmpl.Labels {
+		if !slices.Contains(base.Labels, label) {
+			base.Labels = append(base.Labels, label)
+		}
+	}
+	base.Assignees = append([]string(nil), tmpl.Assignees...)
+	base.Body = tmpl.Body
+	return base
+}
+
+// templateTitle combines a template title such as "[Bug]: " with the title
+// given on the command line.
+func templateTitle(prefix, title string) string {
+	switch {
+	case strings.TrimSpace(prefix) == "":
+		return title
+	case title == "":
+		return prefix
+	case strings.HasPrefix(title, strings.TrimSpace(prefix)):
+		return title
+	case strings.HasSuffix(prefix, " "):
+		return prefix + title
+	}
+	return prefix + " " + title
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestNewIssueFromTemplate(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	dir := filepath.Join(root, ".github", "ISSUE_TEMPLATE")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	template := "---\nname: Bug report\nabout: Report a bug\ntitle: \"[Bug]: \"\nlabels: bug\nassignees: octocat\n---\n\n## Steps\n"
+	if err := os.WriteFile(filepath.Join(dir, "bug_report.md"), []byte(template), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	var out strings.Builder
+	application := New(root, ghcli.ExecRunner{}, &out, io.Discard)
+	if err := application.NewIssue(context.Background(), "", NewOptions{ListTemplates: true}); err != nil {
+		t.Fatalf("list templates: %v", err)
+	}
+	if !strings.Contains(out.String(), "bug_report") || !strings.Contains(out.String(), "Report a bug") {
+		t.Fatalf("unexpected template list: %q", out.String())
+	}
+
+	opts := NewOptions{Template: "bug_report", Labels: []string{"ui"}}
+	if err := application.NewIssue(context.Background(), "Crash on start", opts); err != nil {
+		t.Fatalf("new issue: %v", err)
+	}
+	entries, err := os.ReadDir(p.OpenDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected 1 issue file, got %d (%v)", len(entries), err)
+	}
+	parsed, err := issue.ParseFile(filepath.Join(p.OpenDir, entries[0].Name()))
+	if err != nil {
+		t.Fatalf("parse issue: %v", err)
+	}
+	if parsed.Title != "[Bug]: Crash on start" {
+		t.Fatalf("unexpected title: %q", parsed.Title)
+	}
+	if strings.Join(parsed.Labels, ",") != "bug,ui" || strings.Join(parsed.Assignees, ",") != "octocat" {
+		t.Fatalf("unexpected labels/assignees: %v %v", parsed.Labels, parsed.Assignees)
+	}
+	if parsed.Body != "## Steps\n" {
+		t.Fatalf("unexpected body: %q", parsed.Body)
+	}
+
+	if err := application.NewIssue(context.Background(), "x", NewOptions{Template: "nope"}); err == nil || !strings.Contains(err.Error(), "bug_report") {
+		t.Fatalf("expected unknown template error listing available templates, got %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// TimelineCache stores the timeline of an issue as fetched by pull --timeline
+type TimelineCache struct {
+	Number   string                `json:"number"`
+	Events   []ghcli.TimelineEvent `json:"events"`
+	SyncedAt time.Time             `json:"synced_at"`
+}
+
+func timelinePath(p paths.Paths, number string) string {
+	return filepath.Join(p.TimelineDir, number+".json")
+}
+
+// loadTimeline reads the stored timeline of an issue. The boolean is false if
+// none was fetched yet.
+func loadTimeline(p paths.Paths, number string) (TimelineCache, bool, error) {
+	var cache TimelineCache
+	data, err := os.ReadFile(timelinePath(p, number))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cache, false, nil
+		}
+		return cache, false, err
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cache, false, fmt.Errorf("invalid timeline for #%s: %w", number, err)
+	}
+	return cache, true, nil
+}
+
+func saveTimeline(p paths.Paths, cache TimelineCache) error {
+	if err := os.MkdirAll(p.TimelineDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(timelinePath(p, cache.Number), data, 0o644)
+}
+
+// fetchTimelines fetches and stores the timelines of the given issues.
+// Failures are reported as warnings so they never fail a pull.
+func (a *App) fetchTimelines(ctx context.Context, p paths.Paths, client *ghcli.Client, numbers []string) {
+	if len(numbers) == 0 {
+		return
+	}
+	fetched := 0
+	for _, number := range numbers {
+		events, err := client.GetTimeline(ctx, number)
+		if err == nil {
+			err = saveTimeline(p, TimelineCache{Number: number, Events: events, SyncedAt: a.Now().UTC()})
+		}
+		if err != nil {
+			fmt.Fprintf(a.Err, "%s fetching timeline of #%s: %v\n", a.Theme.WarningText("Warning:"), number, err)
+			continue
+		}
+		fetched++
+	}
+	if fetched > 0 {
+		fmt.Fprintf(a.Out, "%s\n", a.Theme.MutedText(fmt.Sprintf("Fetched %s", pluralize(fetched, "timeline"))))
+	}
+}
+
+// timelineTargets returns the issues whose timelines pull --timeline fetches:
+// the pulled ones, plus the given local issues that have no timeline yet.
+func timelineTargets(p paths.Paths, pulled []issue.Issue, local []IssueFile) []string {
+	seen := map[string]struct{}{}
+	var numbers []string
+	add := func(number issue.IssueNumber) {
+		if number.IsLocal() {
+			return
+		}
+		if _, ok := seen[number.String()]; ok {
+			return
+		}
+		seen[number.String()] = struct{}{}
+		numbers = append(numbers, number.String())
+	}
+	for _, remote := range pulled {
+		add(remote.Number)
+	}
+	for _, item := range local {
+		if _, err := os.Stat(timelinePath(p, item.Issue.Number.String())); errors.Is(err, os.ErrNotExist) {
+			add(item.Issue.Number)
+		}
+	}
+	return numbers
+}
+
+// Timeline shows the stored timeline of an issue: who labeled, assigned,
+// closed, reopened or renamed it, and where it was referenced.
+func (a *App) Timeline(ctx context.Context, ref string) error {
+	p := paths.New(a.Root)
+	cfg, err := loadConfig(p.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	// Acquire shared lock
+	lck, err := lock.AcquireShared(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+	t := a.Theme
+
+	file, err := findIssueByRef(a.Root, p, ref)
+	if err != nil {
+		return err
+	}
+	number := file.Issue.Number.String()
+	if file.Issue.Number.IsLocal() {
+		return fmt.Errorf("%s has not been pushed yet and has no timeline", number)
+	}
+	cache, ok, err := loadTimeline(p, number)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no timeline for #%s; fetch it with `gh-issue-sync pull --timeline %s`", number, number)
+	}
+
+	labelCache, _ := loadLabelCache(p)
+	labelColors := labelCacheToColorMap(labelCache)
+
+	fmt.Fprintln(a.Out, t.FormatIssueHeader("M", number, file.Issue.Title))
+	fmt.Fprintln(a.Out)
+	if len(cache.Events) == 0 {
+		fmt.Fprintf(a.Out, "  %s\n", t.MutedText("No events"))
+	}
+	width := 0
+	for _, event := range cache.Events {
+		if n := len(formatActor(event.Actor)); n > width {
+			width = n
+		}
+	}
+	repo := repoSlug(cfg)
+	for _, event := range cache.Events {
+		actor := formatActor(event.Actor)
+		fmt.Fprintf(a.Out, "  %s  %s%s  %s\n",
+			t.MutedText(event.CreatedAt.Local().Format("2006-01-02 15:04")),
+			t.AccentText(actor), strings.Repeat(" ", width-len(actor)),
+			a.describeTimelineEvent(event, repo, labelColors))
+	}
+	fmt.Fprintf(a.Out, "\n  %s\n", t.MutedText("Fetched "+formatRelativeTime(a.Now(), cache.SyncedAt)))
+	return nil
+}
+
+func formatActor(login string) string {
+	if login == "" {
+		return "ghost"
+	}
+	return "@" + login
+}
+
+// describeTimelineEvent renders what happened in a timeline event.
+func (a *App) describeTimelineEvent(event ghcli.TimelineEvent, repo string, labelColors map[string]string) string {
+	t := a.Theme
+	switch event.Event {
+	case "labeled":
+		return "added label " + t.FormatLabel(event.Label, labelColors[strings.ToLower(event.Label)])
+	case "unlabeled":
+		return "removed label " + t.FormatLabel(event.Label, labelColors[strings.ToLower(event.Label)])
+	case "assigned":
+		return "assigned " + formatActor(event.Assignee)
+	case "unassigned":
+		return "unassigned " + formatActor(event.Assignee)
+	case "closed":
+		text := t.ErrorText("closed")
+		if event.StateReason != "" {
+			text += " as " + strings.ReplaceAll(event.StateReason, "_", " ")
+		}
+		if event.CommitID != "" {
+			text += " in commit " + shortCommit(event.CommitID)
+		}
+		return text
+	case "reopened":
+		return t.SuccessText("reopened")
+	case "referenced":
+		return "referenced in commit " + shortCommit(event.CommitID)
+	case "cross-referenced":
+		if event.Source == nil {
+			return "mentioned elsewhere"
+		}
+		kind := "issue"
+		if event.Source.PullRequest {
+			kind = "pull request"
+		}
+		target := fmt.Sprintf("#%d", event.Source.Number)
+		if event.Source.Repository != "" && !strings.EqualFold(event.Source.Repository, repo) {
+			target = event.Source.Repository + target
+		}
+		return fmt.Sprintf("mentioned in %s %s %s", kind, t.AccentText(target), t.MutedText(fmt.Sprintf("%q", event.Source.Title)))
+	case "renamed":
+		return fmt.Sprintf("renamed %s -> %s", t.Strikethrough(fmt.Sprintf("%q", event.From)), t.Underline(fmt.Sprintf("%q", event.To)))
+	}
+	return event.Event
+}
+
+func shortCommit(id string) string {
+	if len(id) > 7 {
+		return id[:7]
+	}
+	return id
+}
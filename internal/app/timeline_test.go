@@ -0,0 +1,76 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestTimeline(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	for _, item := range []issue.Issue{
+		{Number: "4", Title: "Crash on start", State: "open"},
+		{Number: "5", Title: "Slow start", State: "open"},
+	} {
+		if err := issue.WriteFile(issue.PathFor(p.OpenDir, item.Number, item.Title), item); err != nil {
+			t.Fatalf("write issue: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	application := New(root, offlineRunner{}, &out, &out)
+	at := time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)
+	events := []ghcli.TimelineEvent{
+		{Event: "labeled", Actor: "alice", CreatedAt: at, Label: "bug"},
+		{Event: "assigned", Actor: "alice", CreatedAt: at, Assignee: "bob"},
+		{Event: "cross-referenced", Actor: "bob", CreatedAt: at, Source: &ghcli.TimelineSource{Repository: "owner/repo", Number: 12, Title: "Fix crash", PullRequest: true}},
+		{Event: "renamed", Actor: "alice", CreatedAt: at, From: "Crash", To: "Crash on start"},
+		{Event: "closed", Actor: "bob", CreatedAt: at, StateReason: "not_planned"},
+	}
+	if err := saveTimeline(p, TimelineCache{Number: "4", Events: events, SyncedAt: at}); err != nil {
+		t.Fatalf("save timeline: %v", err)
+	}
+
+	local, err := loadLocalIssues(p)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	targets := timelineTargets(p, []issue.Issue{{Number: "9"}, {Number: "T1"}}, local)
+	if strings.Join(targets, ",") != "9,5" {
+		t.Fatalf("expected pulled issues and those without a timeline, got %v", targets)
+	}
+
+	if err := application.Timeline(context.Background(), "4"); err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	got := stripAnsi(out.String())
+	for _, want := range []string{
+		"@alice  added label bug",
+		"@bob    mentioned in pull request #12 \"Fix crash\"",
+		"renamed \"Crash\" -> \"Crash on start\"",
+		"closed as not planned",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in output:\n%s", want, got)
+		}
+	}
+
+	if err := application.Timeline(context.Background(), filepath.Join(".issues", "open", "5-slow-start.md")); err == nil || !strings.Contains(err.Error(), "pull --timeline") {
+		t.Fatalf("expected missing timeline error, got %v", err)
+	}
+}
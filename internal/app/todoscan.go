@@ -0,0 +1,279 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/localid"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// todoPattern matches TODO and FIXME markers following a comment delimiter,
+// like "// TODO: handle errors" or "# FIXME(alice) flaky". The groups are the
+// keyword, an optional parenthesized note and the text.
+var todoPattern = regexp.MustCompile(`(?:^|[^\w"'])(?://+|#+|/\*+|<!--|--|;+|\*)[ \t]*(TODO|FIXME)\b(\([^)\n]*\))?:?[ \t]*(.*)`)
+
+// todoRefPattern matches issue references that mark a TODO as tracked.
+var todoRefPattern = regexp.MustCompile(`#(?:\d+|T[a-zA-Z0-9]+)\b|/issues/\d+`)
+
+const (
+	// todoContextLines is the number of lines shown around a TODO.
+	todoContextLines = 3
+	maxTodoTitle     = 72
+)
+
+// todoComment is a TODO or FIXME comment without an issue reference.
+type todoComment struct {
+	// Path is relative to the top level of the working tree, with slashes.
+	Path    string
+	Line    int
+	Keyword string
+	Text    string
+	// Snippet holds the lines around the comment.
+	Snippet string
+	// insertAt is the byte offset in the line where a reference goes, and
+	// inParens tells whether it is added to an existing note.
+	insertAt int
+	inParens bool
+}
+
+// findTodos returns the untracked TODO comments in content.
+func findTodos(name string, content string) []todoComment {
+	lines := strings.Split(content, "\n")
+	var todos []todoComment
+	for i, line := range lines {
+		m := todoPattern.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		note, text := "", strings.TrimRight(line[m[6]:m[7]], "\r")
+		if m[4] >= 0 {
+			note = line[m[4]:m[5]]
+		}
+		if todoRefPattern.MatchString(note) || todoRefPattern.MatchString(text) {
+			continue
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(text), "*/"), "-->"))
+
+		todo := todoComment{
+			Path:     name,
+			Line:     i + 1,
+			Keyword:  line[m[2]:m[3]],
+			Text:     text,
+			insertAt: m[3],
+		}
+		if note != "" {
+			todo.insertAt = m[5] - 1
+			todo.inParens = true
+		}
+		start, end := max(0, i-todoContextLines), min(len(lines), i+todoContextLines+1)
+		todo.Snippet = strings.TrimRight(strings.Join(lines[start:end], "\n"), "\r\n")
+		todos = append(todos, todo)
+	}
+	return todos
+}
+
+// todoTitle turns the text of a TODO into an issue title.
+func todoTitle(todo todoComment) string {
+	title := strings.TrimSpace(todo.Text)
+	if title == "" {
+		return fmt.Sprintf("%s in %s:%d", todo.Keyword, todo.Path, todo.Line)
+	}
+	r, size := utf8.DecodeRuneInString(title)
+	title = string(unicode.ToUpper(r)) + title[size:]
+	if utf8.RuneCountInString(title) > maxTodoTitle {
+		runes := []rune(title)[:maxTodoTitle]
+		cut := string(runes)
+		if i := strings.LastIndex(cut, " "); i > maxTodoTitle/2 {
+			cut = cut[:i]
+		}
+		title = strings.TrimRight(cut, " ,.;:") + "…"
+	}
+	return title
+}
+
+// todoBody describes where a TODO is, linking it at commit when known.
+func todoBody(todo todoComment, repo, commit string) string {
+	location := fmt.Sprintf("`%s:%d`", todo.Path, todo.Line)
+	if repo != "" && commit != "" {
+		location = fmt.Sprintf("[%s](https://github.com/%s/blob/%s/%s#L%d)", location, repo, commit, todo.Path, todo.Line)
+	}
+	fence := "```"
+	for strings.Contains(todo.Snippet, fence) {
+		fence += "`"
+	}
+	lang := strings.TrimPrefix(path.Ext(todo.Path), ".")
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s in %s:\n\n", todo.Keyword, location)
+	fmt.Fprintf(&body, "%s%s\n%s\n%s\n", fence, lang, todo.Snippet, fence)
+	return body.String()
+}
+
+// insertTodoRef adds a reference to number to the TODO comment on line.
+func insertTodoRef(line string, todo todoComment, number string) string {
+	if todo.inParens {
+		return line[:todo.insertAt] + ", #" + number + line[todo.insertAt:]
+	}
+	return line[:todo.insertAt] + "(#" + number + ")" + line[todo.insertAt:]
+}
+
+// TodoScan creates local issues from TODO and FIXME comments in the git
+// working tree that do not reference an issue yet. With InsertRefs the new
+// local ID is written back into the comment, and the push creating the issue
+// renumbers it.
+func (a *App) TodoScan(ctx context.Context, globs []string, opts TodoScanOptions) error {
+	p := paths.New(a.Root)
+	cfg, err := loadConfig(p.ConfigPath)
+	if err != nil {
+		return err
+	}
+	match, err := globMatcher(globs)
+	if err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lck, err := lock.Acquire(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+	t := a.Theme
+
+	top, files, err := a.workingTreeFiles(ctx)
+	if err != nil {
+		return err
+	}
+	commit := ""
+	if out, err := a.Runner.Run(ctx, "git", "-C", top, "rev-parse", "HEAD"); err == nil {
+		commit = strings.TrimSpace(out)
+	}
+
+	// Issues created by an earlier scan are not created again
+	existing, err := loadLocalIssues(p)
+	if err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, item := range existing {
+		known[item.Issue.Title] = true
+	}
+	isKnown := func(todo todoComment, title string) bool {
+		if !known[title] {
+			return false
+		}
+		for _, item := range existing {
+			if item.Issue.Title == title && strings.Contains(item.Issue.Body, "`"+todo.Path+":") {
+				return true
+			}
+		}
+		return false
+	}
+
+	codeRefs, err := loadCodeRefs(p)
+	if err != nil {
+		return err
+	}
+
+	created, skipped := 0, 0
+	for _, name := range files {
+		if !match(name) {
+			continue
+		}
+		filePath := filepath.Join(top, filepath.FromSlash(name))
+		if isIssuesPath(p, filePath) {
+			continue
+		}
+		data, perm, ok, err := textFile(filePath)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		todos := findTodos(name, string(data))
+		if len(todos) == 0 {
+			continue
+		}
+
+		lines := strings.Split(string(data), "\n")
+		rewritten := false
+		for _, todo := range todos {
+			title := todoTitle(todo)
+			location := t.MutedText(fmt.Sprintf("(%s:%d)", todo.Path, todo.Line))
+			if isKnown(todo, title) {
+				skipped++
+				continue
+			}
+			if opts.DryRun {
+				fmt.Fprintf(a.Out, "%s %s %s\n", t.MutedText("Would create issue"), title, location)
+				created++
+				continue
+			}
+
+			id, err := localid.Generate()
+			if err != nil {
+				return fmt.Errorf("failed to generate local ID: %w", err)
+			}
+			newIssue := issue.Issue{
+				Number: issue.IssueNumber("T" + id),
+				Title:  title,
+				Labels: opts.Labels,
+				State:  "open",
+				Body:   todoBody(todo, repoSlug(cfg), commit),
+			}
+			issuePath := issue.PathFor(p.OpenDir, newIssue.Number, newIssue.Title)
+			if err := issue.WriteFile(issuePath, newIssue); err != nil {
+				return err
+			}
+			existing = append(existing, IssueFile{Issue: newIssue, Path: issuePath, State: "open"})
+			known[title] = true
+			created++
+			fmt.Fprintf(a.Out, "%s %s %s\n", t.SuccessText("Created"), relPath(a.Root, issuePath), location)
+
+			if opts.InsertRefs {
+				lines[todo.Line-1] = insertTodoRef(lines[todo.Line-1], todo, newIssue.Number.String())
+				rewritten = true
+			}
+		}
+		if rewritten {
+			if err := os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), perm); err != nil {
+				return err
+			}
+			// Push renumbers the references once the issues exist
+			if !slices.Contains(codeRefs, name) {
+				codeRefs = append(codeRefs, name)
+			}
+			fmt.Fprintf(a.Out, "%s %s\n", t.MutedText("Updated references in"), relPath(a.Root, filePath))
+		}
+	}
+
+	if opts.InsertRefs && !opts.DryRun {
+		if err := saveCodeRefs(p, codeRefs); err != nil {
+			return err
+		}
+	}
+
+	summary := fmt.Sprintf("Created %s", pluralize(created, "issue"))
+	if opts.DryRun {
+		summary = fmt.Sprintf("Would create %s", pluralize(created, "issue"))
+	}
+	if created == 0 {
+		summary = "No untracked TODOs found"
+	}
+	if skipped > 0 {
+		summary += fmt.Sprintf(" (%s already tracked by local issues)", pluralize(skipped, "TODO"))
+	}
+	fmt.Fprintf(a.Out, "%s\n", t.MutedText(summary))
+	return nil
+}
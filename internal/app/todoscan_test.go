@@ -0,0 +1,125 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestFindTodos(t *testing.T) {
+	content := strings.Join([]string{
+		"package main",
+		"// TODO: handle errors",
+		"x := 1 # FIXME(alice) flaky on CI",
+		"// TODO(#12): already tracked",
+		"// FIXME see https://github.com/o/r/issues/3",
+		`msg := "// TODO: not a comment"`,
+		"/* TODO */",
+		"// TODOS are not todos",
+	}, "\n")
+	todos := findTodos("main.go", content)
+	if len(todos) != 3 {
+		t.Fatalf("expected 3 todos, got %+v", todos)
+	}
+	if todos[0].Line != 2 || todos[0].Keyword != "TODO" || todos[0].Text != "handle errors" {
+		t.Fatalf("unexpected first todo %+v", todos[0])
+	}
+	if todos[1].Keyword != "FIXME" || todos[1].Text != "flaky on CI" {
+		t.Fatalf("unexpected second todo %+v", todos[1])
+	}
+	if todos[2].Text != "" || todoTitle(todos[2]) != "TODO in main.go:7" {
+		t.Fatalf("unexpected third todo %+v", todos[2])
+	}
+	if todoTitle(todos[0]) != "Handle errors" {
+		t.Fatalf("unexpected title %q", todoTitle(todos[0]))
+	}
+
+	lines := strings.Split(content, "\n")
+	if got := insertTodoRef(lines[1], todos[0], "T1abc"); got != "// TODO(#T1abc): handle errors" {
+		t.Fatalf("unexpected line %q", got)
+	}
+	if got := insertTodoRef(lines[2], todos[1], "T1abc"); got != "x := 1 # FIXME(alice, #T1abc) flaky on CI" {
+		t.Fatalf("unexpected line %q", got)
+	}
+}
+
+func TestTodoScan(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	source := "package main\n\nfunc main() {\n\t// TODO: handle errors\n\trun()\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte("# TODO: write docs\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	runner := &lsFilesRunner{top: root, head: "abc123", files: []string{"main.go", "notes.txt"}}
+	var out bytes.Buffer
+	application := New(root, runner, &out, &out)
+
+	// Without references, a second scan recognizes the issues it created
+	if err := application.TodoScan(context.Background(), []string{"*.txt"}, TodoScanOptions{}); err != nil {
+		t.Fatalf("todo-scan: %v", err)
+	}
+	if err := application.TodoScan(context.Background(), []string{"*.txt"}, TodoScanOptions{}); err != nil {
+		t.Fatalf("todo-scan: %v", err)
+	}
+	if !strings.Contains(stripAnsi(out.String()), "No untracked TODOs found (1 TODO already tracked by local issues)") {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := application.TodoScan(context.Background(), []string{"*.go"}, TodoScanOptions{InsertRefs: true, Labels: []string{"todo"}}); err != nil {
+		t.Fatalf("todo-scan: %v", err)
+	}
+	issues, err := loadLocalIssues(p)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	var created IssueFile
+	for _, item := range issues {
+		if item.Issue.Title == "Handle errors" {
+			created = item
+		}
+	}
+	if created.Path == "" || strings.Join(created.Issue.Labels, ",") != "todo" {
+		t.Fatalf("expected issue to be created, got %+v", issues)
+	}
+	if !strings.Contains(created.Issue.Body, "(https://github.com/owner/repo/blob/abc123/main.go#L4)") ||
+		!strings.Contains(created.Issue.Body, "```go\npackage main\n\nfunc main() {\n\t// TODO: handle errors\n\trun()\n}\n```") {
+		t.Fatalf("unexpected body:\n%s", created.Issue.Body)
+	}
+	number := created.Issue.Number.String()
+	data, _ := os.ReadFile(filepath.Join(root, "main.go"))
+	if !strings.Contains(string(data), "// TODO(#"+number+"): handle errors") {
+		t.Fatalf("expected reference to be inserted:\n%s", data)
+	}
+
+	// The push creating the issue renumbers the reference
+	if err := recordIDMapping(p, map[string]string{number: "42"}); err != nil {
+		t.Fatalf("id map: %v", err)
+	}
+	changes, err := application.pushCodeRefs(context.Background(), p, config.Default("owner", "repo"), PushOptions{}, map[string]string{number: "42"})
+	if err != nil || len(changes) != 1 {
+		t.Fatalf("expected main.go to be rewritten, got %+v, %v", changes, err)
+	}
+	data, _ = os.ReadFile(filepath.Join(root, "main.go"))
+	if !strings.Contains(string(data), "// TODO(#42): handle errors") {
+		t.Fatalf("expected reference to be renumbered:\n%s", data)
+	}
+	if _, err := os.Stat(p.CodeRefsPath); !os.IsNotExist(err) {
+		t.Fatalf("expected pending code references to be cleared, got %v", err)
+	}
+}
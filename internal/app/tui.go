@@ -0,0 +1,679 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+	"github.com/mitsuhiko/gh-issue-sync/internal/search"
+)
+
+// TUI layout limits
+const (
+	tuiMinSplitWidth = 80
+	tuiMinListWidth  = 30
+	tuiMaxListWidth  = 60
+)
+
+const tuiHelp = "j/k move  enter zoom  / filter  l labels  a assign  x close/reopen  e edit  c comment  d changes  D all changes  r reload  q quit"
+
+// tuiPane selects what the preview pane shows.
+type tuiPane int
+
+const (
+	tuiPanePreview tuiPane = iota // the selected issue, rendered like view
+	tuiPaneChanges                // pending changes of the selected issue
+	tuiPaneAll                    // pending changes of all issues
+)
+
+// tuiInput is an active single-line prompt in the status bar.
+type tuiInput struct {
+	label  string
+	value  string
+	submit func(value string) error
+}
+
+// tuiModel holds the state of the interactive issue browser. Terminal
+// handling lives in TUI, so the model can be driven by key names in tests.
+type tuiModel struct {
+	ctx  context.Context
+	app  *App
+	p    paths.Paths
+	repo string
+	all  bool
+
+	query    string
+	issues   []IssueFile
+	modified map[string]bool
+	comments map[string]PendingComment
+
+	cursor int
+	offset int
+	pane   tuiPane
+	zoom   bool
+	scroll int
+	input  *tuiInput
+	status string
+	quit   bool
+
+	previews map[string][]string
+
+	// suspend runs fn with the terminal restored, e.g. to run an editor
+	suspend func(fn func() error) error
+}
+
+// TUI runs a full-screen browser over the local issues with keybindings for
+// common triage actions. All changes are local until the next push.
+func (a *App) TUI(ctx context.Context, opts TUIOptions) error {
+	p := paths.New(a.Root)
+	cfg, err := loadConfig(p.ConfigPath)
+	if err != nil {
+		return err
+	}
+	out, ok := a.Out.(*os.File)
+	in := os.Stdin
+	if !ok || !term.IsTerminal(out.Fd()) || !term.IsTerminal(in.Fd()) {
+		return fmt.Errorf("tui requires an interactive terminal")
+	}
+
+	// Detect the markdown style up front: auto detection queries the
+	// terminal, which does not work while we own it in raw mode.
+	if lipgloss.HasDarkBackground() {
+		a.markdownStyle = styles.DarkStyle
+	} else {
+		a.markdownStyle = styles.LightStyle
+	}
+
+	m := newTUIModel(ctx, a, p, repoSlug(cfg), opts)
+	m.reload()
+
+	state, err := term.MakeRaw(in.Fd())
+	if err != nil {
+		return err
+	}
+	enter := func() { fmt.Fprint(out, "\x1b[?1049h\x1b[?25l") }
+	leave := func() { fmt.Fprint(out, "\x1b[?25h\x1b[?1049l") }
+	enter()
+	defer func() {
+		leave()
+		_ = term.Restore(in.Fd(), state)
+	}()
+	m.suspend = func(fn func() error) error {
+		leave()
+		_ = term.Restore(in.Fd(), state)
+		err := fn()
+		if _, rawErr := term.MakeRaw(in.Fd()); rawErr != nil && err == nil {
+			err = rawErr
+		}
+		enter()
+		return err
+	}
+
+	buf := make([]byte, 256)
+	for !m.quit {
+		width, height, err := term.GetSize(out.Fd())
+		if err != nil || width <= 0 || height <= 0 {
+			width, height = 80, 24
+		}
+		var screen strings.Builder
+		screen.WriteString("\x1b[H")
+		for i, line := range m.render(width-1, height) {
+			if i > 0 {
+				screen.WriteString("\r\n")
+			}
+			screen.WriteString(line)
+			screen.WriteString("\x1b[K")
+		}
+		screen.WriteString("\x1b[J")
+		fmt.Fprint(out, screen.String())
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			m.handleKey(key)
+			if m.quit {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func newTUIModel(ctx context.Context, a *App, p paths.Paths, repo string, opts TUIOptions) *tuiModel {
+	return &tuiModel{
+		ctx:     ctx,
+		app:     a,
+		p:       p,
+		repo:    repo,
+		all:     opts.All,
+		query:   opts.Search,
+		suspend: func(fn func() error) error { return fn() },
+	}
+}
+
+// reload reads the issues from disk and applies the filter, keeping the
+// current selection where possible.
+func (m *tuiModel) reload() {
+	selected := ""
+	if item, ok := m.selected(); ok {
+		selected = item.Issue.Number.String()
+	}
+
+	result := loadLocalIssuesWithErrors(m.p)
+	if len(result.Errors) > 0 {
+		m.status = m.app.Theme.WarningText(fmt.Sprintf("Warning: %v", result.Errors[0]))
+	}
+	q := search.Parse(m.query)
+	var issues []IssueFile
+	for _, item := range result.Issues {
+		if !m.all && q.State == "" && item.State != "open" {
+			continue
+		}
+		if !q.Match(searchData(item)) {
+			continue
+		}
+		issues = append(issues, item)
+	}
+	sortTUIIssues(issues, q)
+
+	m.issues = issues
+	m.modified = make(map[string]bool)
+	for _, item := range issues {
+		number := item.Issue.Number.String()
+		if item.Issue.Number.IsLocal() {
+			continue
+		}
+		if original, ok := readOriginalIssue(m.p, number); ok && !issue.EqualIgnoringSyncedAt(item.Issue, original) {
+			m.modified[number] = true
+		}
+	}
+	m.comments = loadAllPendingComments(m.p)
+	m.previews = nil
+
+	m.cursor = min(m.cursor, max(len(issues)-1, 0))
+	for i, item := range issues {
+		if item.Issue.Number.String() == selected {
+			m.cursor = i
+			break
+		}
+	}
+	m.scroll = 0
+}
+
+// sortTUIIssues applies the query's sort, falling back to issue number.
+func sortTUIIssues(issues []IssueFile, q search.Query) {
+	sortBoardIssues(issues)
+	if q.SortField == "" {
+		return
+	}
+	data := make([]search.IssueData, len(issues))
+	byNumber := make(map[string]IssueFile, len(issues))
+	for i, item := range issues {
+		data[i] = searchData(item)
+		byNumber[item.Issue.Number.String()] = item
+	}
+	q.Sort(data)
+	for i, d := range data {
+		issues[i] = byNumber[d.Number.String()]
+	}
+}
+
+func (m *tuiModel) selected() (IssueFile, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.issues) {
+		return IssueFile{}, false
+	}
+	return m.issues[m.cursor], true
+}
+
+func (m *tuiModel) move(delta int) {
+	m.cursor = min(max(m.cursor+delta, 0), max(len(m.issues)-1, 0))
+	m.scroll = 0
+}
+
+// handleKey applies a single key press (as returned by parseKeys).
+func (m *tuiModel) handleKey(key string) {
+	if m.input != nil {
+		m.handleInputKey(key)
+		return
+	}
+	m.status = ""
+	switch key {
+	case "q", "ctrl+c":
+		m.quit = true
+	case "j", "down":
+		m.move(1)
+	case "k", "up":
+		m.move(-1)
+	case "pgdown":
+		m.move(10)
+	case "pgup":
+		m.move(-10)
+	case "g", "home":
+		m.move(-len(m.issues))
+	case "G", "end":
+		m.move(len(m.issues))
+	case "J", "space":
+		m.scroll++
+	case "K":
+		m.scroll = max(m.scroll-1, 0)
+	case "enter":
+		m.zoom = !m.zoom
+		m.scroll = 0
+	case "esc":
+		m.zoom = false
+		m.pane = tuiPanePreview
+		m.scroll = 0
+	case "d":
+		m.togglePane(tuiPaneChanges)
+	case "D":
+		m.togglePane(tuiPaneAll)
+	case "r":
+		m.reload()
+	case "/":
+		m.prompt("Filter", m.query, func(value string) error {
+			m.query = strings.TrimSpace(value)
+			m.reload()
+			return nil
+		})
+	case "l":
+		m.promptList("Labels", func(iss *issue.Issue) *[]string { return &iss.Labels })
+	case "a":
+		m.promptList("Assignees", func(iss *issue.Issue) *[]string { return &iss.Assignees })
+	case "x":
+		m.run(m.toggleState)
+	case "e":
+		m.run(m.edit)
+	case "c":
+		m.run(m.comment)
+	}
+}
+
+func (m *tuiModel) handleInputKey(key string) {
+	in := m.input
+	switch key {
+	case "esc", "ctrl+c":
+		m.input = nil
+	case "enter":
+		m.input = nil
+		m.run(func() error { return in.submit(in.value) })
+	case "backspace":
+		if in.value != "" {
+			_, size := utf8.DecodeLastRuneInString(in.value)
+			in.value = in.value[:len(in.value)-size]
+		}
+	case "ctrl+u":
+		in.value = ""
+	case "space":
+		in.value += " "
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			in.value += key
+		}
+	}
+}
+
+func (m *tuiModel) prompt(label, value string, submit func(string) error) {
+	m.input = &tuiInput{label: label, value: value, submit: submit}
+}
+
+// promptList edits a list field of the selected issue as comma separated
+// values.
+func (m *tuiModel) promptList(label string, field func(*issue.Issue) *[]string) {
+	item, ok := m.selected()
+	if !ok {
+		return
+	}
+	current := strings.Join(*field(&item.Issue), ", ")
+	m.prompt(label, current, func(value string) error {
+		var values []string
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" && !slices.Contains(values, v) {
+				values = append(values, v)
+			}
+		}
+		if err := m.updateSelected(func(iss *issue.Issue) { *field(iss) = values }); err != nil {
+			return err
+		}
+		m.status = fmt.Sprintf("Updated %s of %s", strings.ToLower(label), tuiIssueRef(item))
+		return nil
+	})
+}
+
+func (m *tuiModel) togglePane(pane tuiPane) {
+	if m.pane == pane {
+		m.pane = tuiPanePreview
+	} else {
+		m.pane = pane
+	}
+	m.scroll = 0
+}
+
+// run executes an action and reports its error in the status bar.
+func (m *tuiModel) run(action func() error) {
+	if err := action(); err != nil {
+		m.status = m.app.Theme.ErrorText("Error: " + err.Error())
+	}
+}
+
+// updateSelected rewrites the selected issue file under the sync lock.
+func (m *tuiModel) updateSelected(update func(*issue.Issue)) error {
+	item, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	lck, err := lock.Acquire(m.p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+
+	file, err := findIssueByNumber(m.p, item.Issue.Number.String())
+	if err != nil {
+		return err
+	}
+	update(&file.Issue)
+	if err := issue.WriteFile(file.Path, file.Issue); err != nil {
+		return err
+	}
+	m.reload()
+	return nil
+}
+
+func (m *tuiModel) toggleState() error {
+	item, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	number := item.Issue.Number.String()
+	if item.State == "closed" {
+		if err := m.app.Reopen(m.ctx, number); err != nil {
+			return err
+		}
+		m.status = "Reopened " + tuiIssueRef(item)
+	} else {
+		if err := m.app.Close(m.ctx, number, CloseOptions{}); err != nil {
+			return err
+		}
+		m.status = "Closed " + tuiIssueRef(item)
+	}
+	m.reload()
+	return nil
+}
+
+func (m *tuiModel) edit() error {
+	item, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	err := m.suspend(func() error {
+		return m.app.Edit(m.ctx, item.Issue.Number.String())
+	})
+	m.reload()
+	return err
+}
+
+// comment opens the pending comment of the selected issue in the editor,
+// creating it if needed. Comments left empty are removed again.
+func (m *tuiModel) comment() error {
+	item, ok := m.selected()
+	if !ok {
+		return nil
+	}
+	path := pendingCommentPath(m.p, item)
+	err := m.suspend(func() error { return openEditor(m.ctx, path) })
+	if err != nil {
+		return err
+	}
+	if content, readErr := os.ReadFile(path); readErr == nil && strings.TrimSpace(string(content)) == "" {
+		_ = os.Remove(path)
+		m.status = "Discarded empty comment"
+	} else if readErr == nil {
+		m.status = "Queued comment on " + tuiIssueRef(item)
+	}
+	m.reload()
+	return nil
+}
+
+func tuiIssueRef(item IssueFile) string {
+	if item.Issue.Number.IsLocal() {
+		return item.Issue.Number.String()
+	}
+	return "#" + item.Issue.Number.String()
+}
+
+// render returns exactly height lines of at most width visible characters.
+func (m *tuiModel) render(width, height int) []string {
+	t := m.app.Theme
+	reset := t.Styler().Reset()
+	bodyHeight := max(height-2, 1)
+
+	header := t.Bold("gh-issue-sync") + "  " + t.MutedText(m.repo) + "  " + pluralize(len(m.issues), "issue")
+	if m.query != "" {
+		header += "  " + t.MutedText("filter:") + " " + m.query
+	}
+	lines := []string{truncateAnsi(header, width, reset)}
+
+	listWidth, previewWidth := width, 0
+	switch {
+	case m.zoom:
+		listWidth, previewWidth = 0, width
+	case width >= tuiMinSplitWidth:
+		listWidth = min(max(width*2/5, tuiMinListWidth), tuiMaxListWidth)
+		previewWidth = width - listWidth - 3
+	}
+
+	var list []string
+	if listWidth > 0 {
+		list = m.renderList(listWidth, bodyHeight)
+	}
+	var preview []string
+	if previewWidth > 0 {
+		preview = m.previewLines(previewWidth)
+		m.scroll = min(m.scroll, max(len(preview)-1, 0))
+		preview = preview[m.scroll:]
+	}
+	for row := 0; row < bodyHeight; row++ {
+		var line string
+		if listWidth > 0 {
+			cell := ""
+			if row < len(list) {
+				cell = list[row]
+			}
+			line = cell
+			if previewWidth > 0 {
+				line = padVisible(cell, listWidth) + " " + t.MutedText("│") + " "
+			}
+		}
+		if previewWidth > 0 && row < len(preview) {
+			line += truncateAnsi(preview[row], previewWidth, reset)
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+
+	var footer string
+	switch {
+	case m.input != nil:
+		footer = t.AccentText(m.input.label+":") + " " + m.input.value + "_"
+	case m.status != "":
+		footer = m.status
+	default:
+		footer = t.MutedText(tuiHelp)
+	}
+	lines = append(lines, truncateAnsi(footer, width, reset))
+	return lines
+}
+
+// renderList renders the visible part of the issue list.
+func (m *tuiModel) renderList(width, height int) []string {
+	t := m.app.Theme
+	reset := t.Styler().Reset()
+	if len(m.issues) == 0 {
+		return []string{t.MutedText("No issues found")}
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+height {
+		m.offset = m.cursor - height + 1
+	}
+
+	var lines []string
+	for i := m.offset; i < len(m.issues) && i < m.offset+height; i++ {
+		item := m.issues[i]
+		number := item.Issue.Number.String()
+		marker := "  "
+		if i == m.cursor {
+			marker = t.AccentText("> ")
+		}
+		status := " "
+		switch {
+		case item.Issue.Number.IsLocal():
+			status = t.FormatStatus("A")
+		case m.modified[number]:
+			status = t.FormatStatus("M")
+		}
+		if _, ok := m.comments[number]; ok {
+			status += t.WarningText("c")
+		} else {
+			status += " "
+		}
+		title := item.Issue.Title
+		switch {
+		case i == m.cursor:
+			title = t.Bold(title)
+		case item.State == "closed":
+			title = t.MutedText(title)
+		}
+		line := marker + status + " " + t.AccentText(tuiIssueRef(item)) + " " + title
+		lines = append(lines, truncateAnsi(line, width, reset))
+	}
+	return lines
+}
+
+// previewLines renders the preview pane by running view or diff against a
+// buffer. Results are cached until the next reload.
+func (m *tuiModel) previewLines(width int) []string {
+	t := m.app.Theme
+	item, ok := m.selected()
+	if !ok && m.pane != tuiPaneAll {
+		return nil
+	}
+	key := fmt.Sprintf("%d:%d:%s", m.pane, width, item.Issue.Number)
+	if lines, ok := m.previews[key]; ok {
+		return lines
+	}
+
+	var buf bytes.Buffer
+	view := *m.app
+	view.Out = &buf
+	view.Err = io.Discard
+	view.markdownWidth = width
+	number := item.Issue.Number.String()
+	var err error
+	switch m.pane {
+	case tuiPanePreview:
+		err = view.View(m.ctx, number, ViewOptions{})
+	case tuiPaneChanges:
+		if item.Issue.Number.IsLocal() {
+			fmt.Fprintln(&buf, t.MutedText("New issue, will be created on push"))
+		} else {
+			err = view.Diff(m.ctx, number, DiffOptions{})
+		}
+	case tuiPaneAll:
+		err = view.DiffAll(m.ctx, DiffOptions{})
+	}
+	if err != nil {
+		fmt.Fprintln(&buf, t.ErrorText(err.Error()))
+	}
+
+	text := strings.ReplaceAll(strings.TrimRight(buf.String(), "\n"), "\t", "  ")
+	lines := strings.Split(text, "\n")
+	if m.previews == nil {
+		m.previews = make(map[string][]string)
+	}
+	m.previews[key] = lines
+	return lines
+}
+
+// padVisible pads s with spaces to width visible characters.
+func padVisible(s string, width int) string {
+	if n := visibleLen(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// csiKeys maps CSI/SS3 escape sequence suffixes to key names.
+var csiKeys = map[string]string{
+	"A":  "up",
+	"B":  "down",
+	"C":  "right",
+	"D":  "left",
+	"H":  "home",
+	"F":  "end",
+	"1~": "home",
+	"4~": "end",
+	"5~": "pgup",
+	"6~": "pgdown",
+}
+
+// parseKeys splits raw terminal input into key names. Printable characters
+// are returned as themselves, special keys by name ("up", "enter", ...).
+func parseKeys(data []byte) []string {
+	var keys []string
+	for len(data) > 0 {
+		c := data[0]
+		if c == 0x1b {
+			if len(data) > 2 && (data[1] == '[' || data[1] == 'O') {
+				end := 2
+				for end < len(data) && (data[end] < 0x40 || data[end] > 0x7e) {
+					end++
+				}
+				if end < len(data) {
+					if name, ok := csiKeys[string(data[2:end+1])]; ok {
+						keys = append(keys, name)
+					}
+					data = data[end+1:]
+					continue
+				}
+			}
+			keys = append(keys, "esc")
+			data = data[1:]
+			continue
+		}
+		switch c {
+		case '\r', '\n':
+			keys = append(keys, "enter")
+		case 0x7f, 0x08:
+			keys = append(keys, "backspace")
+		case 0x03:
+			keys = append(keys, "ctrl+c")
+		case 0x15:
+			keys = append(keys, "ctrl+u")
+		case ' ':
+			keys = append(keys, "space")
+		default:
+			if c >= 0x20 {
+				r, size := utf8.DecodeRune(data)
+				keys = append(keys, string(r))
+				data = data[size:]
+				continue
+			}
+		}
+		data = data[1:]
+	}
+	return keys
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+func TestParseKeys(t *testing.T) {
+	keys := parseKeys([]byte("j\x1b[Ak\r\x7f\x1b[6~\x1b\x03é "))
+	expected := []string{"j", "up", "k", "enter", "backspace", "pgdown", "esc", "ctrl+c", "é", "space"}
+	if strings.Join(keys, ",") != strings.Join(expected, ",") {
+		t.Fatalf("unexpected keys: %q", keys)
+	}
+}
+
+func TestTUIModel(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	for _, iss := range []issue.Issue{
+		{Number: "1", Title: "Crash on start", State: "open", Labels: []string{"bug"}},
+		{Number: "2", Title: "Add dark mode", State: "open"},
+	} {
+		if err := issue.WriteFile(filepath.Join(p.OpenDir, iss.Number.String()+".md"), iss); err != nil {
+			t.Fatalf("write issue: %v", err)
+		}
+	}
+
+	application := New(root, ghcli.ExecRunner{}, io.Discard, io.Discard)
+	m := newTUIModel(context.Background(), application, p, "owner/repo", TUIOptions{})
+	m.reload()
+	press := func(keys ...string) {
+		for _, key := range keys {
+			m.handleKey(key)
+		}
+	}
+
+	screen := stripAnsi(strings.Join(m.render(100, 10), "\n"))
+	if !strings.Contains(screen, "> ") || !strings.Contains(screen, "#1 Crash on start") || !strings.Contains(screen, "2 issues") {
+		t.Fatalf("unexpected screen:\n%s", screen)
+	}
+
+	// Filter with a search query
+	press("/", "d", "a", "r", "k", "enter")
+	if m.query != "dark" || len(m.issues) != 1 || m.issues[0].Issue.Number != "2" {
+		t.Fatalf("unexpected filter result: %q %d", m.query, len(m.issues))
+	}
+
+	// Replace the labels of the selected issue
+	press("l", "ctrl+u", "u", "i", ",", "space", "u", "x", "enter")
+	updated, err := findIssueByNumber(p, "2")
+	if err != nil {
+		t.Fatalf("find issue: %v", err)
+	}
+	if strings.Join(updated.Issue.Labels, ",") != "ui,ux" {
+		t.Fatalf("unexpected labels: %v", updated.Issue.Labels)
+	}
+
+	// Close it; it drops out of the (open only) list
+	press("x")
+	closed, err := findIssueByNumber(p, "2")
+	if err != nil || closed.State != "closed" {
+		t.Fatalf("expected issue to be closed, got %v %v", closed.State, err)
+	}
+	if len(m.issues) != 0 || !strings.Contains(m.status, "Closed #2") {
+		t.Fatalf("unexpected state after close: %d %q", len(m.issues), m.status)
+	}
+
+	// Queue a comment through the editor
+	press("/", "ctrl+u", "enter")
+	prevEditor := runInteractiveCommand
+	defer func() { runInteractiveCommand = prevEditor }()
+	runInteractiveCommand = func(ctx context.Context, command string, args ...string) error {
+		return os.WriteFile(args[len(args)-1], []byte("Looking into it\n"), 0o644)
+	}
+	t.Setenv("EDITOR", "true")
+	press("c")
+	comment, ok := findPendingCommentForIssue(p, "1", "open")
+	if !ok || comment.Body != "Looking into it" {
+		t.Fatalf("expected queued comment, got %+v %v", comment, ok)
+	}
+	screen = stripAnsi(strings.Join(m.render(100, 10), "\n"))
+	if !strings.Contains(screen, " c #1 Crash on start") {
+		t.Fatalf("expected comment marker in list:\n%s", screen)
+	}
+
+	press("q")
+	if !m.quit {
+		t.Fatalf("expected quit")
+	}
+}
@@ -0,0 +1,332 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/ghcli"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/oplog"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// snapshotDirs are the directories pull and push change, relative to the
+// issues directory.
+var snapshotDirs = []string{
+	paths.OpenDirName,
+	paths.ClosedDirName,
+	filepath.Join(paths.SyncDirName, paths.OriginalsDirName),
+}
+
+// operationLog returns the operations log. Pending comments are left out of
+// its snapshots: push deletes them once posted, and restoring them on undo
+// would post them again.
+func operationLog(p paths.Paths) *oplog.Log {
+	log := oplog.New(p.OplogDir)
+	log.Skip = func(rel string) bool {
+		return strings.HasSuffix(rel, ".comment.md")
+	}
+	return log
+}
+
+// opRecorder records a pull or push in the operations log so it can be
+// undone.
+type opRecorder struct {
+	log *oplog.Log
+	op  oplog.Operation
+}
+
+// beginOperation snapshots the issue files and the last full pull time
+// before a pull or push. Failing to record is only a warning; the sync itself
+// goes ahead.
+func (a *App) beginOperation(p paths.Paths, kind string) *opRecorder {
+	log := operationLog(p)
+	before, err := log.Snapshot(p.IssuesDir, snapshotDirs)
+	if err != nil {
+		fmt.Fprintf(a.Err, "%s recording %s for undo: %v\n", a.Theme.WarningText("Warning:"), kind, err)
+		return nil
+	}
+	rec := &opRecorder{log: log, op: oplog.Operation{Kind: kind, Before: before}}
+	if cfg, err := loadConfig(p.ConfigPath); err == nil {
+		rec.op.LastFullPull = cfg.Sync.LastFullPull
+	}
+	return rec
+}
+
+// remote records an issue the operation created or edited on GitHub.
+func (r *opRecorder) remote(change oplog.RemoteChange) {
+	if r != nil {
+		r.op.Remote = append(r.op.Remote, change)
+	}
+}
+
+// finishOperation snapshots the issue files after the operation and records
+// it, unless it changed nothing.
+func (a *App) finishOperation(p paths.Paths, r *opRecorder) {
+	if r == nil {
+		return
+	}
+	after, err := r.log.Snapshot(p.IssuesDir, snapshotDirs)
+	if err == nil {
+		if after.Equal(r.op.Before) && len(r.op.Remote) == 0 {
+			return
+		}
+		r.op.After = after
+		r.op.CreatedAt = a.Now().UTC()
+		err = r.log.Record(r.op)
+	}
+	if err != nil {
+		fmt.Fprintf(a.Err, "%s recording %s for undo: %v\n", a.Theme.WarningText("Warning:"), r.op.Kind, err)
+	}
+}
+
+// issueState returns the remotely editable fields of an issue.
+func issueState(item issue.Issue) *oplog.IssueState {
+	return &oplog.IssueState{
+		Title:       item.Title,
+		Body:        item.Body,
+		Labels:      item.Labels,
+		Assignees:   item.Assignees,
+		Milestone:   item.Milestone,
+		State:       item.State,
+		StateReason: normalizeOptional(item.StateReason),
+	}
+}
+
+// issueFromState is the inverse of issueState.
+func issueFromState(number string, state *oplog.IssueState) issue.Issue {
+	item := issue.Issue{
+		Number:    issue.IssueNumber(number),
+		Title:     state.Title,
+		Body:      state.Body,
+		Labels:    state.Labels,
+		Assignees: state.Assignees,
+		Milestone: state.Milestone,
+		State:     state.State,
+	}
+	if state.StateReason != "" {
+		reason := state.StateReason
+		item.StateReason = &reason
+	}
+	return item
+}
+
+// Undo reverts the most recent pull or push: local issue files and originals
+// are restored to their state before it (pending comments are kept as they
+// are), undoing a pull resets the last full pull time, and for pushes the
+// issues it edited on GitHub are set back to their previous title, body,
+// labels, assignees, milestone and state. Issues the push created are closed
+// as not planned and their local IDs forgotten, so pushing the restored local
+// issues creates them again.
+func (a *App) Undo(ctx context.Context, opts UndoOptions) error {
+	p := paths.New(a.Root)
+	cfg, err := loadConfig(p.ConfigPath)
+	if err != nil {
+		return err
+	}
+
+	// Acquire lock
+	lck, err := lock.Acquire(p.SyncDir, lock.DefaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer lck.Release()
+
+	t := a.Theme
+	log := operationLog(p)
+	ops, err := log.List()
+	if err != nil {
+		return err
+	}
+	if opts.List {
+		if len(ops) == 0 {
+			fmt.Fprintln(a.Out, t.MutedText("No operations recorded"))
+		}
+		for _, op := range ops {
+			fmt.Fprintf(a.Out, "%-4s %s %s\n", op.Kind, formatRelativeTime(a.Now(), op.CreatedAt), t.MutedText(operationSummary(op)))
+		}
+		return nil
+	}
+	if len(ops) == 0 {
+		fmt.Fprintln(a.Out, t.MutedText("Nothing to undo"))
+		return nil
+	}
+	op := ops[0]
+
+	current, err := log.Snapshot(p.IssuesDir, snapshotDirs)
+	if err != nil {
+		return err
+	}
+	if !opts.Force && !current.Equal(op.After) {
+		return fmt.Errorf("local files changed since the %s %s; use --force to discard those changes", op.Kind, formatRelativeTime(a.Now(), op.CreatedAt))
+	}
+
+	var closed []string
+	if len(op.Remote) > 0 && !opts.LocalOnly {
+		client := ghcli.NewClient(a.Runner, repoSlug(cfg))
+		att, err := loadAttachments(p, cfg)
+		if err != nil {
+			return err
+		}
+		closed = a.revertRemote(ctx, client, att, op.Remote, opts.Force)
+		if err := forgetCreatedIssues(p, att, closed); err != nil {
+			return err
+		}
+	}
+
+	if err := log.Restore(p.IssuesDir, snapshotDirs, op.Before); err != nil {
+		return err
+	}
+	// The next incremental pull has to fetch the rolled back issues again
+	if op.Kind == "pull" {
+		cfg.Sync.LastFullPull = op.LastFullPull
+		if err := config.Save(p.ConfigPath, cfg); err != nil {
+			return err
+		}
+	}
+	if err := log.Remove(op.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.Out, "%s %s %s %s\n", t.SuccessText("Undid"), op.Kind, formatRelativeTime(a.Now(), op.CreatedAt),
+		t.MutedText("("+pluralize(changedFiles(op.Before, op.After), "file")+" restored)"))
+	return nil
+}
+
+// revertRemote sets issues a push edited back to their previous state and
+// closes the ones it created, returning the numbers of the closed issues.
+// Issues changed on GitHub since the push are left alone unless force is
+// set.
+func (a *App) revertRemote(ctx context.Context, client *ghcli.Client, att *attachments, changes []oplog.RemoteChange, force bool) []string {
+	t := a.Theme
+	var closed []string
+	for _, change := range changes {
+		number := change.Number
+		if change.Created {
+			// Without its creation marker the closed issue is not mistaken
+			// for the local issue when that is pushed again
+			remote, err := client.GetIssue(ctx, number)
+			if err != nil {
+				fmt.Fprintf(a.Err, "%s fetching #%s: %v\n", t.WarningText("Warning:"), number, err)
+				continue
+			}
+			if _, ok := issue.CreationMarkerID(remote.Body); ok {
+				body := issue.StripCreationMarker(remote.Body)
+				if err := client.EditIssue(ctx, number, ghcli.IssueChange{Body: &body}); err != nil {
+					fmt.Fprintf(a.Err, "%s removing creation marker from #%s: %v\n", t.WarningText("Warning:"), number, err)
+					continue
+				}
+			}
+			if err := client.CloseIssue(ctx, number, "not planned"); err != nil {
+				fmt.Fprintf(a.Err, "%s closing #%s: %v\n", t.WarningText("Warning:"), number, err)
+				continue
+			}
+			closed = append(closed, number)
+			fmt.Fprintf(a.Out, "%s #%s %s\n", t.SuccessText("Closed"), number, t.MutedText("(created by the push)"))
+			continue
+		}
+		if change.Before == nil || change.After == nil {
+			continue
+		}
+
+		remote, err := client.GetIssue(ctx, number)
+		if err != nil {
+			fmt.Fprintf(a.Err, "%s fetching #%s: %v\n", t.WarningText("Warning:"), number, err)
+			continue
+		}
+		marker, hasMarker := issue.CreationMarkerID(remote.Body)
+		remote.Body = att.localBody(number, remote.Body)
+		current := issueFromState(number, issueState(remote))
+		if !force && !issue.EqualForConflictCheck(current, issueFromState(number, change.After)) {
+			fmt.Fprintf(a.Err, "%s #%s changed on GitHub since the push, not reverted (use --force)\n", t.WarningText("Warning:"), number)
+			continue
+		}
+
+		before := issueFromState(number, change.Before)
+		edit := diffIssue(issue.Normalize(current), issue.Normalize(before))
+		if edit.Body != nil {
+			body := a.remoteBody(att, number, "", *edit.Body)
+			if hasMarker {
+				body = issue.WithCreationMarker(body, marker)
+			}
+			edit.Body = &body
+		}
+		if hasEdits(edit) {
+			if err := client.EditIssue(ctx, number, edit); err != nil {
+				fmt.Fprintf(a.Err, "%s reverting #%s: %v\n", t.WarningText("Warning:"), number, err)
+				continue
+			}
+		}
+		if edit.StateTransition != nil {
+			if *edit.StateTransition == "close" {
+				err = client.CloseIssue(ctx, number, normalizeOptional(before.StateReason))
+			} else {
+				err = client.ReopenIssue(ctx, number)
+			}
+			if err != nil {
+				fmt.Fprintf(a.Err, "%s reverting state of #%s: %v\n", t.WarningText("Warning:"), number, err)
+				continue
+			}
+		}
+		fmt.Fprintln(a.Out, t.FormatIssueHeader("U", number, before.Title))
+	}
+	return closed
+}
+
+// forgetCreatedIssues removes closed issues created by a push from the ID
+// map, and moves their uploads back to the local IDs they were made for.
+func forgetCreatedIssues(p paths.Paths, att *attachments, numbers []string) error {
+	if len(numbers) == 0 {
+		return nil
+	}
+	ids, err := loadIDMap(p)
+	if err != nil {
+		return err
+	}
+	locals := map[string]string{}
+	for local, number := range ids {
+		if slices.Contains(numbers, number) {
+			locals[number] = local
+			delete(ids, local)
+		}
+	}
+	if len(locals) == 0 {
+		return nil
+	}
+	if err := saveIDMap(p, ids); err != nil {
+		return err
+	}
+	if att.store.RenameUploads(locals) {
+		return att.store.Save()
+	}
+	return nil
+}
+
+// operationSummary describes what an operation changed.
+func operationSummary(op oplog.Operation) string {
+	summary := pluralize(changedFiles(op.Before, op.After), "file")
+	if len(op.Remote) > 0 {
+		summary += ", " + pluralize(len(op.Remote), "remote issue")
+	}
+	return summary
+}
+
+// changedFiles counts the files that differ between two snapshots.
+func changedFiles(before, after oplog.Snapshot) int {
+	changed := 0
+	for path, hash := range before {
+		if after[path] != hash {
+			changed++
+		}
+	}
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			changed++
+		}
+	}
+	return changed
+}
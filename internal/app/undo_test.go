@@ -0,0 +1,193 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/assets"
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/oplog"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+// revertRunner serves issue #5 as a push left it and #6 as the push created
+// it, and records edits.
+type revertRunner struct {
+	calls []string
+}
+
+func (r *revertRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	switch {
+	case strings.HasPrefix(joined, "issue view 5"):
+		return `{"number":5,"title":"New title","body":"Body\n\n<!-- gh-issue-sync:local-id=T1 -->","labels":[{"name":"bug"}],"state":"CLOSED","stateReason":"COMPLETED"}`, nil
+	case strings.HasPrefix(joined, "issue view 6"):
+		return `{"number":6,"title":"Created","body":"Details\n\n<!-- gh-issue-sync:local-id=T2 -->","state":"OPEN"}`, nil
+	case strings.HasPrefix(joined, "issue edit"), strings.HasPrefix(joined, "issue reopen"), strings.HasPrefix(joined, "issue close"):
+		r.calls = append(r.calls, joined)
+		return "", nil
+	}
+	return "", errors.New("unexpected call: " + joined)
+}
+
+func TestUndoPush(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	before := issue.Issue{Number: "5", Title: "Old title", Body: "Body\n", State: "open"}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "5-old-title.md"), before); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	if err := writeOriginalIssue(p, before); err != nil {
+		t.Fatalf("write original: %v", err)
+	}
+	commentPath := filepath.Join(p.OpenDir, "5.comment.md")
+	if err := os.WriteFile(commentPath, []byte("Fixed\n"), 0o644); err != nil {
+		t.Fatalf("write comment: %v", err)
+	}
+
+	if err := recordIDMapping(p, map[string]string{"T1": "5"}); err != nil {
+		t.Fatalf("id map: %v", err)
+	}
+
+	runner := &revertRunner{}
+	var out bytes.Buffer
+	application := New(root, runner, &out, &out)
+
+	// Record a push that posted the comment, renamed, relabeled and closed the
+	// issue and created #6
+	rec := application.beginOperation(p, "push")
+	if err := os.Remove(commentPath); err != nil {
+		t.Fatalf("remove comment: %v", err)
+	}
+	after := before
+	after.Title = "New title"
+	after.Labels = []string{"bug"}
+	after.State = "closed"
+	if err := os.Remove(filepath.Join(p.OpenDir, "5-old-title.md")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := issue.WriteFile(filepath.Join(p.ClosedDir, "5-new-title.md"), after); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	if err := writeOriginalIssue(p, after); err != nil {
+		t.Fatalf("write original: %v", err)
+	}
+	rec.remote(oplog.RemoteChange{Number: "5", Before: issueState(before), After: issueState(after)})
+	rec.remote(oplog.RemoteChange{Number: "6", Created: true})
+	if err := recordIDMapping(p, map[string]string{"T2": "6"}); err != nil {
+		t.Fatalf("id map: %v", err)
+	}
+	store, err := assets.Load(p.AssetsDir)
+	if err != nil {
+		t.Fatalf("load assets: %v", err)
+	}
+	store.RecordUpload(assets.Upload{Issue: "6", Ref: "shot.png", URL: "https://example.com/shot.png"})
+	if err := store.Save(); err != nil {
+		t.Fatalf("save assets: %v", err)
+	}
+	application.finishOperation(p, rec)
+
+	if err := application.Undo(context.Background(), UndoOptions{}); err != nil {
+		t.Fatalf("undo: %v\n%s", err, out.String())
+	}
+
+	calls := strings.Join(runner.calls, "\n")
+	for _, want := range []string{"issue edit 5 --title Old title", "--remove-label bug", "issue reopen 5", "issue edit 6 --body Details --repo", "issue close 6 --reason not planned"} {
+		if !strings.Contains(calls, want) {
+			t.Fatalf("expected %q in calls:\n%s", want, calls)
+		}
+	}
+	if strings.Contains(calls, "issue edit 5 --body") || strings.Contains(calls, "local-id=T2") {
+		t.Fatalf("expected the unchanged body to be left alone and the marker removed:\n%s", calls)
+	}
+	// The local issue is created again by the next push
+	if ids, err := loadIDMap(p); err != nil || len(ids) != 1 || ids["T1"] != "5" {
+		t.Fatalf("expected T2 to be removed from the ID map, got %v (%v)", ids, err)
+	}
+	if store, err := assets.Load(p.AssetsDir); err != nil || store.Manifest.Uploads[0].Issue != "T2" {
+		t.Fatalf("expected upload to move back to T2, got %+v (%v)", store.Manifest.Uploads, err)
+	}
+	if _, err := os.Stat(filepath.Join(p.OpenDir, "5-old-title.md")); err != nil {
+		t.Fatalf("expected local file to be restored: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(p.ClosedDir, "5-new-title.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected pushed file to be removed, got %v", err)
+	}
+	if original, ok := readOriginalIssue(p, "5"); !ok || original.Title != "Old title" {
+		t.Fatalf("expected original to be restored, got %+v", original)
+	}
+	if _, err := os.Stat(commentPath); !os.IsNotExist(err) {
+		t.Fatalf("expected posted comment to stay deleted, got %v", err)
+	}
+
+	out.Reset()
+	if err := application.Undo(context.Background(), UndoOptions{}); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if !strings.Contains(out.String(), "Nothing to undo") {
+		t.Fatalf("expected nothing left to undo, got %q", out.String())
+	}
+}
+
+func TestUndoRefusesLocalChanges(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	cfg := config.Default("owner", "repo")
+	lastPull := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	cfg.Sync.LastFullPull = &lastPull
+	if err := config.Save(p.ConfigPath, cfg); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	application := New(root, offlineRunner{}, &bytes.Buffer{}, &bytes.Buffer{})
+
+	path := filepath.Join(p.OpenDir, "1-pulled.md")
+	rec := application.beginOperation(p, "pull")
+	if err := issue.WriteFile(path, issue.Issue{Number: "1", Title: "Pulled", State: "open"}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	pulledAt := lastPull.AddDate(0, 0, 1)
+	cfg.Sync.LastFullPull = &pulledAt
+	if err := config.Save(p.ConfigPath, cfg); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	application.finishOperation(p, rec)
+
+	// An edit after the pull would be lost
+	if err := issue.WriteFile(path, issue.Issue{Number: "1", Title: "Edited", State: "open"}); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	err := application.Undo(context.Background(), UndoOptions{})
+	if err == nil || !strings.Contains(err.Error(), "local files changed since the pull") {
+		t.Fatalf("expected undo to refuse, got %v", err)
+	}
+
+	if err := application.Undo(context.Background(), UndoOptions{Force: true}); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected pulled file to be removed, got %v", err)
+	}
+	cfg, err = loadConfig(p.ConfigPath)
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	if cfg.Sync.LastFullPull == nil || !cfg.Sync.LastFullPull.Equal(lastPull) {
+		t.Fatalf("expected last full pull to be reset to %v, got %v", lastPull, cfg.Sync.LastFullPull)
+	}
+}
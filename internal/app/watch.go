@@ -0,0 +1,235 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/lock"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+	"github.com/mitsuhiko/gh-issue-sync/internal/watch"
+)
+
+// Watch defaults
+const (
+	defaultWatchInterval = 5 * time.Minute
+	defaultWatchDebounce = 2 * time.Second
+)
+
+// Watch keeps the local issues in sync until ctx is cancelled: it pulls on an
+// interval (incrementally once a full pull has happened) and pushes local
+// edits after they settle. The sync lock is only held while pulling or
+// pushing, so other commands can run in between; if one holds the lock the
+// step is retried later.
+func (a *App) Watch(ctx context.Context, opts WatchOptions) error {
+	p := paths.New(a.Root)
+	if _, err := loadConfig(p.ConfigPath); err != nil {
+		return err
+	}
+	t := a.Theme
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = defaultWatchDebounce
+	}
+
+	watcher, err := watch.New([]string{p.OpenDir, p.ClosedDir})
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	fmt.Fprintf(a.Out, "%s %s %s\n", t.Bold("Watching"), relPath(a.Root, p.IssuesDir),
+		t.MutedText(fmt.Sprintf("(pulling every %s, press Ctrl-C to stop)", opts.Interval)))
+
+	// Confirmations are read in the background so that file events, pulls
+	// and cancellation are still handled while a prompt waits for an answer.
+	var answers <-chan string
+	if opts.Confirm {
+		answers = readLines(ctx, a.In)
+	}
+	asking := false
+	var debounce *time.Timer
+	var debounceC <-chan time.Time
+	schedulePush := func() {
+		if opts.NoPush {
+			return
+		}
+		if debounce == nil {
+			debounce = time.NewTimer(opts.Debounce)
+			debounceC = debounce.C
+		} else {
+			debounce.Reset(opts.Debounce)
+		}
+	}
+	push := func() {
+		if retry := a.watchStep(ctx, "Pushing", func() error { return a.Push(ctx, PushOptions{}, nil) }); retry {
+			schedulePush()
+		}
+		drainEvents(watcher.Events)
+	}
+	defer func() {
+		if debounce != nil {
+			debounce.Stop()
+		}
+	}()
+
+	// Local edits made while the watcher was not running are pushed right
+	// after the initial pull.
+	a.watchStep(ctx, "Pulling", func() error { return a.Pull(ctx, PullOptions{}, nil) })
+	drainEvents(watcher.Events)
+	if localChangeCount(p) > 0 {
+		schedulePush()
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case path, ok := <-watcher.Events:
+			if !ok {
+				return fmt.Errorf("file watcher stopped")
+			}
+			if strings.HasSuffix(path, ".md") {
+				schedulePush()
+			}
+		case <-debounceC:
+			changes := localChangeCount(p)
+			if changes == 0 {
+				continue
+			}
+			if opts.Confirm {
+				if asking {
+					continue
+				}
+				fmt.Fprintf(a.Out, "Push %s with local changes? [y/N] ", pluralize(changes, "issue"))
+				if answers == nil {
+					// Input is closed, so nobody can confirm
+					fmt.Fprintln(a.Out)
+					a.skipPush()
+					continue
+				}
+				asking = true
+				continue
+			}
+			push()
+		case answer, ok := <-answers:
+			if !ok {
+				answers = nil
+			}
+			if !asking {
+				// Input typed while nothing was asked is ignored
+				continue
+			}
+			asking = false
+			if !ok {
+				fmt.Fprintln(a.Out)
+			}
+			if !ok || !isYes(answer) {
+				a.skipPush()
+				continue
+			}
+			push()
+		case <-ticker.C:
+			a.watchStep(ctx, "Pulling", func() error { return a.Pull(ctx, PullOptions{}, nil) })
+			drainEvents(watcher.Events)
+		}
+	}
+}
+
+// watchStep runs a pull or push, reporting errors instead of stopping the
+// watch. It returns true if the step should be retried because another
+// command held the lock.
+func (a *App) watchStep(ctx context.Context, label string, step func() error) bool {
+	t := a.Theme
+	fmt.Fprintf(a.Out, "%s %s\n", t.MutedText(a.Now().Format("15:04:05")), label)
+	err := step()
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, lock.ErrTimeout):
+		fmt.Fprintf(a.Err, "%s another command is holding the lock; will retry\n", t.WarningText("Warning:"))
+		return true
+	case ctx.Err() != nil:
+		return false
+	}
+	fmt.Fprintf(a.Err, "%s %s failed: %v\n", t.WarningText("Warning:"), strings.ToLower(label), err)
+	return false
+}
+
+func (a *App) skipPush() {
+	fmt.Fprintln(a.Out, a.Theme.MutedText("Skipped push; changes stay local until the next edit or sync"))
+}
+
+func isYes(answer string) bool {
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// readLines reads lines from r in the background until it fails or ctx is
+// cancelled. The channel is closed at the end of input.
+func readLines(ctx context.Context, r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		input := bufio.NewReader(r)
+		for {
+			line, err := input.ReadString('\n')
+			if line != "" {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+// drainEvents discards queued file events, used after our own pulls and
+// pushes rewrote issue files.
+func drainEvents(events <-chan string) {
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+// localChangeCount returns the number of issues push would act on: new local
+// issues, issues that differ from their original and pending comments.
+func localChangeCount(p paths.Paths) int {
+	changed := make(map[string]struct{})
+	for _, item := range loadLocalIssuesWithErrors(p).Issues {
+		number := item.Issue.Number.String()
+		if item.Issue.Number.IsLocal() {
+			changed[number] = struct{}{}
+			continue
+		}
+		original, ok := readOriginalIssue(p, number)
+		if !ok || !issue.EqualIgnoringSyncedAt(item.Issue, original) {
+			changed[number] = struct{}{}
+		}
+	}
+	for number := range loadAllPendingComments(p) {
+		changed[number] = struct{}{}
+	}
+	return len(changed)
+}
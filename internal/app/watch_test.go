@@ -0,0 +1,164 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/config"
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+	"github.com/mitsuhiko/gh-issue-sync/internal/paths"
+)
+
+type offlineRunner struct{}
+
+func (offlineRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	return "", errors.New("offline")
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLocalChangeCount(t *testing.T) {
+	p := paths.New(t.TempDir())
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	unchanged := issue.Issue{Number: "1", Title: "Same", State: "open"}
+	edited := issue.Issue{Number: "2", Title: "Edited", State: "open"}
+	for _, iss := range []issue.Issue{unchanged, edited, {Number: "T1", Title: "New", State: "open"}} {
+		if err := issue.WriteFile(filepath.Join(p.OpenDir, iss.Number.String()+".md"), iss); err != nil {
+			t.Fatalf("write issue: %v", err)
+		}
+	}
+	edited.Title = "Before"
+	for _, original := range []issue.Issue{unchanged, edited} {
+		if err := writeOriginalIssue(p, original); err != nil {
+			t.Fatalf("write original: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(p.OpenDir, "1.comment.md"), []byte("Note"), 0o644); err != nil {
+		t.Fatalf("write comment: %v", err)
+	}
+	if got := localChangeCount(p); got != 3 {
+		t.Fatalf("expected 3 changed issues, got %d", got)
+	}
+}
+
+func TestWatchConfirmsPush(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+
+	var out syncBuffer
+	application := New(root, offlineRunner{}, &out, io.Discard)
+	application.In = strings.NewReader("n\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- application.Watch(ctx, WatchOptions{Interval: time.Hour, Debounce: 10 * time.Millisecond, Confirm: true})
+	}()
+
+	// Wait for the initial pull, then make a local edit
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "Pulling") {
+		if time.Now().After(deadline) {
+			t.Fatalf("watch did not start: %q", out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	local := issue.Issue{Number: "T1", Title: "New", State: "open"}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "T1-new.md"), local); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+	for !strings.Contains(out.String(), "Skipped push") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected push confirmation, got %q", out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !strings.Contains(out.String(), "Push 1 issue with local changes? [y/N]") {
+		t.Fatalf("unexpected prompt: %q", out.String())
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+}
+
+func TestWatchStopsWhileAskingToPush(t *testing.T) {
+	root := t.TempDir()
+	p := paths.New(root)
+	if err := p.EnsureLayout(); err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if err := config.Save(p.ConfigPath, config.Default("owner", "repo")); err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	local := issue.Issue{Number: "T1", Title: "New", State: "open"}
+	if err := issue.WriteFile(filepath.Join(p.OpenDir, "T1-new.md"), local); err != nil {
+		t.Fatalf("write issue: %v", err)
+	}
+
+	// Nobody answers the prompt
+	input, answer := io.Pipe()
+	defer answer.Close()
+	var out syncBuffer
+	application := New(root, offlineRunner{}, &out, io.Discard)
+	application.In = input
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- application.Watch(ctx, WatchOptions{Interval: time.Hour, Debounce: 10 * time.Millisecond, Confirm: true})
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "[y/N]") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected push confirmation, got %q", out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watch: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("watch did not stop while waiting for an answer")
+	}
+}
@@ -0,0 +1,406 @@
+// Package assets stores images and attachments referenced by issue bodies so
+// they are available offline.
+//
+// Files are content-addressed (named by the SHA-256 of their contents) so the
+// same attachment referenced from several issues is stored once. A manifest
+// maps each remote URL to its stored file.
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestFileName is the name of the manifest inside the assets directory.
+const ManifestFileName = "manifest.json"
+
+// MaxSize is the largest attachment that will be downloaded.
+const MaxSize = 100 << 20
+
+// urlPattern matches attachment URLs GitHub generates for uploads.
+var urlPattern = regexp.MustCompile(`https://(?:user-images\.githubusercontent\.com|private-user-images\.githubusercontent\.com|github\.com/user-attachments/(?:assets|files)|github\.com/[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+/(?:assets|files))/[^\s"'<>()\[\]]+`)
+
+// Image references: Markdown images and HTML img tags.
+var (
+	markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)`)
+	htmlImagePattern     = regexp.MustCompile(`<img\s[^>]*?src\s*=\s*["']([^"']+)["']`)
+)
+
+// IsLocalRef reports whether an image reference points at a local file
+// rather than a URL or an anchor.
+func IsLocalRef(ref string) bool {
+	if ref == "" || strings.Contains(ref, "://") {
+		return false
+	}
+	for _, prefix := range []string{"data:", "mailto:", "#", "/"} {
+		if strings.HasPrefix(ref, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindLocalImages returns the unique local image references in body, in
+// order of first appearance.
+func FindLocalImages(body string) []string {
+	var refs []string
+	seen := make(map[string]struct{})
+	for _, pattern := range []*regexp.Regexp{markdownImagePattern, htmlImagePattern} {
+		for _, match := range pattern.FindAllStringSubmatch(body, -1) {
+			ref := match[1]
+			if !IsLocalRef(ref) {
+				continue
+			}
+			if _, ok := seen[ref]; ok {
+				continue
+			}
+			seen[ref] = struct{}{}
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// ReplaceLocalImages replaces local image references in body with the value
+// returned by fn. References for which fn returns false are left alone.
+func ReplaceLocalImages(body string, fn func(ref string) (string, bool)) string {
+	for _, pattern := range []*regexp.Regexp{markdownImagePattern, htmlImagePattern} {
+		body = pattern.ReplaceAllStringFunc(body, func(match string) string {
+			sub := pattern.FindStringSubmatchIndex(match)
+			ref := match[sub[2]:sub[3]]
+			if !IsLocalRef(ref) {
+				return match
+			}
+			replacement, ok := fn(ref)
+			if !ok {
+				return match
+			}
+			return match[:sub[2]] + replacement + match[sub[3]:]
+		})
+	}
+	return body
+}
+
+// FindURLs returns the unique attachment URLs referenced in body, in order of
+// first appearance.
+func FindURLs(body string) []string {
+	var urls []string
+	seen := make(map[string]struct{})
+	for _, u := range urlPattern.FindAllString(body, -1) {
+		u, _ = splitTrailing(u)
+		if _, ok := seen[u]; ok {
+			continue
+		}
+		seen[u] = struct{}{}
+		urls = append(urls, u)
+	}
+	return urls
+}
+
+// Entry describes a stored attachment.
+type Entry struct {
+	File         string    `json:"file"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Size         int64     `json:"size"`
+	DownloadedAt time.Time `json:"downloaded_at"`
+}
+
+// Upload records a local file that was uploaded when pushing an issue.
+type Upload struct {
+	Issue      string    `json:"issue"` // Issue whose body references the file
+	Ref        string    `json:"ref"`   // Reference as written in the issue body
+	Path       string    `json:"path"`  // File path relative to the repository root
+	SHA256     string    `json:"sha256"`
+	URL        string    `json:"url"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// Manifest maps attachment URLs to stored files and remembers uploads.
+type Manifest struct {
+	Assets  map[string]Entry `json:"assets"`
+	Uploads []Upload         `json:"uploads,omitempty"`
+}
+
+// Store is a content-addressed attachment store backed by a directory.
+type Store struct {
+	Dir      string
+	Manifest Manifest
+}
+
+// Load opens the store in dir. A missing directory yields an empty store.
+func Load(dir string) (*Store, error) {
+	store := &Store{Dir: dir, Manifest: Manifest{Assets: map[string]Entry{}}}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.Manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse asset manifest: %w", err)
+	}
+	if store.Manifest.Assets == nil {
+		store.Manifest.Assets = map[string]Entry{}
+	}
+	return store, nil
+}
+
+// Save writes the manifest.
+func (s *Store) Save() error {
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	return os.WriteFile(filepath.Join(s.Dir, ManifestFileName), data, 0o644)
+}
+
+// Lookup returns the stored file name for a URL.
+func (s *Store) Lookup(rawURL string) (string, bool) {
+	entry, ok := s.Manifest.Assets[rawURL]
+	if !ok {
+		return "", false
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, entry.File)); err != nil {
+		return "", false
+	}
+	return entry.File, true
+}
+
+// Add stores data for a URL and returns the stored file name. Identical
+// contents are only stored once.
+func (s *Store) Add(rawURL string, data []byte, contentType string, now time.Time) (string, error) {
+	name := Hash(data) + extensionFor(rawURL, contentType)
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return "", err
+	}
+	target := filepath.Join(s.Dir, name)
+	if _, err := os.Stat(target); errors.Is(err, os.ErrNotExist) {
+		tmp := target + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o644); err != nil {
+			return "", err
+		}
+		if err := os.Rename(tmp, target); err != nil {
+			return "", err
+		}
+	}
+	s.Manifest.Assets[rawURL] = Entry{
+		File:         name,
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		DownloadedAt: now,
+	}
+	return name, nil
+}
+
+// Localize replaces stored attachment URLs in body with prefix + file name.
+func (s *Store) Localize(body, prefix string) string {
+	return urlPattern.ReplaceAllStringFunc(body, func(match string) string {
+		u, trailing := splitTrailing(match)
+		if name, ok := s.Lookup(u); ok {
+			return prefix + name + trailing
+		}
+		return match
+	})
+}
+
+// splitTrailing separates sentence punctuation that the URL pattern swallowed.
+func splitTrailing(match string) (string, string) {
+	u := strings.TrimRight(match, ".,;:!?")
+	return u, match[len(u):]
+}
+
+// Restore reverses Localize: references to prefix + file name are replaced
+// with the URL the file was downloaded from.
+func (s *Store) Restore(body, prefix string) string {
+	if !strings.Contains(body, prefix) {
+		return body
+	}
+	byFile := make(map[string]string, len(s.Manifest.Assets))
+	urls := make([]string, 0, len(s.Manifest.Assets))
+	for u := range s.Manifest.Assets {
+		urls = append(urls, u)
+	}
+	// The same file can come from several URLs; pick one deterministically.
+	sort.Strings(urls)
+	for _, u := range urls {
+		file := s.Manifest.Assets[u].File
+		if _, ok := byFile[file]; !ok {
+			byFile[file] = u
+		}
+	}
+	pattern := regexp.MustCompile(regexp.QuoteMeta(prefix) + `([0-9a-f]{64}(?:\.[A-Za-z0-9]+)?)`)
+	return pattern.ReplaceAllStringFunc(body, func(ref string) string {
+		if u, ok := byFile[strings.TrimPrefix(ref, prefix)]; ok {
+			return u
+		}
+		return ref
+	})
+}
+
+// FindUpload returns a previous upload of a file with the given hash.
+func (s *Store) FindUpload(sha string) (Upload, bool) {
+	for _, u := range s.Manifest.Uploads {
+		if u.SHA256 == sha {
+			return u, true
+		}
+	}
+	return Upload{}, false
+}
+
+// IssueUpload returns the upload of the file at path with the given hash
+// for an issue.
+func (s *Store) IssueUpload(issue, path, sha string) (Upload, bool) {
+	for _, u := range s.Manifest.Uploads {
+		if u.Issue == issue && u.Path == path && u.SHA256 == sha {
+			return u, true
+		}
+	}
+	return Upload{}, false
+}
+
+// LatestUpload returns the most recent upload recorded for a reference in an
+// issue.
+func (s *Store) LatestUpload(issue, ref string) (Upload, bool) {
+	var latest Upload
+	found := false
+	for _, u := range s.Manifest.Uploads {
+		if u.Issue == issue && u.Ref == ref && (!found || u.UploadedAt.After(latest.UploadedAt)) {
+			latest, found = u, true
+		}
+	}
+	return latest, found
+}
+
+// RecordUpload remembers an upload. Each issue/reference/URL combination is
+// kept once.
+func (s *Store) RecordUpload(upload Upload) {
+	for i, u := range s.Manifest.Uploads {
+		if u.Issue == upload.Issue && u.Ref == upload.Ref && u.URL == upload.URL {
+			s.Manifest.Uploads[i] = upload
+			return
+		}
+	}
+	s.Manifest.Uploads = append(s.Manifest.Uploads, upload)
+}
+
+// RenameUploads moves uploads recorded for local issue IDs to the numbers the
+// issues were created as (or back, when a push is undone). It reports whether
+// any upload changed.
+func (s *Store) RenameUploads(mapping map[string]string) bool {
+	changed := false
+	for i, u := range s.Manifest.Uploads {
+		if number, ok := mapping[u.Issue]; ok {
+			s.Manifest.Uploads[i].Issue = number
+			changed = true
+		}
+	}
+	return changed
+}
+
+// UnmapUploads replaces URLs of files uploaded for an issue in its body with
+// the local references they were uploaded from.
+func (s *Store) UnmapUploads(issue, body string) string {
+	for _, u := range s.Manifest.Uploads {
+		if u.Issue == issue {
+			body = strings.ReplaceAll(body, u.URL, u.Ref)
+		}
+	}
+	return body
+}
+
+// Hash returns the hex SHA-256 of data, as used for stored file names.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Download fetches an attachment. The token is only sent to GitHub hosts;
+// redirects to storage hosts drop it.
+func Download(ctx context.Context, client *http.Client, rawURL, token string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if token != "" && isGitHubHost(req.URL.Host) {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("download failed: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > MaxSize {
+		return nil, "", fmt.Errorf("attachment exceeds %d MB", MaxSize>>20)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	return data, contentType, nil
+}
+
+func isGitHubHost(host string) bool {
+	return host == "github.com" || strings.HasSuffix(host, ".githubusercontent.com")
+}
+
+// extensionFor picks a file extension from the URL path or the content type.
+func extensionFor(rawURL, contentType string) string {
+	if parsed, err := url.Parse(rawURL); err == nil {
+		ext := strings.ToLower(path.Ext(parsed.Path))
+		if len(ext) > 1 && len(ext) <= 6 && isAlnum(ext[1:]) {
+			return ext
+		}
+	}
+	switch contentType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/svg+xml":
+		return ".svg"
+	case "video/mp4":
+		return ".mp4"
+	}
+	if exts, err := mime.ExtensionsByType(contentType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+func isAlnum(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+package assets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindURLs(t *testing.T) {
+	body := `Screenshot: ![bug](https://github.com/user-attachments/assets/1234-abcd)
+Old one: <img src="https://user-images.githubusercontent.com/1/2/shot.png" width="300">
+See https://github.com/user-attachments/assets/1234-abcd.
+Not an attachment: https://example.com/image.png
+`
+	urls := FindURLs(body)
+	expected := []string{
+		"https://github.com/user-attachments/assets/1234-abcd",
+		"https://user-images.githubusercontent.com/1/2/shot.png",
+	}
+	if strings.Join(urls, " ") != strings.Join(expected, " ") {
+		t.Fatalf("unexpected urls: %v", urls)
+	}
+}
+
+func TestStoreDeduplicatesAndRewrites(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	first, err := store.Add("https://github.com/user-attachments/assets/a", []byte("png"), "image/png", now)
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	second, err := store.Add("https://user-images.githubusercontent.com/1/b.png", []byte("png"), "image/png", now)
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if first != second || !strings.HasSuffix(first, ".png") {
+		t.Fatalf("expected identical content to share a file, got %q and %q", first, second)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected one stored file, got %d", len(entries))
+	}
+
+	body := "![x](https://github.com/user-attachments/assets/a).\n"
+	local := store.Localize(body, "../assets/")
+	if local != "![x](../assets/"+first+").\n" {
+		t.Fatalf("unexpected localized body: %q", local)
+	}
+	if restored := store.Restore(local, "../assets/"); restored != body {
+		t.Fatalf("unexpected restored body: %q", restored)
+	}
+
+	if err := store.Save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	reloaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if name, ok := reloaded.Lookup("https://github.com/user-attachments/assets/a"); !ok || name != first {
+		t.Fatalf("manifest lookup failed: %q %v", name, ok)
+	}
+}
+
+func TestDownload(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "image/gif; charset=binary")
+		w.Write([]byte("GIF89a"))
+	}))
+	defer server.Close()
+
+	data, contentType, err := Download(context.Background(), server.Client(), server.URL+"/x", "secret")
+	if err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if string(data) != "GIF89a" || contentType != "image/gif" {
+		t.Fatalf("unexpected download: %q %q", data, contentType)
+	}
+	if gotAuth != "" {
+		t.Fatalf("token must not be sent to non-GitHub hosts, got %q", gotAuth)
+	}
+	if ext := extensionFor(server.URL+"/x", contentType); ext != ".gif" {
+		t.Fatalf("unexpected extension %q", ext)
+	}
+}
+
+func TestFindAndReplaceLocalImages(t *testing.T) {
+	body := `![shot](docs/shot.png) and <img src="./img/a b.jpg" width="10">
+![remote](https://example.com/x.png) ![again](docs/shot.png) [link](docs/shot.png)
+`
+	refs := FindLocalImages(body)
+	if strings.Join(refs, "|") != "docs/shot.png|./img/a b.jpg" {
+		t.Fatalf("unexpected refs: %q", refs)
+	}
+
+	replaced := ReplaceLocalImages(body, func(ref string) (string, bool) {
+		return "https://host/" + ref, ref == "docs/shot.png"
+	})
+	expected := `![shot](https://host/docs/shot.png) and <img src="./img/a b.jpg" width="10">
+![remote](https://example.com/x.png) ![again](https://host/docs/shot.png) [link](docs/shot.png)
+`
+	if replaced != expected {
+		t.Fatalf("unexpected body:\n%s", replaced)
+	}
+}
+
+func TestUploadsRoundTrip(t *testing.T) {
+	store, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	sha := Hash([]byte("png"))
+	store.RecordUpload(Upload{Issue: "1", Ref: "docs/shot.png", Path: "docs/shot.png", SHA256: sha, URL: "https://github.com/o/r/raw/b/" + sha + ".png"})
+	store.RecordUpload(Upload{Issue: "1", Ref: "docs/shot.png", Path: "docs/shot.png", SHA256: sha, URL: "https://github.com/o/r/raw/b/" + sha + ".png"})
+	if len(store.Manifest.Uploads) != 1 {
+		t.Fatalf("expected uploads to be deduplicated, got %d", len(store.Manifest.Uploads))
+	}
+	if u, ok := store.FindUpload(sha); !ok || u.Ref != "docs/shot.png" {
+		t.Fatalf("find upload failed: %+v %v", u, ok)
+	}
+	body := "![shot](https://github.com/o/r/raw/b/" + sha + ".png)"
+	if got := store.UnmapUploads("1", body); got != "![shot](docs/shot.png)" {
+		t.Fatalf("unexpected unmapped body: %q", got)
+	}
+
+	// The same file referenced differently from another issue
+	store.RecordUpload(Upload{Issue: "T1abc", Ref: "shot.png", Path: ".issues/open/shot.png", SHA256: sha, URL: "https://github.com/o/r/raw/b/" + sha + ".png"})
+	if !store.RenameUploads(map[string]string{"T1abc": "2"}) {
+		t.Fatalf("expected uploads to be renamed")
+	}
+	if got := store.UnmapUploads("2", body); got != "![shot](shot.png)" {
+		t.Fatalf("unexpected unmapped body: %q", got)
+	}
+	if got := store.UnmapUploads("3", body); got != body {
+		t.Fatalf("expected body of other issue untouched, got %q", got)
+	}
+	if _, ok := store.IssueUpload("2", "docs/shot.png", sha); ok {
+		t.Fatalf("expected upload to be scoped to its path")
+	}
+}
@@ -8,8 +8,11 @@ import (
 )
 
 type Config struct {
-	Repository RepoConfig `json:"repository"`
-	Sync       SyncConfig `json:"sync,omitempty"`
+	Repository RepoConfig      `json:"repository"`
+	Sync       SyncConfig      `json:"sync,omitempty"`
+	Branch     BranchConfig    `json:"branch,omitempty"`
+	Changelog  ChangelogConfig `json:"changelog,omitempty"`
+	Stale      StaleConfig     `json:"stale,omitempty"`
 }
 
 type RepoConfig struct {
@@ -19,6 +22,74 @@ type RepoConfig struct {
 
 type SyncConfig struct {
 	LastFullPull *time.Time `json:"last_full_pull,omitempty"`
+	// Attachments is "download" to store attachments referenced by issue
+	// bodies locally, or "rewrite" to also point local bodies at the copies.
+	Attachments string `json:"attachments,omitempty"`
+	// UploadImages controls whether push uploads images that local issue
+	// bodies reference by path to AttachmentBranch (default true). Set it to
+	// false to push local image paths as they are.
+	UploadImages *bool `json:"upload_images,omitempty"`
+	// AttachmentBranch hosts images uploaded from local issue bodies
+	// (default "issue-attachments").
+	AttachmentBranch string `json:"attachment_branch,omitempty"`
+	// RewriteRefs lists globs of files in the git working tree in which push
+	// rewrites local issue references (#T1a2b) once the issue was created,
+	// like ["*.go", "docs/**"]. Without it only push --rewrite-refs does.
+	RewriteRefs []string `json:"rewrite_refs,omitempty"`
+}
+
+type BranchConfig struct {
+	// Pattern names the branches created by start. {number} is replaced
+	// with the issue number and {slug} with the slugified title
+	// (default "{number}-{slug}").
+	Pattern string `json:"pattern,omitempty"`
+	// Label is added to issues when work on them starts
+	// (default "in-progress").
+	Label string `json:"label,omitempty"`
+}
+
+type ChangelogConfig struct {
+	// Sections group closed issues in the changelog, in order. An issue goes
+	// into the first section matching one of its labels or its issue type;
+	// the others are listed under "Other".
+	Sections []ChangelogSection `json:"sections,omitempty"`
+	// Exclude lists labels of issues left out of the changelog.
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+type ChangelogSection struct {
+	Title  string   `json:"title"`
+	Labels []string `json:"labels,omitempty"`
+	Types  []string `json:"types,omitempty"`
+}
+
+type StaleConfig struct {
+	// Policies are evaluated in order by the stale command; an open issue
+	// follows the first policy it matches.
+	Policies []StalePolicy `json:"policies,omitempty"`
+}
+
+type StalePolicy struct {
+	Name string `json:"name,omitempty"`
+	// Labels limits the policy to issues with one of these labels; without
+	// it the policy applies to all open issues.
+	Labels []string `json:"labels,omitempty"`
+	// ExemptLabels lists labels of issues the policy never touches.
+	ExemptLabels []string `json:"exempt_labels,omitempty"`
+	// Days without an update after which an issue is marked stale.
+	Days int `json:"days"`
+	// Label marks stale issues (default "stale").
+	Label string `json:"label,omitempty"`
+	// Comment is queued when an issue is marked stale.
+	Comment string `json:"comment,omitempty"`
+	// CloseAfterDays closes stale issues without an update for that many
+	// more days; 0 never closes them.
+	CloseAfterDays int `json:"close_after_days,omitempty"`
+	// CloseReason is the state reason of closed issues
+	// (default "not_planned").
+	CloseReason string `json:"close_reason,omitempty"`
+	// CloseComment is queued when an issue is closed.
+	CloseComment string `json:"close_comment,omitempty"`
 }
 
 func Default(owner, repo string) Config {
@@ -72,6 +72,24 @@ func (c *Client) HasProjectScope(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+// AuthToken returns the token gh uses for the current host.
+func (c *Client) AuthToken(ctx context.Context) (string, error) {
+	out, err := c.runner.Run(ctx, "gh", "auth", "token")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// CurrentUser returns the login of the authenticated user.
+func (c *Client) CurrentUser(ctx context.Context) (string, error) {
+	out, err := c.runner.Run(ctx, "gh", "api", "user", "-q", ".login")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
 func (c *Client) withRepo(args []string) []string {
 	if c.repo == "" {
 		return args
@@ -115,6 +133,7 @@ type apiIssue struct {
 	Author      *apiUser      `json:"author"`
 	CreatedAt   string        `json:"createdAt"`
 	UpdatedAt   string        `json:"updatedAt"`
+	ClosedAt    string        `json:"closedAt"`
 }
 
 func (a apiIssue) ToIssue() issue.Issue {
@@ -155,6 +174,11 @@ func (a apiIssue) ToIssue() issue.Issue {
 			iss.UpdatedAt = &t
 		}
 	}
+	if a.ClosedAt != "" {
+		if t, err := time.Parse(time.RFC3339, a.ClosedAt); err == nil {
+			iss.ClosedAt = &t
+		}
+	}
 	return iss
 }
 
@@ -163,6 +187,17 @@ func (c *Client) ListIssues(ctx context.Context, state string, labels []string)
 	for _, label := range labels {
 		args = append(args, "--label", label)
 	}
+	return c.listIssues(ctx, args)
+}
+
+// SearchIssues returns issues in any state matching a GitHub search query.
+// Search results lag behind issue changes by a few seconds.
+func (c *Client) SearchIssues(ctx context.Context, query string) ([]issue.Issue, error) {
+	args := []string{"issue", "list", "--state", "all", "--search", query, "--limit", "100", "--json", "number,title,body,labels,assignees,milestone,state,stateReason,author"}
+	return c.listIssues(ctx, args)
+}
+
+func (c *Client) listIssues(ctx context.Context, args []string) ([]issue.Issue, error) {
 	out, err := c.runner.Run(ctx, "gh", c.withRepo(args)...)
 	if err != nil {
 		return nil, err
@@ -272,15 +307,17 @@ func (c *Client) ListIssuesWithRelationships(ctx context.Context, opts ListIssue
         stateReason
         createdAt
         updatedAt
+        closedAt
         author { login }
         labels(first: 100) { nodes { name } }
         assignees(first: 100) { nodes { login } }
         milestone { title }
         issueType { name }
-        projectItems(first: 20) { nodes { project { title } } }
+        `+projectItemsQuery+`
         parent { number }
         blockedBy(first: 100) { nodes { number } }
         blocking(first: 100) { nodes { number } }
+        `+linkedPullRequestsQuery+`
       }
     }
   }
@@ -320,6 +357,7 @@ func (c *Client) ListIssuesWithRelationships(ctx context.Context, opts ListIssue
 							EndCursor   string `json:"endCursor"`
 						} `json:"pageInfo"`
 						Nodes []struct {
+							linkedPullRequests
 							Number      int     `json:"number"`
 							Title       string  `json:"title"`
 							Body        string  `json:"body"`
@@ -327,6 +365,7 @@ func (c *Client) ListIssuesWithRelationships(ctx context.Context, opts ListIssue
 							StateReason *string `json:"stateReason"`
 							CreatedAt   string  `json:"createdAt"`
 							UpdatedAt   string  `json:"updatedAt"`
+							ClosedAt    string  `json:"closedAt"`
 							Author      *struct {
 								Login string `json:"login"`
 							} `json:"author"`
@@ -346,14 +385,8 @@ func (c *Client) ListIssuesWithRelationships(ctx context.Context, opts ListIssue
 							IssueType *struct {
 								Name string `json:"name"`
 							} `json:"issueType"`
-							ProjectItems *struct {
-								Nodes []struct {
-									Project struct {
-										Title string `json:"title"`
-									} `json:"project"`
-								} `json:"nodes"`
-							} `json:"projectItems"`
-							Parent *struct {
+							ProjectItems *projectItems `json:"projectItems"`
+							Parent       *struct {
 								Number int `json:"number"`
 							} `json:"parent"`
 							BlockedBy struct {
@@ -410,12 +443,8 @@ func (c *Client) ListIssuesWithRelationships(ctx context.Context, opts ListIssue
 				issueType = node.IssueType.Name
 			}
 
-			var projects []string
-			if node.ProjectItems != nil {
-				for _, pi := range node.ProjectItems.Nodes {
-					projects = append(projects, pi.Project.Title)
-				}
-			}
+			projects := node.ProjectItems.titles()
+			projectFields := node.ProjectItems.fields()
 
 			author := ""
 			if node.Author != nil {
@@ -423,17 +452,19 @@ func (c *Client) ListIssuesWithRelationships(ctx context.Context, opts ListIssue
 			}
 
 			iss := issue.Issue{
-				Number:      issue.IssueNumber(strconv.Itoa(node.Number)),
-				Title:       node.Title,
-				Body:        node.Body,
-				State:       strings.ToLower(node.State),
-				StateReason: node.StateReason,
-				Labels:      issLabels,
-				Assignees:   assignees,
-				Milestone:   milestone,
-				IssueType:   issueType,
-				Projects:    projects,
-				Author:      author,
+				Number:        issue.IssueNumber(strconv.Itoa(node.Number)),
+				Title:         node.Title,
+				Body:          node.Body,
+				State:         strings.ToLower(node.State),
+				StateReason:   node.StateReason,
+				Labels:        issLabels,
+				Assignees:     assignees,
+				Milestone:     milestone,
+				IssueType:     issueType,
+				Projects:      projects,
+				ProjectFields: projectFields,
+				Author:        author,
+				LinkedPRs:     node.linkedPRs(c.repo),
 			}
 
 			// Parse timestamps
@@ -447,6 +478,11 @@ func (c *Client) ListIssuesWithRelationships(ctx context.Context, opts ListIssue
 					iss.UpdatedAt = &t
 				}
 			}
+			if node.ClosedAt != "" {
+				if t, err := time.Parse(time.RFC3339, node.ClosedAt); err == nil {
+					iss.ClosedAt = &t
+				}
+			}
 
 			if node.Parent != nil {
 				ref := issue.IssueRef(strconv.Itoa(node.Parent.Number))
@@ -497,6 +533,8 @@ func (c *Client) EnrichWithRelationships(ctx context.Context, iss *issue.Issue)
 	iss.Blocks = rels.Blocks
 	iss.IssueType = rels.IssueType
 	iss.Projects = rels.Projects
+	iss.ProjectFields = rels.ProjectFields
+	iss.LinkedPRs = rels.LinkedPRs
 	return nil
 }
 
@@ -531,6 +569,8 @@ func (c *Client) EnrichWithRelationshipsBatch(ctx context.Context, issues []issu
 			issues[i].Blocks = rel.Blocks
 			issues[i].IssueType = rel.IssueType
 			issues[i].Projects = rel.Projects
+			issues[i].ProjectFields = rel.ProjectFields
+			issues[i].LinkedPRs = rel.LinkedPRs
 		}
 	}
 
@@ -538,7 +578,7 @@ func (c *Client) EnrichWithRelationshipsBatch(ctx context.Context, issues []issu
 }
 
 func (c *Client) GetIssue(ctx context.Context, number string) (issue.Issue, error) {
-	args := []string{"issue", "view", number, "--json", "number,title,body,labels,assignees,milestone,state,stateReason,author,createdAt,updatedAt"}
+	args := []string{"issue", "view", number, "--json", "number,title,body,labels,assignees,milestone,state,stateReason,author,createdAt,updatedAt,closedAt"}
 	out, err := c.runner.Run(ctx, "gh", c.withRepo(args)...)
 	if err != nil {
 		return issue.Issue{}, err
@@ -608,15 +648,17 @@ func (c *Client) getIssuesBatchChunk(ctx context.Context, numbers []string) (map
       stateReason
       createdAt
       updatedAt
+      closedAt
       author { login }
       labels(first: 100) { nodes { name } }
       assignees(first: 100) { nodes { login } }
       milestone { title }
       issueType { name }
-      projectItems(first: 20) { nodes { project { title } } }
+      `+projectItemsQuery+`
       parent { number }
       blockedBy(first: 100) { nodes { number } }
       blocking(first: 100) { nodes { number } }
+      `+linkedPullRequestsQuery+`
     }`, i, n))
 	}
 
@@ -668,6 +710,7 @@ func (c *Client) getIssuesBatchChunk(ctx context.Context, numbers []string) (map
 		}
 
 		var issueData struct {
+			linkedPullRequests
 			Number      int     `json:"number"`
 			Title       string  `json:"title"`
 			Body        string  `json:"body"`
@@ -675,6 +718,7 @@ func (c *Client) getIssuesBatchChunk(ctx context.Context, numbers []string) (map
 			StateReason *string `json:"stateReason"`
 			CreatedAt   string  `json:"createdAt"`
 			UpdatedAt   string  `json:"updatedAt"`
+			ClosedAt    string  `json:"closedAt"`
 			Author      *struct {
 				Login string `json:"login"`
 			} `json:"author"`
@@ -694,14 +738,8 @@ func (c *Client) getIssuesBatchChunk(ctx context.Context, numbers []string) (map
 			IssueType *struct {
 				Name string `json:"name"`
 			} `json:"issueType"`
-			ProjectItems *struct {
-				Nodes []struct {
-					Project struct {
-						Title string `json:"title"`
-					} `json:"project"`
-				} `json:"nodes"`
-			} `json:"projectItems"`
-			Parent *struct {
+			ProjectItems *projectItems `json:"projectItems"`
+			Parent       *struct {
 				Number int `json:"number"`
 			} `json:"parent"`
 			BlockedBy struct {
@@ -735,12 +773,8 @@ func (c *Client) getIssuesBatchChunk(ctx context.Context, numbers []string) (map
 		if issueData.IssueType != nil {
 			issueType = issueData.IssueType.Name
 		}
-		var projects []string
-		if issueData.ProjectItems != nil {
-			for _, pi := range issueData.ProjectItems.Nodes {
-				projects = append(projects, pi.Project.Title)
-			}
-		}
+		projects := issueData.ProjectItems.titles()
+		projectFields := issueData.ProjectItems.fields()
 
 		author := ""
 		if issueData.Author != nil {
@@ -748,17 +782,19 @@ func (c *Client) getIssuesBatchChunk(ctx context.Context, numbers []string) (map
 		}
 
 		iss := issue.Issue{
-			Number:      issue.IssueNumber(strconv.Itoa(issueData.Number)),
-			Title:       issueData.Title,
-			Body:        issueData.Body,
-			State:       strings.ToLower(issueData.State),
-			StateReason: issueData.StateReason,
-			Labels:      labels,
-			Assignees:   assignees,
-			Milestone:   milestone,
-			IssueType:   issueType,
-			Projects:    projects,
-			Author:      author,
+			Number:        issue.IssueNumber(strconv.Itoa(issueData.Number)),
+			Title:         issueData.Title,
+			Body:          issueData.Body,
+			State:         strings.ToLower(issueData.State),
+			StateReason:   issueData.StateReason,
+			Labels:        labels,
+			Assignees:     assignees,
+			Milestone:     milestone,
+			IssueType:     issueType,
+			Projects:      projects,
+			ProjectFields: projectFields,
+			Author:        author,
+			LinkedPRs:     issueData.linkedPRs(c.repo),
 		}
 
 		// Parse timestamps
@@ -772,6 +808,11 @@ func (c *Client) getIssuesBatchChunk(ctx context.Context, numbers []string) (map
 				iss.UpdatedAt = &t
 			}
 		}
+		if issueData.ClosedAt != "" {
+			if t, err := time.Parse(time.RFC3339, issueData.ClosedAt); err == nil {
+				iss.ClosedAt = &t
+			}
+		}
 
 		if issueData.Parent != nil {
 			ref := issue.IssueRef(strconv.Itoa(issueData.Parent.Number))
@@ -1542,3 +1583,25 @@ func (c *Client) CreateComment(ctx context.Context, issueNumber string, body str
 	_, err := c.runner.Run(ctx, "gh", c.withRepo(args)...)
 	return err
 }
+
+// ListCommentBodies returns the bodies of all comments on an issue.
+func (c *Client) ListCommentBodies(ctx context.Context, issueNumber string) ([]string, error) {
+	args := []string{"issue", "view", issueNumber, "--json", "comments"}
+	out, err := c.runner.Run(ctx, "gh", c.withRepo(args)...)
+	if err != nil {
+		return nil, err
+	}
+	var payload struct {
+		Comments []struct {
+			Body string `json:"body"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		return nil, err
+	}
+	bodies := make([]string, 0, len(payload.Comments))
+	for _, comment := range payload.Comments {
+		bodies = append(bodies, comment.Body)
+	}
+	return bodies, nil
+}
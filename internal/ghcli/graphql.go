@@ -17,23 +17,21 @@ type IssueRelationships struct {
 	Blocks    []issue.IssueRef
 	IssueType string
 	Projects  []string
+	// ProjectFields holds custom field values per project
+	ProjectFields issue.ProjectFields
+	LinkedPRs     []issue.LinkedPR
 }
 
 // graphqlIssue represents the GraphQL response structure for an issue.
 type graphqlIssue struct {
+	linkedPullRequests
 	ID        string `json:"id"`
 	Number    int    `json:"number"`
 	IssueType *struct {
 		Name string `json:"name"`
 	} `json:"issueType"`
-	ProjectItems *struct {
-		Nodes []struct {
-			Project struct {
-				Title string `json:"title"`
-			} `json:"project"`
-		} `json:"nodes"`
-	} `json:"projectItems"`
-	Parent *struct {
+	ProjectItems *projectItems `json:"projectItems"`
+	Parent       *struct {
 		Number int    `json:"number"`
 		ID     string `json:"id"`
 	} `json:"parent"`
@@ -134,11 +132,7 @@ func (c *Client) getIssueRelationshipsBatchChunk(ctx context.Context, numbers []
       id
       number
       issueType { name }
-      projectItems(first: 20) {
-        nodes {
-          project { title }
-        }
-      }
+      `+projectItemsQuery+`
       parent {
         number
         id
@@ -155,6 +149,7 @@ func (c *Client) getIssueRelationshipsBatchChunk(ctx context.Context, numbers []
           id
         }
       }
+      `+linkedPullRequestsQuery+`
     }`, i, n))
 	}
 
@@ -225,11 +220,9 @@ func (c *Client) getIssueRelationshipsBatchChunk(ctx context.Context, numbers []
 		if issueData.IssueType != nil {
 			rels.IssueType = issueData.IssueType.Name
 		}
-		if issueData.ProjectItems != nil {
-			for _, node := range issueData.ProjectItems.Nodes {
-				rels.Projects = append(rels.Projects, node.Project.Title)
-			}
-		}
+		rels.Projects = issueData.ProjectItems.titles()
+		rels.ProjectFields = issueData.ProjectItems.fields()
+		rels.LinkedPRs = issueData.linkedPRs(c.repo)
 		if issueData.Parent != nil {
 			ref := issue.IssueRef(strconv.Itoa(issueData.Parent.Number))
 			rels.Parent = &ref
@@ -0,0 +1,101 @@
+package ghcli
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+)
+
+// linkedPullRequestsQuery selects the pull requests that close an issue and
+// those that mention it.
+const linkedPullRequestsQuery = `closedByPullRequestsReferences(first: 20, includeClosedPrs: true) {
+        nodes { number title state merged headRefName repository { nameWithOwner } }
+      }
+      timelineItems(last: 50, itemTypes: [CROSS_REFERENCED_EVENT]) {
+        nodes {
+          ... on CrossReferencedEvent {
+            willCloseTarget
+            source { ... on PullRequest { number title state merged headRefName repository { nameWithOwner } } }
+          }
+        }
+      }`
+
+// graphqlPullRequest is a pull request as selected by linkedPullRequestsQuery.
+type graphqlPullRequest struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	State       string `json:"state"`
+	Merged      bool   `json:"merged"`
+	HeadRefName string `json:"headRefName"`
+	Repository  struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+// linkedPullRequests is the response shape of linkedPullRequestsQuery. It is
+// embedded in the issue node structs.
+type linkedPullRequests struct {
+	ClosedBy *struct {
+		Nodes []graphqlPullRequest `json:"nodes"`
+	} `json:"closedByPullRequestsReferences"`
+	TimelineItems *struct {
+		Nodes []struct {
+			WillCloseTarget bool               `json:"willCloseTarget"`
+			Source          graphqlPullRequest `json:"source"`
+		} `json:"nodes"`
+	} `json:"timelineItems"`
+}
+
+// linkedPRs returns the linked pull requests, closing references first and
+// then by number. Pull requests in repo are returned without repository.
+func (l linkedPullRequests) linkedPRs(repo string) []issue.LinkedPR {
+	var prs []issue.LinkedPR
+	index := map[string]int{}
+	add := func(pr graphqlPullRequest, closes bool) {
+		if pr.Number == 0 {
+			// Cross-references from issues have an empty source
+			return
+		}
+		linked := issue.LinkedPR{
+			Number: pr.Number,
+			Title:  pr.Title,
+			State:  "open",
+			Merged: pr.Merged || pr.State == "MERGED",
+			Branch: pr.HeadRefName,
+			Closes: closes,
+		}
+		if pr.State != "OPEN" {
+			linked.State = "closed"
+		}
+		if !strings.EqualFold(pr.Repository.NameWithOwner, repo) {
+			linked.Repository = pr.Repository.NameWithOwner
+		}
+		if i, ok := index[linked.Ref()]; ok {
+			prs[i].Closes = prs[i].Closes || closes
+			return
+		}
+		index[linked.Ref()] = len(prs)
+		prs = append(prs, linked)
+	}
+	if l.ClosedBy != nil {
+		for _, pr := range l.ClosedBy.Nodes {
+			add(pr, true)
+		}
+	}
+	if l.TimelineItems != nil {
+		for _, node := range l.TimelineItems.Nodes {
+			add(node.Source, node.WillCloseTarget)
+		}
+	}
+	sort.SliceStable(prs, func(i, j int) bool {
+		if prs[i].Closes != prs[j].Closes {
+			return prs[i].Closes
+		}
+		if prs[i].Repository != prs[j].Repository {
+			return prs[i].Repository < prs[j].Repository
+		}
+		return prs[i].Number < prs[j].Number
+	})
+	return prs
+}
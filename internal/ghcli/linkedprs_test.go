@@ -0,0 +1,37 @@
+package ghcli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLinkedPullRequests(t *testing.T) {
+	payload := `{
+		"closedByPullRequestsReferences": {"nodes": [
+			{"number": 12, "title": "Fix crash", "state": "MERGED", "merged": true, "headRefName": "fix-crash", "repository": {"nameWithOwner": "octo/repo"}}
+		]},
+		"timelineItems": {"nodes": [
+			{"willCloseTarget": false, "source": {}},
+			{"willCloseTarget": false, "source": {"number": 15, "title": "Refactor", "state": "OPEN", "merged": false, "headRefName": "refactor", "repository": {"nameWithOwner": "octo/repo"}}},
+			{"willCloseTarget": true, "source": {"number": 12, "title": "Fix crash", "state": "MERGED", "merged": true, "headRefName": "fix-crash", "repository": {"nameWithOwner": "octo/repo"}}},
+			{"willCloseTarget": false, "source": {"number": 3, "title": "Workaround", "state": "CLOSED", "merged": false, "headRefName": "wip", "repository": {"nameWithOwner": "octo/other"}}}
+		]}
+	}`
+	var linked linkedPullRequests
+	if err := json.Unmarshal([]byte(payload), &linked); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	prs := linked.linkedPRs("Octo/Repo")
+	if len(prs) != 3 {
+		t.Fatalf("expected issue references to be skipped and duplicates merged, got %+v", prs)
+	}
+	if prs[0].Number != 12 || !prs[0].Closes || !prs[0].Merged || prs[0].State != "closed" || prs[0].Repository != "" {
+		t.Fatalf("unexpected closing PR: %+v", prs[0])
+	}
+	if prs[1].Number != 15 || prs[1].State != "open" || prs[1].Branch != "refactor" {
+		t.Fatalf("unexpected mentioning PR: %+v", prs[1])
+	}
+	if prs[2].Ref() != "octo/other#3" || prs[2].State != "closed" {
+		t.Fatalf("unexpected cross-repository PR: %+v", prs[2])
+	}
+}
@@ -0,0 +1,362 @@
+package ghcli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
+)
+
+// projectItemsQuery selects the projects an issue belongs to together with
+// the custom field values set on each project item.
+const projectItemsQuery = `projectItems(first: 20) {
+        nodes {
+          project { title }
+          fieldValues(first: 50) {
+            nodes {
+              __typename
+              ... on ProjectV2ItemFieldSingleSelectValue { name field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldIterationValue { title field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldNumberValue { number field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldTextValue { text field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldDateValue { date field { ... on ProjectV2FieldCommon { name } } }
+            }
+          }
+        }
+      }`
+
+// projectItems is the response shape of projectItemsQuery.
+type projectItems struct {
+	Nodes []struct {
+		Project struct {
+			Title string `json:"title"`
+		} `json:"project"`
+		FieldValues struct {
+			Nodes []struct {
+				Typename string   `json:"__typename"`
+				Name     string   `json:"name"`
+				Title    string   `json:"title"`
+				Number   *float64 `json:"number"`
+				Text     string   `json:"text"`
+				Date     string   `json:"date"`
+				Field    struct {
+					Name string `json:"name"`
+				} `json:"field"`
+			} `json:"nodes"`
+		} `json:"fieldValues"`
+	} `json:"nodes"`
+}
+
+// titles returns the titles of the projects the issue belongs to.
+func (p *projectItems) titles() []string {
+	if p == nil {
+		return nil
+	}
+	var titles []string
+	for _, item := range p.Nodes {
+		titles = append(titles, item.Project.Title)
+	}
+	return titles
+}
+
+// fields returns the custom field values per project. The built-in Title
+// field mirrors the issue title and is skipped.
+func (p *projectItems) fields() issue.ProjectFields {
+	if p == nil {
+		return nil
+	}
+	fields := issue.ProjectFields{}
+	for _, item := range p.Nodes {
+		for _, v := range item.FieldValues.Nodes {
+			name := v.Field.Name
+			var value string
+			switch v.Typename {
+			case "ProjectV2ItemFieldSingleSelectValue":
+				value = v.Name
+			case "ProjectV2ItemFieldIterationValue":
+				value = v.Title
+			case "ProjectV2ItemFieldNumberValue":
+				if v.Number != nil {
+					value = strconv.FormatFloat(*v.Number, 'f', -1, 64)
+				}
+			case "ProjectV2ItemFieldTextValue":
+				if name == "Title" {
+					continue
+				}
+				value = v.Text
+			case "ProjectV2ItemFieldDateValue":
+				value = v.Date
+			}
+			if name == "" || value == "" {
+				continue
+			}
+			if fields[item.Project.Title] == nil {
+				fields[item.Project.Title] = map[string]string{}
+			}
+			fields[item.Project.Title][name] = value
+		}
+	}
+	return fields.Normalized()
+}
+
+// ProjectField describes a custom field of a project.
+type ProjectField struct {
+	ID       string               `json:"id"`
+	Name     string               `json:"name"`
+	DataType string               `json:"data_type"` // SINGLE_SELECT, ITERATION, NUMBER, TEXT, DATE, ...
+	Options  []ProjectFieldOption `json:"options,omitempty"`
+}
+
+// ProjectFieldOption is a single-select option or an iteration.
+type ProjectFieldOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ListProjectFields fetches the field definitions of a project.
+func (c *Client) ListProjectFields(ctx context.Context, projectID string) ([]ProjectField, error) {
+	query := `query($projectId: ID!) {
+  node(id: $projectId) {
+    ... on ProjectV2 {
+      fields(first: 50) {
+        nodes {
+          ... on ProjectV2Field { id name dataType }
+          ... on ProjectV2SingleSelectField { id name dataType options { id name } }
+          ... on ProjectV2IterationField {
+            id name dataType
+            configuration {
+              iterations { id title }
+              completedIterations { id title }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+	args := []string{"api", "graphql",
+		"-f", fmt.Sprintf("query=%s", query),
+		"-f", fmt.Sprintf("projectId=%s", projectID),
+	}
+
+	out, err := c.runner.Run(ctx, "gh", args...)
+	if err != nil {
+		if strings.Contains(err.Error(), "INSUFFICIENT_SCOPES") {
+			return nil, ErrMissingProjectScope
+		}
+		return nil, err
+	}
+
+	type iteration struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+	}
+	var resp struct {
+		Data struct {
+			Node struct {
+				Fields struct {
+					Nodes []struct {
+						ID       string `json:"id"`
+						Name     string `json:"name"`
+						DataType string `json:"dataType"`
+						Options  []struct {
+							ID   string `json:"id"`
+							Name string `json:"name"`
+						} `json:"options"`
+						Configuration *struct {
+							Iterations          []iteration `json:"iterations"`
+							CompletedIterations []iteration `json:"completedIterations"`
+						} `json:"configuration"`
+					} `json:"nodes"`
+				} `json:"fields"`
+			} `json:"node"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+	for _, e := range resp.Errors {
+		if e.Type == "INSUFFICIENT_SCOPES" {
+			return nil, ErrMissingProjectScope
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL error: %s", resp.Errors[0].Message)
+	}
+
+	var fields []ProjectField
+	for _, node := range resp.Data.Node.Fields.Nodes {
+		if node.ID == "" {
+			continue
+		}
+		field := ProjectField{ID: node.ID, Name: node.Name, DataType: node.DataType}
+		for _, opt := range node.Options {
+			field.Options = append(field.Options, ProjectFieldOption{ID: opt.ID, Name: opt.Name})
+		}
+		if node.Configuration != nil {
+			for _, it := range append(node.Configuration.Iterations, node.Configuration.CompletedIterations...) {
+				field.Options = append(field.Options, ProjectFieldOption{ID: it.ID, Name: it.Title})
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// ProjectFieldUpdate sets (or clears, if Value is empty) one field of the
+// item an issue has in a project.
+type ProjectFieldUpdate struct {
+	ProjectID string
+	Field     ProjectField
+	Value     string
+}
+
+// valueArgs returns the gh api arguments for the ProjectV2FieldValue input.
+func (u ProjectFieldUpdate) valueArgs() ([]string, error) {
+	switch u.Field.DataType {
+	case "SINGLE_SELECT", "ITERATION":
+		for _, opt := range u.Field.Options {
+			if strings.EqualFold(opt.Name, u.Value) {
+				key := "singleSelectOptionId"
+				if u.Field.DataType == "ITERATION" {
+					key = "iterationId"
+				}
+				return []string{"-f", fmt.Sprintf("value[%s]=%s", key, opt.ID)}, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not a valid value for %s", u.Value, u.Field.Name)
+	case "NUMBER":
+		if _, err := strconv.ParseFloat(u.Value, 64); err != nil {
+			return nil, fmt.Errorf("%s must be a number, got %q", u.Field.Name, u.Value)
+		}
+		return []string{"-F", fmt.Sprintf("value[number]=%s", u.Value)}, nil
+	case "DATE":
+		if len(u.Value) != len("2006-01-02") {
+			return nil, fmt.Errorf("%s must be a date (YYYY-MM-DD), got %q", u.Field.Name, u.Value)
+		}
+		return []string{"-f", fmt.Sprintf("value[date]=%s", u.Value)}, nil
+	case "TEXT":
+		return []string{"-f", fmt.Sprintf("value[text]=%s", u.Value)}, nil
+	}
+	return nil, fmt.Errorf("field %s (%s) cannot be edited", u.Field.Name, u.Field.DataType)
+}
+
+// UpdateProjectFields applies field updates to the project items of an issue.
+// The issue must already be in the projects being updated.
+func (c *Client) UpdateProjectFields(ctx context.Context, issueNumber string, updates []ProjectFieldUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	issueNodeID, err := c.GetIssueNodeID(ctx, issueNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get issue node ID: %w", err)
+	}
+	itemIDs, err := c.projectItemIDs(ctx, issueNodeID)
+	if err != nil {
+		return err
+	}
+
+	for _, update := range updates {
+		itemID, ok := itemIDs[update.ProjectID]
+		if !ok {
+			return fmt.Errorf("issue #%s is not in the project of field %s", issueNumber, update.Field.Name)
+		}
+		var args []string
+		if update.Value == "" {
+			mutation := `mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!) {
+  clearProjectV2ItemFieldValue(input: {projectId: $projectId, itemId: $itemId, fieldId: $fieldId}) {
+    projectV2Item { id }
+  }
+}`
+			args = []string{"api", "graphql", "-f", fmt.Sprintf("query=%s", mutation)}
+		} else {
+			valueArgs, err := update.valueArgs()
+			if err != nil {
+				return err
+			}
+			mutation := `mutation($projectId: ID!, $itemId: ID!, $fieldId: ID!, $value: ProjectV2FieldValue!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $projectId, itemId: $itemId, fieldId: $fieldId, value: $value}) {
+    projectV2Item { id }
+  }
+}`
+			args = append([]string{"api", "graphql", "-f", fmt.Sprintf("query=%s", mutation)}, valueArgs...)
+		}
+		args = append(args,
+			"-f", fmt.Sprintf("projectId=%s", update.ProjectID),
+			"-f", fmt.Sprintf("itemId=%s", itemID),
+			"-f", fmt.Sprintf("fieldId=%s", update.Field.ID),
+		)
+		out, err := c.runner.Run(ctx, "gh", args...)
+		if err != nil {
+			if strings.Contains(err.Error(), "INSUFFICIENT_SCOPES") {
+				return ErrMissingProjectScope
+			}
+			return err
+		}
+		var resp graphqlMutationResponse
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			return fmt.Errorf("failed to parse GraphQL response: %w", err)
+		}
+		if len(resp.Errors) > 0 {
+			return fmt.Errorf("GraphQL error: %s", resp.Errors[0].Message)
+		}
+	}
+	return nil
+}
+
+// projectItemIDs returns the project item ID of an issue per project ID.
+func (c *Client) projectItemIDs(ctx context.Context, issueNodeID string) (map[string]string, error) {
+	query := `query($issueId: ID!) {
+  node(id: $issueId) {
+    ... on Issue {
+      projectItems(first: 100) {
+        nodes {
+          id
+          project { id }
+        }
+      }
+    }
+  }
+}`
+
+	args := []string{"api", "graphql",
+		"-f", fmt.Sprintf("query=%s", query),
+		"-f", fmt.Sprintf("issueId=%s", issueNodeID),
+	}
+
+	out, err := c.runner.Run(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Node struct {
+				ProjectItems struct {
+					Nodes []struct {
+						ID      string `json:"id"`
+						Project struct {
+							ID string `json:"id"`
+						} `json:"project"`
+					} `json:"nodes"`
+				} `json:"projectItems"`
+			} `json:"node"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+	}
+	ids := make(map[string]string, len(resp.Data.Node.ProjectItems.Nodes))
+	for _, item := range resp.Data.Node.ProjectItems.Nodes {
+		ids[item.Project.ID] = item.ID
+	}
+	return ids, nil
+}
@@ -0,0 +1,86 @@
+package ghcli
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestProjectItemsFields(t *testing.T) {
+	payload := `{"nodes": [{
+		"project": {"title": "Roadmap"},
+		"fieldValues": {"nodes": [
+			{"__typename": "ProjectV2ItemFieldTextValue", "text": "Issue title", "field": {"name": "Title"}},
+			{"__typename": "ProjectV2ItemFieldSingleSelectValue", "name": "In Progress", "field": {"name": "Status"}},
+			{"__typename": "ProjectV2ItemFieldNumberValue", "number": 2.5, "field": {"name": "Estimate"}},
+			{"__typename": "ProjectV2ItemFieldIterationValue", "title": "Sprint 4", "field": {"name": "Iteration"}},
+			{"__typename": "ProjectV2ItemFieldDateValue", "date": "2025-03-01", "field": {"name": "Due"}},
+			{"__typename": "ProjectV2ItemFieldLabelValue"}
+		]}
+	}]}`
+	var items projectItems
+	if err := json.Unmarshal([]byte(payload), &items); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if titles := items.titles(); len(titles) != 1 || titles[0] != "Roadmap" {
+		t.Fatalf("unexpected titles: %v", titles)
+	}
+	fields := items.fields()["Roadmap"]
+	expected := map[string]string{"Status": "In Progress", "Estimate": "2.5", "Iteration": "Sprint 4", "Due": "2025-03-01"}
+	if len(fields) != len(expected) {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	for name, value := range expected {
+		if fields[name] != value {
+			t.Fatalf("expected %s=%q, got %v", name, value, fields)
+		}
+	}
+}
+
+// projectFieldsRunner answers the queries made by UpdateProjectFields and
+// records the mutations.
+type projectFieldsRunner struct {
+	mutations [][]string
+}
+
+func (r *projectFieldsRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	joined := strings.Join(args, " ")
+	switch {
+	case strings.Contains(joined, "issue(number: $number)"):
+		return `{"data": {"repository": {"issue": {"id": "ISSUE"}}}}`, nil
+	case strings.Contains(joined, "projectItems(first: 100)"):
+		return `{"data": {"node": {"projectItems": {"nodes": [{"id": "ITEM", "project": {"id": "PROJ"}}]}}}}`, nil
+	}
+	r.mutations = append(r.mutations, args)
+	return `{"data": {}}`, nil
+}
+
+func TestUpdateProjectFields(t *testing.T) {
+	runner := &projectFieldsRunner{}
+	client := NewClient(runner, "octo/repo")
+	status := ProjectField{ID: "F1", Name: "Status", DataType: "SINGLE_SELECT", Options: []ProjectFieldOption{{ID: "OPT", Name: "Done"}}}
+	estimate := ProjectField{ID: "F2", Name: "Estimate", DataType: "NUMBER"}
+	err := client.UpdateProjectFields(context.Background(), "7", []ProjectFieldUpdate{
+		{ProjectID: "PROJ", Field: status, Value: "done"},
+		{ProjectID: "PROJ", Field: estimate},
+	})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if len(runner.mutations) != 2 {
+		t.Fatalf("expected 2 mutations, got %d", len(runner.mutations))
+	}
+	set := strings.Join(runner.mutations[0], " ")
+	if !strings.Contains(set, "updateProjectV2ItemFieldValue") || !strings.Contains(set, "value[singleSelectOptionId]=OPT") || !strings.Contains(set, "itemId=ITEM") {
+		t.Fatalf("unexpected update mutation: %v", runner.mutations[0])
+	}
+	if clear := strings.Join(runner.mutations[1], " "); !strings.Contains(clear, "clearProjectV2ItemFieldValue") || !strings.Contains(clear, "fieldId=F2") {
+		t.Fatalf("unexpected clear mutation: %v", runner.mutations[1])
+	}
+
+	bad := ProjectFieldUpdate{ProjectID: "PROJ", Field: status, Value: "Unknown"}
+	if err := client.UpdateProjectFields(context.Background(), "7", []ProjectFieldUpdate{bad}); err == nil {
+		t.Fatalf("expected error for unknown option")
+	}
+}
@@ -0,0 +1,153 @@
+package ghcli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimelineEventTypes are the timeline events kept for auditing. Others
+// (comments, subscriptions, project moves, ...) are skipped.
+var TimelineEventTypes = map[string]bool{
+	"labeled":          true,
+	"unlabeled":        true,
+	"assigned":         true,
+	"unassigned":       true,
+	"closed":           true,
+	"reopened":         true,
+	"referenced":       true,
+	"cross-referenced": true,
+	"renamed":          true,
+}
+
+// TimelineEvent is an event from an issue's timeline.
+type TimelineEvent struct {
+	Event     string    `json:"event"`
+	Actor     string    `json:"actor,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Label is set for labeled and unlabeled events.
+	Label string `json:"label,omitempty"`
+	// Assignee is set for assigned and unassigned events.
+	Assignee string `json:"assignee,omitempty"`
+	// StateReason is set for closed events.
+	StateReason string `json:"state_reason,omitempty"`
+	// CommitID is set for referenced events and for issues closed by a commit.
+	CommitID string `json:"commit_id,omitempty"`
+	// From and To are the old and new title of renamed events.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	// Source is the issue or pull request of cross-referenced events.
+	Source *TimelineSource `json:"source,omitempty"`
+}
+
+// TimelineSource is an issue or pull request referencing another issue.
+type TimelineSource struct {
+	Repository  string `json:"repository,omitempty"`
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	URL         string `json:"url,omitempty"`
+	PullRequest bool   `json:"pull_request,omitempty"`
+}
+
+// apiTimelineEvent is the REST shape of a timeline event.
+type apiTimelineEvent struct {
+	Event string `json:"event"`
+	Actor *struct {
+		Login string `json:"login"`
+	} `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	Label     *struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	Assignee *struct {
+		Login string `json:"login"`
+	} `json:"assignee"`
+	StateReason string `json:"state_reason"`
+	CommitID    string `json:"commit_id"`
+	Rename      *struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	} `json:"rename"`
+	Source *struct {
+		Issue *struct {
+			Number      int             `json:"number"`
+			Title       string          `json:"title"`
+			HTMLURL     string          `json:"html_url"`
+			PullRequest json.RawMessage `json:"pull_request"`
+			Repository  *struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		} `json:"issue"`
+	} `json:"source"`
+}
+
+func (e apiTimelineEvent) toEvent() TimelineEvent {
+	event := TimelineEvent{
+		Event:       e.Event,
+		CreatedAt:   e.CreatedAt,
+		StateReason: strings.ToLower(e.StateReason),
+		CommitID:    e.CommitID,
+	}
+	if e.Actor != nil {
+		event.Actor = e.Actor.Login
+	}
+	if e.Label != nil {
+		event.Label = e.Label.Name
+	}
+	if e.Assignee != nil {
+		event.Assignee = e.Assignee.Login
+	}
+	if e.Rename != nil {
+		event.From = e.Rename.From
+		event.To = e.Rename.To
+	}
+	if e.Source != nil && e.Source.Issue != nil {
+		src := e.Source.Issue
+		event.Source = &TimelineSource{
+			Number:      src.Number,
+			Title:       src.Title,
+			URL:         src.HTMLURL,
+			PullRequest: len(src.PullRequest) > 0 && string(src.PullRequest) != "null",
+		}
+		if src.Repository != nil {
+			event.Source.Repository = src.Repository.FullName
+		}
+	}
+	return event
+}
+
+// GetTimeline fetches the audit relevant events of an issue's timeline,
+// oldest first.
+func (c *Client) GetTimeline(ctx context.Context, number string) ([]TimelineEvent, error) {
+	owner, repo := splitRepo(c.repo)
+	if owner == "" || repo == "" {
+		return nil, fmt.Errorf("invalid repository format")
+	}
+	// Note: gh api doesn't support --repo, so we must expand the repo in the URL
+	endpoint := fmt.Sprintf("repos/%s/%s/issues/%s/timeline?per_page=100", owner, repo, number)
+	args := []string{"api", endpoint, "--paginate", "-q", ".[]"}
+	out, err := c.runner.Run(ctx, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Output is newline-delimited JSON objects
+	var events []TimelineEvent
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var e apiTimelineEvent
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse timeline event %q: %w", line, err)
+		}
+		if !TimelineEventTypes[e.Event] {
+			continue
+		}
+		events = append(events, e.toEvent())
+	}
+	return events, nil
+}
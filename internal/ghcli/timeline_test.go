@@ -0,0 +1,50 @@
+package ghcli
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type timelineRunner struct {
+	args []string
+}
+
+func (r *timelineRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	r.args = append([]string(nil), args...)
+	return strings.Join([]string{
+		`{"event":"labeled","actor":{"login":"alice"},"created_at":"2025-05-01T10:00:00Z","label":{"name":"bug","color":"d73a4a"}}`,
+		`{"event":"commented","actor":{"login":"bob"},"created_at":"2025-05-01T11:00:00Z","body":"Me too"}`,
+		`{"event":"cross-referenced","actor":{"login":"bob"},"created_at":"2025-05-02T09:00:00Z","source":{"type":"issue","issue":{"number":12,"title":"Fix crash","html_url":"https://github.com/octo/repo/pull/12","pull_request":{"url":"x"},"repository":{"full_name":"octo/repo"}}}}`,
+		`{"event":"renamed","actor":{"login":"alice"},"created_at":"2025-05-02T10:00:00Z","rename":{"from":"Crash","to":"Crash on start"}}`,
+		`{"event":"closed","actor":{"login":"bob"},"created_at":"2025-05-03T08:00:00Z","state_reason":"COMPLETED","commit_id":"0123456789abcdef"}`,
+	}, "\n"), nil
+}
+
+func TestGetTimeline(t *testing.T) {
+	runner := &timelineRunner{}
+	client := NewClient(runner, "octo/repo")
+
+	events, err := client.GetTimeline(context.Background(), "4")
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	if runner.args[1] != "repos/octo/repo/issues/4/timeline?per_page=100" {
+		t.Fatalf("unexpected endpoint: %v", runner.args)
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected comments to be skipped, got %+v", events)
+	}
+	if events[0].Event != "labeled" || events[0].Actor != "alice" || events[0].Label != "bug" {
+		t.Fatalf("unexpected labeled event: %+v", events[0])
+	}
+	if src := events[1].Source; src == nil || src.Number != 12 || !src.PullRequest || src.Repository != "octo/repo" {
+		t.Fatalf("unexpected cross-reference: %+v", events[1])
+	}
+	if events[2].From != "Crash" || events[2].To != "Crash on start" {
+		t.Fatalf("unexpected rename: %+v", events[2])
+	}
+	if events[3].StateReason != "completed" || events[3].CommitID != "0123456789abcdef" {
+		t.Fatalf("unexpected close: %+v", events[3])
+	}
+}
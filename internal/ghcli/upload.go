@@ -0,0 +1,97 @@
+package ghcli
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// isNotFound reports whether a gh api call failed with HTTP 404.
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "HTTP 404")
+}
+
+// EnsureBranch creates branch as an orphan branch holding only a README if it
+// does not exist yet. It is used to host files referenced from issues without
+// touching the repository's regular history.
+func (c *Client) EnsureBranch(ctx context.Context, branch, readme string) error {
+	owner, repo := splitRepo(c.repo)
+	if owner == "" || repo == "" {
+		return fmt.Errorf("invalid repository format")
+	}
+	base := fmt.Sprintf("repos/%s/%s", owner, repo)
+
+	_, err := c.runner.Run(ctx, "gh", "api", base+"/branches/"+branch, "-q", ".name")
+	if err == nil {
+		return nil
+	}
+	if !isNotFound(err) {
+		return err
+	}
+
+	blob, err := c.runner.Run(ctx, "gh", "api", base+"/git/blobs", "-X", "POST",
+		"-f", "content="+readme, "-f", "encoding=utf-8", "-q", ".sha")
+	if err != nil {
+		return fmt.Errorf("creating branch %s: %w", branch, err)
+	}
+	tree, err := c.runner.Run(ctx, "gh", "api", base+"/git/trees", "-X", "POST",
+		"-f", "tree[][path]=README.md", "-f", "tree[][mode]=100644",
+		"-f", "tree[][type]=blob", "-f", "tree[][sha]="+strings.TrimSpace(blob), "-q", ".sha")
+	if err != nil {
+		return fmt.Errorf("creating branch %s: %w", branch, err)
+	}
+	commit, err := c.runner.Run(ctx, "gh", "api", base+"/git/commits", "-X", "POST",
+		"-f", "message=Initialize "+branch, "-f", "tree="+strings.TrimSpace(tree), "-q", ".sha")
+	if err != nil {
+		return fmt.Errorf("creating branch %s: %w", branch, err)
+	}
+	_, err = c.runner.Run(ctx, "gh", "api", base+"/git/refs", "-X", "POST",
+		"-f", "ref=refs/heads/"+branch, "-f", "sha="+strings.TrimSpace(commit))
+	if err != nil {
+		return fmt.Errorf("creating branch %s: %w", branch, err)
+	}
+	return nil
+}
+
+// UploadFile commits data to path on branch (unless a file already exists
+// there) and returns a URL that serves the raw file.
+func (c *Client) UploadFile(ctx context.Context, branch, path string, data []byte, message string) (string, error) {
+	owner, repo := splitRepo(c.repo)
+	if owner == "" || repo == "" {
+		return "", fmt.Errorf("invalid repository format")
+	}
+	endpoint := fmt.Sprintf("repos/%s/%s/contents/%s", owner, repo, path)
+	url := fmt.Sprintf("https://github.com/%s/%s/raw/%s/%s", owner, repo, branch, path)
+
+	_, err := c.runner.Run(ctx, "gh", "api", endpoint+"?ref="+branch, "-q", ".sha")
+	if err == nil {
+		return url, nil
+	}
+	if !isNotFound(err) {
+		return "", err
+	}
+
+	// The encoded file can exceed the argument size limit, so pass it via a
+	// temporary file.
+	tmp, err := os.CreateTemp("", "gh-issue-sync-upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(base64.StdEncoding.EncodeToString(data)); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	_, err = c.runner.Run(ctx, "gh", "api", endpoint, "-X", "PUT",
+		"-f", "message="+message, "-f", "branch="+branch, "-F", "content=@"+tmp.Name())
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
@@ -0,0 +1,156 @@
+// Package history keeps past versions of synced issues.
+//
+// Every issue has an index file listing its versions, oldest first. The
+// versions themselves are issue files stored gzip compressed under the hash
+// of their contents, so identical versions (an issue changing back and forth)
+// are only stored once.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	BlobsDirName = "blobs"
+	IndexExt     = ".jsonl"
+)
+
+// Version is a past version of an issue.
+type Version struct {
+	Hash string `json:"hash"`
+	// SyncedAt is when this version was synced from or to GitHub.
+	SyncedAt time.Time `json:"synced_at,omitempty"`
+	// ReplacedAt is when a later sync replaced it, and ReplacedBy the kind
+	// of that sync ("pull" or "push").
+	ReplacedAt time.Time `json:"replaced_at"`
+	ReplacedBy string    `json:"replaced_by"`
+}
+
+// Store is a history store in a directory.
+type Store struct {
+	Dir string
+}
+
+// New returns the store in dir.
+func New(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+func (s *Store) indexPath(number string) string {
+	return filepath.Join(s.Dir, number+IndexExt)
+}
+
+func (s *Store) blobPath(hash string) string {
+	return filepath.Join(s.Dir, BlobsDirName, hash[:2], hash[2:]+".gz")
+}
+
+// Append adds a version of an issue, given as the contents of its issue
+// file. It is skipped if it matches the latest recorded version.
+func (s *Store) Append(number string, content []byte, v Version) error {
+	sum := sha256.Sum256(content)
+	v.Hash = hex.EncodeToString(sum[:])
+
+	versions, err := s.Versions(number)
+	if err != nil {
+		return err
+	}
+	if len(versions) > 0 && versions[len(versions)-1].Hash == v.Hash {
+		return nil
+	}
+	if err := s.storeBlob(v.Hash, content); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.indexPath(number), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (s *Store) storeBlob(hash string, content []byte) error {
+	path := s.blobPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(content); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Versions returns the recorded versions of an issue, oldest first.
+func (s *Store) Versions(number string) ([]Version, error) {
+	f, err := os.Open(s.indexPath(number))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var versions []Version
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var v Version
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			return nil, fmt.Errorf("invalid history for #%s: %w", number, err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, scanner.Err()
+}
+
+// Load returns the contents of a recorded version.
+func (s *Store) Load(hash string) ([]byte, error) {
+	f, err := os.Open(s.blobPath(hash))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
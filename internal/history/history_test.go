@@ -0,0 +1,37 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppendDeduplicates(t *testing.T) {
+	store := New(t.TempDir())
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, content := range []string{"v1", "v1", "v2", "v1"} {
+		v := Version{ReplacedAt: at.Add(time.Duration(i) * time.Hour), ReplacedBy: "pull"}
+		if err := store.Append("7", []byte(content), v); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+
+	versions, err := store.Versions("7")
+	if err != nil {
+		t.Fatalf("versions: %v", err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("expected repeated version to be skipped, got %d versions", len(versions))
+	}
+	if versions[0].Hash != versions[2].Hash || !versions[2].ReplacedAt.Equal(at.Add(3*time.Hour)) {
+		t.Fatalf("unexpected versions %+v", versions)
+	}
+	data, err := store.Load(versions[1].Hash)
+	if err != nil || string(data) != "v2" {
+		t.Fatalf("expected v2, got %q (%v)", data, err)
+	}
+
+	if versions, err := store.Versions("8"); err != nil || versions != nil {
+		t.Fatalf("expected no history, got %v (%v)", versions, err)
+	}
+}
@@ -20,25 +20,42 @@ type IssueNumber string
 type IssueRef string
 
 type Issue struct {
-	Number      IssueNumber
-	Title       string
-	Labels      []string
-	Assignees   []string
-	Milestone   string
-	IssueType   string
-	Projects    []string
-	State       string
-	StateReason *string
-	Parent      *IssueRef
-	BlockedBy   []IssueRef
-	Blocks      []IssueRef
-	SyncedAt    *time.Time
-	Body        string
+	Number    IssueNumber
+	Title     string
+	Labels    []string
+	Assignees []string
+	Milestone string
+	IssueType string
+	Projects  []string
+	// ProjectFields holds custom field values per project
+	ProjectFields ProjectFields
+	State         string
+	StateReason   *string
+	Parent        *IssueRef
+	BlockedBy     []IssueRef
+	Blocks        []IssueRef
+	SyncedAt      *time.Time
+	Body          string
 
 	// Informational fields (read-only, not synced back to GitHub)
 	Author    string
 	CreatedAt *time.Time
 	UpdatedAt *time.Time
+	ClosedAt  *time.Time
+	LinkedPRs []LinkedPR
+
+	// Extra holds front matter keys gh-issue-sync does not know about, in
+	// file order. They are local-only: preserved on rewrite, never pushed.
+	Extra []ExtraField
+
+	// layout is the parsed front matter, used to rewrite files in place
+	layout *layout
+}
+
+// ExtraField is an unknown front matter key with its value kept verbatim.
+type ExtraField struct {
+	Key   string
+	Value *yaml.Node
 }
 
 // InfoSection contains read-only informational fields that are synced from
@@ -47,22 +64,71 @@ type InfoSection struct {
 	Author    string     `yaml:"author,omitempty"`
 	CreatedAt *time.Time `yaml:"created_at,omitempty"`
 	UpdatedAt *time.Time `yaml:"updated_at,omitempty"`
+	ClosedAt  *time.Time `yaml:"closed_at,omitempty"`
+	LinkedPRs []LinkedPR `yaml:"linked_prs,omitempty"`
+}
+
+// LinkedPR is a pull request linked to an issue, either through a closing
+// reference ("Fixes #12") or by mentioning the issue.
+type LinkedPR struct {
+	Number int `yaml:"number"`
+	// Repository is only set for pull requests in other repositories.
+	Repository string `yaml:"repo,omitempty"`
+	Title      string `yaml:"title"`
+	State      string `yaml:"state"` // open or closed
+	Merged     bool   `yaml:"merged,omitempty"`
+	Branch     string `yaml:"branch,omitempty"`
+	// Closes is set if merging the pull request closes the issue.
+	Closes bool `yaml:"closes,omitempty"`
+}
+
+// Ref returns the pull request as "#12", or "owner/repo#12" for pull
+// requests in other repositories.
+func (pr LinkedPR) Ref() string {
+	return pr.Repository + "#" + strconv.Itoa(pr.Number)
+}
+
+// LinkedPRsEqual reports whether two lists of linked pull requests match.
+func LinkedPRsEqual(a, b []LinkedPR) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TimestampsEqual reports whether two issues were last updated and closed at
+// the same times.
+func TimestampsEqual(a, b Issue) bool {
+	return timeEqual(a.UpdatedAt, b.UpdatedAt) && timeEqual(a.ClosedAt, b.ClosedAt)
+}
+
+func timeEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
 }
 
 type FrontMatter struct {
-	Title       string     `yaml:"title"`
-	Labels      []string   `yaml:"labels,omitempty"`
-	Assignees   []string   `yaml:"assignees,omitempty"`
-	Milestone   string     `yaml:"milestone,omitempty"`
-	IssueType   string     `yaml:"type,omitempty"`
-	Projects    []string   `yaml:"projects,omitempty"`
-	State       string     `yaml:"state,omitempty"`
-	StateReason *string    `yaml:"state_reason"`
-	Parent      *IssueRef  `yaml:"parent,omitempty"`
-	BlockedBy   []IssueRef `yaml:"blocked_by,omitempty"`
-	Blocks      []IssueRef `yaml:"blocks,omitempty"`
-	SyncedAt    *time.Time `yaml:"synced_at,omitempty"`
-	Info        *InfoSection `yaml:"info,omitempty"`
+	Title         string        `yaml:"title"`
+	Labels        []string      `yaml:"labels,omitempty"`
+	Assignees     []string      `yaml:"assignees,omitempty"`
+	Milestone     string        `yaml:"milestone,omitempty"`
+	IssueType     string        `yaml:"type,omitempty"`
+	Projects      []string      `yaml:"projects,omitempty"`
+	ProjectFields ProjectFields `yaml:"project_fields,omitempty"`
+	State         string        `yaml:"state,omitempty"`
+	StateReason   *string       `yaml:"state_reason"`
+	Parent        *IssueRef     `yaml:"parent,omitempty"`
+	BlockedBy     []IssueRef    `yaml:"blocked_by,omitempty"`
+	Blocks        []IssueRef    `yaml:"blocks,omitempty"`
+	SyncedAt      *time.Time    `yaml:"synced_at,omitempty"`
+	Info          *InfoSection  `yaml:"info,omitempty"`
 }
 
 func (n IssueNumber) String() string {
@@ -105,6 +171,118 @@ func (r IssueRef) MarshalYAML() (interface{}, error) {
 
 var frontMatterDelimiter = []byte("---")
 
+// KnownKeys lists the top-level front matter keys understood by the parser.
+// "number" is accepted for compatibility but the filename is authoritative.
+var KnownKeys = []string{
+	"number",
+	"title",
+	"labels",
+	"assignees",
+	"milestone",
+	"type",
+	"projects",
+	"project_fields",
+	"state",
+	"state_reason",
+	"parent",
+	"blocked_by",
+	"blocks",
+	"synced_at",
+	"info",
+}
+
+// IsKnownKey reports whether key is a top-level front matter key understood by the parser.
+func IsKnownKey(key string) bool {
+	for _, known := range KnownKeys {
+		if key == known {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyInfo describes a top-level front matter key and where it appears.
+type KeyInfo struct {
+	Name  string
+	Value string // Scalar value, empty for sequences and mappings
+	Line  int    // 1-based line number within the file
+}
+
+// FrontMatterKeys returns the top-level front matter keys of an issue file in
+// the order they appear.
+func FrontMatterKeys(data []byte) ([]KeyInfo, error) {
+	frontMatter, _, err := splitFrontMatter(data)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(frontMatter, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("front matter must be a mapping")
+	}
+	keys := make([]KeyInfo, 0, len(root.Content)/2)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		info := KeyInfo{
+			Name: key.Value,
+			// The opening delimiter occupies the first line of the file
+			Line: key.Line + 1,
+		}
+		if value.Kind == yaml.ScalarNode {
+			info.Value = value.Value
+		}
+		keys = append(keys, info)
+	}
+	return keys, nil
+}
+
+// extraFields collects the unknown keys of a front matter mapping.
+func extraFields(root *yaml.Node) []ExtraField {
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+	var extra []ExtraField
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key := root.Content[i].Value
+		if IsKnownKey(key) {
+			continue
+		}
+		extra = append(extra, ExtraField{Key: key, Value: root.Content[i+1]})
+	}
+	return extra
+}
+
+// ExtraValues returns the scalar values of the extra fields keyed by name.
+// Sequences contribute each of their scalar items; mappings are skipped.
+func (i Issue) ExtraValues() map[string][]string {
+	if len(i.Extra) == 0 {
+		return nil
+	}
+	values := make(map[string][]string, len(i.Extra))
+	for _, field := range i.Extra {
+		if field.Value == nil {
+			continue
+		}
+		switch field.Value.Kind {
+		case yaml.ScalarNode:
+			values[field.Key] = append(values[field.Key], field.Value.Value)
+		case yaml.SequenceNode:
+			for _, item := range field.Value.Content {
+				if item.Kind == yaml.ScalarNode {
+					values[field.Key] = append(values[field.Key], item.Value)
+				}
+			}
+		}
+	}
+	return values
+}
+
 // numberFromFilename extracts the issue number from a filename like "42-title.md" or "T5-title.md"
 // Also handles simple filenames like "42.md" (used for originals)
 func numberFromFilename(path string) IssueNumber {
@@ -136,56 +314,76 @@ func Parse(data []byte) (Issue, error) {
 	if err != nil {
 		return Issue{}, err
 	}
-	var fm FrontMatter
-	if err := yaml.Unmarshal(frontMatter, &fm); err != nil {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(frontMatter, &doc); err != nil {
 		return Issue{}, err
 	}
+	var fm FrontMatter
+	var extra []ExtraField
+	if len(doc.Content) > 0 {
+		if err := doc.Decode(&fm); err != nil {
+			return Issue{}, err
+		}
+		extra = extraFields(doc.Content[0])
+	}
 	issue := Issue{
-		Title:       fm.Title,
-		Labels:      fm.Labels,
-		Assignees:   fm.Assignees,
-		Milestone:   fm.Milestone,
-		IssueType:   fm.IssueType,
-		Projects:    fm.Projects,
-		State:       fm.State,
-		StateReason: fm.StateReason,
-		Parent:      fm.Parent,
-		BlockedBy:   fm.BlockedBy,
-		Blocks:      fm.Blocks,
-		SyncedAt:    fm.SyncedAt,
-		Body:        normalizeBody(string(body)),
+		Title:         fm.Title,
+		Labels:        fm.Labels,
+		Assignees:     fm.Assignees,
+		Milestone:     fm.Milestone,
+		IssueType:     fm.IssueType,
+		Projects:      fm.Projects,
+		ProjectFields: fm.ProjectFields,
+		State:         fm.State,
+		StateReason:   fm.StateReason,
+		Parent:        fm.Parent,
+		BlockedBy:     fm.BlockedBy,
+		Blocks:        fm.Blocks,
+		SyncedAt:      fm.SyncedAt,
+		Body:          normalizeBody(string(body)),
+		Extra:         extra,
+		layout:        parseLayout(&doc, frontMatter),
 	}
 	if fm.Info != nil {
 		issue.Author = fm.Info.Author
 		issue.CreatedAt = fm.Info.CreatedAt
 		issue.UpdatedAt = fm.Info.UpdatedAt
+		issue.ClosedAt = fm.Info.ClosedAt
+		issue.LinkedPRs = fm.Info.LinkedPRs
 	}
 	return issue, nil
 }
 
 func Render(issue Issue) (string, error) {
 	fm := FrontMatter{
-		Title:       issue.Title,
-		Labels:      sortedStrings(issue.Labels),
-		Assignees:   sortedStrings(issue.Assignees),
-		Milestone:   issue.Milestone,
-		IssueType:   issue.IssueType,
-		Projects:    sortedStrings(issue.Projects),
-		State:       issue.State,
-		StateReason: issue.StateReason,
-		Parent:      issue.Parent,
-		BlockedBy:   sortedRefs(issue.BlockedBy),
-		Blocks:      sortedRefs(issue.Blocks),
-		SyncedAt:    issue.SyncedAt,
-	}
-	if issue.Author != "" || issue.CreatedAt != nil || issue.UpdatedAt != nil {
+		Title:         issue.Title,
+		Labels:        sortedStrings(issue.Labels),
+		Assignees:     sortedStrings(issue.Assignees),
+		Milestone:     issue.Milestone,
+		IssueType:     issue.IssueType,
+		Projects:      sortedStrings(issue.Projects),
+		ProjectFields: issue.ProjectFields.Normalized(),
+		State:         issue.State,
+		StateReason:   issue.StateReason,
+		Parent:        issue.Parent,
+		BlockedBy:     sortedRefs(issue.BlockedBy),
+		Blocks:        sortedRefs(issue.Blocks),
+		SyncedAt:      issue.SyncedAt,
+	}
+	if issue.Author != "" || issue.CreatedAt != nil || issue.UpdatedAt != nil || issue.ClosedAt != nil || len(issue.LinkedPRs) > 0 {
 		fm.Info = &InfoSection{
 			Author:    issue.Author,
 			CreatedAt: issue.CreatedAt,
 			UpdatedAt: issue.UpdatedAt,
+			ClosedAt:  issue.ClosedAt,
+			LinkedPRs: issue.LinkedPRs,
 		}
 	}
-	payload, err := yaml.Marshal(&fm)
+	var node yaml.Node
+	if err := node.Encode(&fm); err != nil {
+		return "", err
+	}
+	payload, err := encodeFrontMatter(issue, &node)
 	if err != nil {
 		return "", err
 	}
@@ -224,6 +422,7 @@ func Normalize(issue Issue) Issue {
 	issue.Labels = sortedStrings(issue.Labels)
 	issue.Assignees = sortedStrings(issue.Assignees)
 	issue.Projects = sortedStrings(issue.Projects)
+	issue.ProjectFields = issue.ProjectFields.Normalized()
 	issue.BlockedBy = sortedRefs(issue.BlockedBy)
 	issue.Blocks = sortedRefs(issue.Blocks)
 	issue.Body = normalizeBody(issue.Body)
@@ -268,6 +467,9 @@ func equalIssues(a, b Issue, ignoreStateReason bool) bool {
 	if !stringSlicesEqual(a.Projects, b.Projects) {
 		return false
 	}
+	if !a.ProjectFields.Equal(b.ProjectFields) {
+		return false
+	}
 	if a.State != b.State {
 		return false
 	}
@@ -438,11 +640,13 @@ type FieldSet struct {
 	Milestone bool
 	IssueType bool
 	Projects  bool
-	State     bool
-	Parent    bool
-	BlockedBy bool
-	Blocks    bool
-	Body      bool
+	// ProjectFields is set when any project field value differs
+	ProjectFields bool
+	State         bool
+	Parent        bool
+	BlockedBy     bool
+	Blocks        bool
+	Body          bool
 }
 
 // Fields returns a list of field names that are set.
@@ -466,6 +670,9 @@ func (f FieldSet) Fields() []string {
 	if f.Projects {
 		fields = append(fields, "projects")
 	}
+	if f.ProjectFields {
+		fields = append(fields, "project_fields")
+	}
 	if f.State {
 		fields = append(fields, "state")
 	}
@@ -487,24 +694,25 @@ func (f FieldSet) Fields() []string {
 // IsEmpty returns true if no fields are set.
 func (f FieldSet) IsEmpty() bool {
 	return !f.Title && !f.Labels && !f.Assignees && !f.Milestone &&
-		!f.IssueType && !f.Projects && !f.State && !f.Parent &&
+		!f.IssueType && !f.Projects && !f.ProjectFields && !f.State && !f.Parent &&
 		!f.BlockedBy && !f.Blocks && !f.Body
 }
 
 // Overlaps returns a FieldSet containing fields that are set in both.
 func (f FieldSet) Overlaps(other FieldSet) FieldSet {
 	return FieldSet{
-		Title:     f.Title && other.Title,
-		Labels:    f.Labels && other.Labels,
-		Assignees: f.Assignees && other.Assignees,
-		Milestone: f.Milestone && other.Milestone,
-		IssueType: f.IssueType && other.IssueType,
-		Projects:  f.Projects && other.Projects,
-		State:     f.State && other.State,
-		Parent:    f.Parent && other.Parent,
-		BlockedBy: f.BlockedBy && other.BlockedBy,
-		Blocks:    f.Blocks && other.Blocks,
-		Body:      f.Body && other.Body,
+		Title:         f.Title && other.Title,
+		Labels:        f.Labels && other.Labels,
+		Assignees:     f.Assignees && other.Assignees,
+		Milestone:     f.Milestone && other.Milestone,
+		IssueType:     f.IssueType && other.IssueType,
+		Projects:      f.Projects && other.Projects,
+		ProjectFields: f.ProjectFields && other.ProjectFields,
+		State:         f.State && other.State,
+		Parent:        f.Parent && other.Parent,
+		BlockedBy:     f.BlockedBy && other.BlockedBy,
+		Blocks:        f.Blocks && other.Blocks,
+		Body:          f.Body && other.Body,
 	}
 }
 
@@ -514,17 +722,18 @@ func ComputeChanges(base, changed Issue) FieldSet {
 	changed = Normalize(changed)
 
 	return FieldSet{
-		Title:     base.Title != changed.Title,
-		Labels:    !stringSlicesEqual(base.Labels, changed.Labels),
-		Assignees: !stringSlicesEqual(base.Assignees, changed.Assignees),
-		Milestone: base.Milestone != changed.Milestone,
-		IssueType: base.IssueType != changed.IssueType,
-		Projects:  !stringSlicesEqual(base.Projects, changed.Projects),
-		State:     base.State != changed.State,
-		Parent:    normalizeOptionalRef(base.Parent) != normalizeOptionalRef(changed.Parent),
-		BlockedBy: !refSlicesEqual(base.BlockedBy, changed.BlockedBy),
-		Blocks:    !refSlicesEqual(base.Blocks, changed.Blocks),
-		Body:      base.Body != changed.Body,
+		Title:         base.Title != changed.Title,
+		Labels:        !stringSlicesEqual(base.Labels, changed.Labels),
+		Assignees:     !stringSlicesEqual(base.Assignees, changed.Assignees),
+		Milestone:     base.Milestone != changed.Milestone,
+		IssueType:     base.IssueType != changed.IssueType,
+		Projects:      !stringSlicesEqual(base.Projects, changed.Projects),
+		ProjectFields: !base.ProjectFields.Equal(changed.ProjectFields),
+		State:         base.State != changed.State,
+		Parent:        normalizeOptionalRef(base.Parent) != normalizeOptionalRef(changed.Parent),
+		BlockedBy:     !refSlicesEqual(base.BlockedBy, changed.BlockedBy),
+		Blocks:        !refSlicesEqual(base.Blocks, changed.Blocks),
+		Body:          base.Body != changed.Body,
 	}
 }
 
@@ -560,8 +769,10 @@ func ThreeWayMerge(base, local, remote Issue) MergeResult {
 		return result
 	}
 
-	// No conflicts - merge by starting with remote and applying local changes
+	// No conflicts - merge by starting with remote and applying local changes.
+	// Extra fields and file layout only exist locally, so they come from local.
 	merged := Normalize(remote)
+	merged.KeepLocal(local)
 
 	if localChanges.Title {
 		merged.Title = local.Title
@@ -581,6 +792,9 @@ func ThreeWayMerge(base, local, remote Issue) MergeResult {
 	if localChanges.Projects {
 		merged.Projects = local.Projects
 	}
+	if localChanges.ProjectFields {
+		merged.ProjectFields = local.ProjectFields
+	}
 	if localChanges.State {
 		merged.State = local.State
 	}
@@ -138,6 +138,38 @@ Body
 	}
 }
 
+func TestLinkedPRsRoundTrip(t *testing.T) {
+	iss := Issue{
+		Title: "Crash",
+		State: "open",
+		LinkedPRs: []LinkedPR{
+			{Number: 12, Title: "Fix crash", State: "closed", Merged: true, Branch: "fix-crash", Closes: true},
+			{Number: 3, Repository: "octo/other", Title: "Workaround", State: "open"},
+		},
+	}
+	rendered, err := Render(iss)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(rendered, "linked_prs:") {
+		t.Fatalf("rendered should contain linked_prs: %s", rendered)
+	}
+	parsed, err := Parse([]byte(rendered))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if !LinkedPRsEqual(parsed.LinkedPRs, iss.LinkedPRs) {
+		t.Fatalf("expected linked PRs to round-trip, got %+v", parsed.LinkedPRs)
+	}
+	if ref := parsed.LinkedPRs[1].Ref(); ref != "octo/other#3" {
+		t.Fatalf("unexpected ref %q", ref)
+	}
+	// Linked PRs are informational and never count as a change
+	if !EqualIgnoringSyncedAt(parsed, Issue{Title: "Crash", State: "open"}) {
+		t.Fatalf("expected linked PRs to be ignored when comparing")
+	}
+}
+
 func TestInfoSectionOmittedWhenEmpty(t *testing.T) {
 	iss := Issue{
 		Title: "No author",
@@ -152,6 +184,26 @@ func TestInfoSectionOmittedWhenEmpty(t *testing.T) {
 	}
 }
 
+func TestTimestampsEqual(t *testing.T) {
+	updated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	closed := updated.Add(time.Hour)
+	local := Issue{Title: "Done", State: "closed", UpdatedAt: &updated}
+	remote := local
+	remote.ClosedAt = &closed
+	if TimestampsEqual(local, remote) {
+		t.Fatalf("expected missing closed_at to differ")
+	}
+	// Timestamps are informational and never count as a change
+	if !EqualIgnoringSyncedAt(local, remote) {
+		t.Fatalf("expected timestamps to be ignored when comparing")
+	}
+	sameInstant := closed.In(time.FixedZone("CET", 3600))
+	local.ClosedAt = &sameInstant
+	if !TimestampsEqual(local, remote) {
+		t.Fatalf("expected the same instants to be equal")
+	}
+}
+
 func TestComputeChanges(t *testing.T) {
 	base := Issue{
 		Title:     "Original title",
@@ -298,3 +350,93 @@ func TestThreeWayMerge_NoLocalChanges(t *testing.T) {
 		t.Errorf("expected merged to have remote labels, got %v", result.Merged.Labels)
 	}
 }
+
+func TestExtraFieldsRoundTrip(t *testing.T) {
+	input := strings.TrimSpace(`---
+title: Test issue
+estimate: 3
+state: open
+notes:
+  - check mobile
+  - ask design
+---
+Body
+`) + "\n"
+
+	parsed, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(parsed.Extra) != 2 || parsed.Extra[0].Key != "estimate" || parsed.Extra[1].Key != "notes" {
+		t.Fatalf("unexpected extra fields: %+v", parsed.Extra)
+	}
+	values := parsed.ExtraValues()
+	if len(values["estimate"]) != 1 || values["estimate"][0] != "3" {
+		t.Fatalf("unexpected estimate values: %v", values["estimate"])
+	}
+	if len(values["notes"]) != 2 {
+		t.Fatalf("unexpected notes values: %v", values["notes"])
+	}
+
+	rendered, err := Render(parsed)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if !strings.Contains(rendered, "estimate: 3\n") || !strings.Contains(rendered, "- check mobile\n") {
+		t.Fatalf("rendered should keep extra fields: %s", rendered)
+	}
+	parsedAgain, err := Parse([]byte(rendered))
+	if err != nil {
+		t.Fatalf("parse rendered failed: %v", err)
+	}
+	if len(parsedAgain.Extra) != 2 {
+		t.Fatalf("extra fields lost on round-trip: %+v", parsedAgain.Extra)
+	}
+
+	// Extras are local-only and never count as a change
+	withoutExtras := parsed
+	withoutExtras.Extra = nil
+	if !EqualIgnoringSyncedAt(parsed, withoutExtras) {
+		t.Fatalf("extra fields should be ignored in equality")
+	}
+}
+
+func TestThreeWayMerge_KeepsLocalExtras(t *testing.T) {
+	local, err := Parse([]byte("---\ntitle: Local title\nestimate: 5\n---\nBody\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	base := Issue{Title: "Original title", Body: "Body\n"}
+	remote := Issue{Title: "Original title", Labels: []string{"bug"}, Body: "Body\n"}
+
+	result := ThreeWayMerge(base, local, remote)
+	if !result.OK {
+		t.Fatalf("expected merge to succeed")
+	}
+	if len(result.Merged.Extra) != 1 || result.Merged.Extra[0].Key != "estimate" {
+		t.Fatalf("expected local extras in merged issue, got %+v", result.Merged.Extra)
+	}
+}
+
+func TestCreationMarker(t *testing.T) {
+	body := WithCreationMarker("Details\n", "T1a2b3c4d")
+	if body != "Details\n\n<!-- gh-issue-sync:local-id=T1a2b3c4d -->\n" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if id, ok := CreationMarkerID(body); !ok || id != "T1a2b3c4d" {
+		t.Fatalf("expected marker id, got %q %v", id, ok)
+	}
+	if got := StripCreationMarker(body); got != "Details\n" {
+		t.Fatalf("expected marker to be stripped, got %q", got)
+	}
+	// GitHub may hand the body back with CRLF line endings
+	if got := StripCreationMarker(strings.ReplaceAll(body, "\n", "\r\n")); strings.TrimSpace(got) != "Details" {
+		t.Fatalf("expected CRLF marker to be stripped, got %q", got)
+	}
+	if got := WithCreationMarker(body, "T5"); got != "Details\n\n<!-- gh-issue-sync:local-id=T5 -->\n" {
+		t.Fatalf("expected marker to be replaced, got %q", got)
+	}
+	if _, ok := CreationMarkerID("No marker"); ok {
+		t.Fatalf("expected no marker")
+	}
+}
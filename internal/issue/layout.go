@@ -0,0 +1,264 @@
+package issue
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent matches the indentation yaml.Marshal uses for fresh files.
+const defaultIndent = 4
+
+// layout remembers how a front matter block was written so that Render can
+// edit the parsed node tree in place instead of re-marshalling from scratch.
+// Comments, key order, quoting and indentation of untouched keys survive.
+type layout struct {
+	doc    *yaml.Node // Document node as parsed, never modified
+	indent int
+}
+
+// parseLayout captures the layout of a parsed front matter document. It
+// returns nil for empty front matter.
+func parseLayout(doc *yaml.Node, frontMatter []byte) *layout {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+	return &layout{doc: doc, indent: detectIndent(frontMatter)}
+}
+
+// detectIndent returns the smallest indentation used in the front matter.
+func detectIndent(frontMatter []byte) int {
+	indent := 0
+	for _, line := range bytes.Split(frontMatter, []byte("\n")) {
+		trimmed := bytes.TrimLeft(line, " ")
+		if len(trimmed) == 0 || trimmed[0] == '#' {
+			continue
+		}
+		if n := len(line) - len(trimmed); n > 0 && (indent == 0 || n < indent) {
+			indent = n
+		}
+	}
+	if indent < 2 {
+		return defaultIndent
+	}
+	return indent
+}
+
+// KeepLocal copies the local-only state of local onto i: the extra fields and
+// the original front matter layout. Use it when rewriting a local file from
+// remote data so custom fields, comments and key order are kept.
+func (i *Issue) KeepLocal(local Issue) {
+	i.Extra = local.Extra
+	i.layout = local.layout
+}
+
+// encodeFrontMatter renders the front matter for issue. Without a parsed
+// layout the fresh node is used as-is; otherwise the parsed document is copied
+// and only the keys whose values changed are touched.
+func encodeFrontMatter(issue Issue, fresh *yaml.Node) ([]byte, error) {
+	if issue.layout == nil {
+		for _, field := range issue.Extra {
+			if field.Value == nil || IsKnownKey(field.Key) {
+				continue
+			}
+			fresh.Content = append(fresh.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: field.Key}, field.Value)
+		}
+		return yaml.Marshal(fresh)
+	}
+
+	doc := copyNode(issue.layout.doc)
+	patchMapping(doc.Content[0], fresh, issue)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(issue.layout.indent)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// patchMapping updates the parsed front matter mapping out to match fresh.
+func patchMapping(out, fresh *yaml.Node, issue Issue) {
+	freshValues := make(map[string]*yaml.Node, len(fresh.Content)/2)
+	var order []string
+	for i := 0; i+1 < len(fresh.Content); i += 2 {
+		key := fresh.Content[i].Value
+		freshValues[key] = fresh.Content[i+1]
+		order = append(order, key)
+	}
+	extras := make(map[string]*yaml.Node, len(issue.Extra))
+	for _, field := range issue.Extra {
+		if field.Value != nil && !IsKnownKey(field.Key) {
+			extras[field.Key] = field.Value
+		}
+	}
+
+	// Update or drop the keys that are already present.
+	present := make(map[string]bool)
+	content := out.Content[:0:0]
+	for i := 0; i+1 < len(out.Content); i += 2 {
+		keyNode, value := out.Content[i], out.Content[i+1]
+		key := keyNode.Value
+		var want *yaml.Node
+		switch {
+		case key == "number":
+			want = value
+			if issue.Number != "" {
+				want = numberNode(issue.Number)
+			}
+		case IsKnownKey(key):
+			want = freshValues[key]
+		default:
+			want = extras[key]
+		}
+		if want == nil || present[key] {
+			continue
+		}
+		present[key] = true
+		if !nodesEquivalent(value, want) {
+			value = replacementNode(value, want)
+		}
+		content = append(content, keyNode, value)
+	}
+
+	// Insert new known keys after their nearest canonical predecessor.
+	for idx, key := range order {
+		if present[key] || isNullNode(freshValues[key]) {
+			continue
+		}
+		pos := 0
+		for j := idx - 1; j >= 0; j-- {
+			if present[order[j]] {
+				pos = indexOfKey(content, order[j]) + 2
+				break
+			}
+		}
+		pair := []*yaml.Node{{Kind: yaml.ScalarNode, Value: key}, freshValues[key]}
+		content = append(content[:pos], append(pair, content[pos:]...)...)
+		present[key] = true
+	}
+
+	for _, field := range issue.Extra {
+		if _, ok := extras[field.Key]; !ok || present[field.Key] {
+			continue
+		}
+		content = append(content, &yaml.Node{Kind: yaml.ScalarNode, Value: field.Key}, field.Value)
+		present[field.Key] = true
+	}
+	out.Content = content
+}
+
+func indexOfKey(content []*yaml.Node, key string) int {
+	for i := 0; i+1 < len(content); i += 2 {
+		if content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// replacementNode returns want styled like the value it replaces: quoting of
+// strings, flow style of lists and attached comments are carried over.
+func replacementNode(old, want *yaml.Node) *yaml.Node {
+	node := copyNode(want)
+	if old.Kind == yaml.ScalarNode && node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str" {
+		node.Style = old.Style & (yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle)
+	}
+	if old.Kind == yaml.SequenceNode && node.Kind == yaml.SequenceNode {
+		node.Style = old.Style & yaml.FlowStyle
+	}
+	node.HeadComment = old.HeadComment
+	node.LineComment = old.LineComment
+	node.FootComment = old.FootComment
+	return node
+}
+
+func numberNode(number IssueNumber) *yaml.Node {
+	if _, err := strconv.Atoi(number.String()); err == nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: number.String()}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: number.String()}
+}
+
+func isNullNode(n *yaml.Node) bool {
+	return n == nil || (n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null")
+}
+
+// nodesEquivalent reports whether two nodes hold the same data. Lists are
+// compared as sets since the tool sorts them anyway, so a hand-ordered list
+// that did not change is left alone.
+func nodesEquivalent(a, b *yaml.Node) bool {
+	if isNullNode(a) || isNullNode(b) {
+		return isNullNode(a) && isNullNode(b)
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case yaml.ScalarNode:
+		return a.Value == b.Value
+	case yaml.SequenceNode:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		av, aok := scalarValues(a)
+		bv, bok := scalarValues(b)
+		if aok && bok {
+			sort.Strings(av)
+			sort.Strings(bv)
+			return strings.Join(av, "\x00") == strings.Join(bv, "\x00")
+		}
+		for i := range a.Content {
+			if !nodesEquivalent(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	case yaml.MappingNode:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			j := indexOfKey(b.Content, a.Content[i].Value)
+			if j < 0 || !nodesEquivalent(a.Content[i+1], b.Content[j+1]) {
+				return false
+			}
+		}
+		return true
+	case yaml.AliasNode:
+		return a.Alias == b.Alias
+	}
+	return false
+}
+
+func scalarValues(n *yaml.Node) ([]string, bool) {
+	values := make([]string, 0, len(n.Content))
+	for _, item := range n.Content {
+		if item.Kind != yaml.ScalarNode {
+			return nil, false
+		}
+		values = append(values, item.Value)
+	}
+	return values, true
+}
+
+func copyNode(n *yaml.Node) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	if len(n.Content) > 0 {
+		c.Content = make([]*yaml.Node, len(n.Content))
+		for i, child := range n.Content {
+			c.Content[i] = copyNode(child)
+		}
+	}
+	return &c
+}
@@ -0,0 +1,116 @@
+package issue
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderPreservesLayout(t *testing.T) {
+	input := `---
+# Triaged in the weekly meeting
+state: open
+title: 'Fix login' # keep it short
+labels:
+  - ios
+  - bug
+estimate: 3 # story points
+---
+Body
+`
+	parsed, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	// Unchanged issues render byte-for-byte identical
+	rendered, err := Render(parsed)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	if rendered != strings.Replace(input, "---\nBody", "---\n\nBody", 1) {
+		t.Fatalf("unchanged issue was reformatted:\n%s", rendered)
+	}
+
+	parsed.Title = "Fix login on Safari"
+	parsed.Assignees = []string{"alice"}
+	rendered, err = Render(parsed)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	expected := `---
+# Triaged in the weekly meeting
+state: open
+title: 'Fix login on Safari' # keep it short
+labels:
+  - ios
+  - bug
+assignees:
+  - alice
+estimate: 3 # story points
+---
+
+Body
+`
+	if rendered != expected {
+		t.Fatalf("unexpected render:\n%s\nwant:\n%s", rendered, expected)
+	}
+}
+
+func TestRenderRemovesClearedKeys(t *testing.T) {
+	input := `---
+title: Test
+labels: [bug, ui]
+milestone: v1
+state: open
+---
+`
+	parsed, err := Parse([]byte(input))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	parsed.Milestone = ""
+	parsed.Labels = []string{"bug"}
+	rendered, err := Render(parsed)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	expected := "---\ntitle: Test\nlabels: [bug]\nstate: open\n---\n\n"
+	if rendered != expected {
+		t.Fatalf("unexpected render:\n%q\nwant:\n%q", rendered, expected)
+	}
+}
+
+func TestRenderUpdatesNumberKey(t *testing.T) {
+	parsed, err := Parse([]byte("---\nnumber: T1\ntitle: Test\nparent: T2\n---\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	parent := IssueRef("12")
+	parsed.Number = "42"
+	parsed.Parent = &parent
+	rendered, err := Render(parsed)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	expected := "---\nnumber: 42\ntitle: Test\nparent: 12\n---\n\n"
+	if rendered != expected {
+		t.Fatalf("unexpected render:\n%q\nwant:\n%q", rendered, expected)
+	}
+}
+
+func TestKeepLocalLayout(t *testing.T) {
+	local, err := Parse([]byte("---\n# notes\ntitle: Old\nstate: open\npriority: high\n---\nBody\n"))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	remote := Issue{Title: "New", State: "open", Body: "Body\n"}
+	remote.KeepLocal(local)
+	rendered, err := Render(remote)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	expected := "---\n# notes\ntitle: New\nstate: open\npriority: high\n---\n\nBody\n"
+	if rendered != expected {
+		t.Fatalf("unexpected render:\n%q\nwant:\n%q", rendered, expected)
+	}
+}
@@ -0,0 +1,56 @@
+package issue
+
+import (
+	"regexp"
+	"strings"
+)
+
+// markerPattern matches the hidden creation marker, including the blank line
+// that separates it from the body.
+var markerPattern = regexp.MustCompile(`(?:\r?\n)*<!-- gh-issue-sync:local-id=(T[0-9A-Za-z]+) -->[ \t]*(?:\r?\n)?`)
+
+// CreationMarker returns the hidden HTML comment push appends to the bodies
+// of issues it creates. It records the local ID the issue was created from,
+// so a later push of the same T-file can find the issue instead of creating
+// it again.
+func CreationMarker(localID IssueNumber) string {
+	return "<!-- gh-issue-sync:local-id=" + localID.String() + " -->"
+}
+
+// WithCreationMarker appends the creation marker for localID to body,
+// replacing any existing one.
+func WithCreationMarker(body string, localID IssueNumber) string {
+	body = strings.TrimRight(StripCreationMarker(body), "\n")
+	if body == "" {
+		return CreationMarker(localID) + "\n"
+	}
+	return body + "\n\n" + CreationMarker(localID) + "\n"
+}
+
+// CreationMarkerID returns the local ID recorded in body's creation marker.
+func CreationMarkerID(body string) (IssueNumber, bool) {
+	match := markerPattern.FindStringSubmatch(body)
+	if match == nil {
+		return "", false
+	}
+	return IssueNumber(match[1]), true
+}
+
+// StripCreationMarker removes the creation marker from body.
+func StripCreationMarker(body string) string {
+	loc := markerPattern.FindStringIndex(body)
+	if loc == nil {
+		return body
+	}
+	before, after := body[:loc[0]], body[loc[1]:]
+	switch {
+	case before == "":
+		return after
+	case after != "":
+		// Someone moved the marker; keep the paragraphs around it apart
+		return before + "\n\n" + after
+	case strings.HasSuffix(body, "\n"):
+		return before + "\n"
+	}
+	return before
+}
@@ -0,0 +1,84 @@
+package issue
+
+import (
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectFields holds GitHub Projects (v2) custom field values, keyed by
+// project title and then by field name. Values are kept as strings: option
+// names for single-select fields, iteration titles, numbers and dates
+// (YYYY-MM-DD) in their textual form. An empty value means the field is not
+// set.
+type ProjectFields map[string]map[string]string
+
+// Normalized returns a copy without empty values and projects, or nil if
+// nothing is set.
+func (f ProjectFields) Normalized() ProjectFields {
+	var out ProjectFields
+	for project, fields := range f {
+		for name, value := range fields {
+			if value == "" {
+				continue
+			}
+			if out == nil {
+				out = ProjectFields{}
+			}
+			if out[project] == nil {
+				out[project] = map[string]string{}
+			}
+			out[project][name] = value
+		}
+	}
+	return out
+}
+
+// Equal reports whether f and other hold the same values, ignoring empty ones.
+func (f ProjectFields) Equal(other ProjectFields) bool {
+	a, b := f.Normalized(), other.Normalized()
+	if len(a) != len(b) {
+		return false
+	}
+	for project, fields := range a {
+		otherFields, ok := b[project]
+		if !ok || len(fields) != len(otherFields) {
+			return false
+		}
+		for name, value := range fields {
+			if otherFields[name] != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MarshalYAML writes values as plain scalars where possible, so numbers and
+// dates are not quoted even though they are held as strings.
+func (f ProjectFields) MarshalYAML() (interface{}, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for _, project := range slices.Sorted(maps.Keys(f)) {
+		fields := &yaml.Node{Kind: yaml.MappingNode}
+		for _, name := range slices.Sorted(maps.Keys(f[project])) {
+			fields.Content = append(fields.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: name},
+				plainScalar(f[project][name]))
+		}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: project}, fields)
+	}
+	return root, nil
+}
+
+// plainScalar returns a scalar node for value. Values that would read back as
+// something other than a string or number (booleans, null) are quoted.
+func plainScalar(value string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	switch node.ShortTag() {
+	case "!!str", "!!int", "!!float", "!!timestamp":
+	default:
+		node.Tag = "!!str"
+	}
+	return node
+}
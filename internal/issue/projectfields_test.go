@@ -0,0 +1,74 @@
+package issue
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProjectFieldsRoundTrip(t *testing.T) {
+	iss := Issue{
+		Number:   "1",
+		Title:    "Test",
+		State:    "open",
+		Projects: []string{"Roadmap"},
+		ProjectFields: ProjectFields{
+			"Roadmap": {
+				"Status":      "In Progress",
+				"Estimate":    "3",
+				"Target date": "2025-03-01",
+				"Flag":        "true",
+				"Empty":       "",
+			},
+		},
+	}
+	rendered, err := Render(iss)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	for _, want := range []string{
+		"project_fields:\n    Roadmap:\n",
+		"Estimate: 3\n",
+		"Target date: 2025-03-01\n",
+		"Status: In Progress\n",
+		"Flag: \"true\"\n",
+	} {
+		if !strings.Contains(rendered, want) {
+			t.Fatalf("expected %q in rendered issue:\n%s", want, rendered)
+		}
+	}
+	if strings.Contains(rendered, "Empty") {
+		t.Fatalf("expected empty values to be dropped:\n%s", rendered)
+	}
+
+	parsed, err := Parse([]byte(rendered))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if !parsed.ProjectFields.Equal(iss.ProjectFields) {
+		t.Fatalf("unexpected fields after round trip: %v", parsed.ProjectFields)
+	}
+	if parsed.ProjectFields["Roadmap"]["Estimate"] != "3" || parsed.ProjectFields["Roadmap"]["Target date"] != "2025-03-01" {
+		t.Fatalf("expected values to be read back as strings: %v", parsed.ProjectFields)
+	}
+}
+
+func TestProjectFieldsMerge(t *testing.T) {
+	base := Issue{Number: "1", Title: "Test", ProjectFields: ProjectFields{"Roadmap": {"Status": "Todo"}}}
+	local := base
+	local.ProjectFields = ProjectFields{"Roadmap": {"Status": "Done"}}
+	remote := base
+	remote.Title = "Renamed"
+
+	result := ThreeWayMerge(base, local, remote)
+	if !result.OK {
+		t.Fatalf("expected merge to succeed")
+	}
+	if !result.LocalChanges.ProjectFields || result.Merged.ProjectFields["Roadmap"]["Status"] != "Done" || result.Merged.Title != "Renamed" {
+		t.Fatalf("unexpected merge result: %+v", result.Merged)
+	}
+
+	remote.ProjectFields = ProjectFields{"Roadmap": {"Status": "In Progress"}}
+	if result := ThreeWayMerge(base, local, remote); result.OK || !result.ConflictingFields.ProjectFields {
+		t.Fatalf("expected project field conflict")
+	}
+}
@@ -0,0 +1,265 @@
+// Package issuetemplate reads the issue templates a repository keeps in
+// .github/ISSUE_TEMPLATE so new local issues can start from them.
+//
+// Two kinds of templates are supported: Markdown templates (front matter plus
+// body) and YAML issue forms. Issue forms are rendered the way GitHub renders
+// a submitted form, with one Markdown section per field.
+package issuetemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dir is the template directory relative to the repository root.
+const Dir = ".github/ISSUE_TEMPLATE"
+
+// Template is an issue template ready to be turned into an issue.
+type Template struct {
+	ID        string // File name without extension, used to select it
+	Name      string
+	About     string
+	Title     string // Title or title prefix, e.g. "[Bug]: "
+	Labels    []string
+	Assignees []string
+	Body      string
+	Form      bool // Whether the template is a YAML issue form
+}
+
+// stringList accepts both a YAML list and a comma separated string, as GitHub
+// does for labels and assignees.
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(node *yaml.Node) error {
+	var items []string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		items = strings.Split(node.Value, ",")
+	case yaml.SequenceNode:
+		if err := node.Decode(&items); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("line %d: expected a list or a string", node.Line)
+	}
+	*l = nil
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+type header struct {
+	Name        string     `yaml:"name"`
+	About       string     `yaml:"about"`
+	Description string     `yaml:"description"`
+	Title       string     `yaml:"title"`
+	Labels      stringList `yaml:"labels"`
+	Assignees   stringList `yaml:"assignees"`
+}
+
+type form struct {
+	header `yaml:",inline"`
+	Body   []formField `yaml:"body"`
+}
+
+type formField struct {
+	Type       string `yaml:"type"`
+	ID         string `yaml:"id"`
+	Attributes struct {
+		Label       string      `yaml:"label"`
+		Description string      `yaml:"description"`
+		Placeholder string      `yaml:"placeholder"`
+		Value       string      `yaml:"value"`
+		Render      string      `yaml:"render"`
+		Options     []yaml.Node `yaml:"options"`
+	} `yaml:"attributes"`
+}
+
+// Load reads all templates in dir, sorted by ID. A missing directory yields
+// no templates. The template chooser config (config.yml) is skipped, and so
+// are templates that cannot be read or parsed; their errors are returned as
+// skipped.
+func Load(dir string) (templates []Template, skipped []error, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		id := strings.TrimSuffix(name, filepath.Ext(name))
+		if ext != ".md" && ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		if ext != ".md" && strings.EqualFold(id, "config") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		var tmpl Template
+		if ext == ".md" {
+			tmpl, err = ParseMarkdown(data)
+		} else {
+			tmpl, err = ParseForm(data)
+		}
+		if err != nil {
+			skipped = append(skipped, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		tmpl.ID = id
+		if tmpl.Name == "" {
+			tmpl.Name = id
+		}
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].ID < templates[j].ID })
+	return templates, skipped, nil
+}
+
+// Find returns the template whose ID or name matches name, ignoring case.
+func Find(templates []Template, name string) (Template, bool) {
+	for _, tmpl := range templates {
+		if strings.EqualFold(tmpl.ID, name) || strings.EqualFold(tmpl.Name, name) {
+			return tmpl, true
+		}
+	}
+	return Template{}, false
+}
+
+// ParseMarkdown parses a Markdown issue template.
+func ParseMarkdown(data []byte) (Template, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	var h header
+	body := data
+	if bytes.HasPrefix(data, []byte("---\n")) {
+		rest := data[len("---\n"):]
+		end := bytes.Index(rest, []byte("\n---"))
+		if end < 0 {
+			return Template{}, fmt.Errorf("unterminated front matter")
+		}
+		if err := yaml.Unmarshal(rest[:end], &h); err != nil {
+			return Template{}, err
+		}
+		body = rest[end+len("\n---"):]
+		if i := bytes.IndexByte(body, '\n'); i >= 0 {
+			body = body[i+1:]
+		} else {
+			body = nil
+		}
+	}
+	return Template{
+		Name:      h.Name,
+		About:     h.About,
+		Title:     h.Title,
+		Labels:    h.Labels,
+		Assignees: h.Assignees,
+		Body:      strings.TrimLeft(string(body), "\n"),
+	}, nil
+}
+
+// ParseForm parses a YAML issue form and renders its fields as Markdown
+// sections.
+func ParseForm(data []byte) (Template, error) {
+	var f form
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return Template{}, err
+	}
+	about := f.Description
+	if about == "" {
+		about = f.About
+	}
+	return Template{
+		Name:      f.Name,
+		About:     about,
+		Title:     f.Title,
+		Labels:    f.Labels,
+		Assignees: f.Assignees,
+		Body:      renderForm(f.Body),
+		Form:      true,
+	}, nil
+}
+
+// renderForm renders form fields like GitHub renders a submitted form: a
+// heading per field followed by its value. Descriptions, placeholders and
+// dropdown options become HTML comments so they guide the author without
+// showing up on GitHub.
+func renderForm(fields []formField) string {
+	var sections []string
+	for _, field := range fields {
+		attrs := field.Attributes
+		if field.Type == "markdown" || attrs.Label == "" {
+			continue
+		}
+		var lines []string
+		lines = append(lines, "### "+attrs.Label, "")
+		if hint := formHint(field); hint != "" {
+			lines = append(lines, "<!-- "+hint+" -->", "")
+		}
+		switch field.Type {
+		case "checkboxes":
+			for _, option := range attrs.Options {
+				label := option.Value
+				if option.Kind == yaml.MappingNode {
+					var item struct {
+						Label string `yaml:"label"`
+					}
+					_ = option.Decode(&item)
+					label = item.Label
+				}
+				lines = append(lines, "- [ ] "+label)
+			}
+		default:
+			value := strings.TrimRight(attrs.Value, "\n")
+			if value != "" && attrs.Render != "" {
+				value = "```" + attrs.Render + "\n" + value + "\n```"
+			}
+			if value != "" {
+				lines = append(lines, value)
+			}
+		}
+		sections = append(sections, strings.TrimRight(strings.Join(lines, "\n"), "\n"))
+	}
+	if len(sections) == 0 {
+		return ""
+	}
+	return strings.Join(sections, "\n\n") + "\n"
+}
+
+func formHint(field formField) string {
+	attrs := field.Attributes
+	var parts []string
+	if s := strings.TrimSpace(attrs.Description); s != "" {
+		parts = append(parts, s)
+	}
+	if s := strings.TrimSpace(attrs.Placeholder); s != "" && attrs.Value == "" {
+		parts = append(parts, "e.g. "+s)
+	}
+	if field.Type == "dropdown" && len(attrs.Options) > 0 {
+		options := make([]string, 0, len(attrs.Options))
+		for _, option := range attrs.Options {
+			options = append(options, option.Value)
+		}
+		parts = append(parts, "Options: "+strings.Join(options, ", "))
+	}
+	hint := strings.Join(parts, " ")
+	return strings.ReplaceAll(hint, "-->", "-- >")
+}
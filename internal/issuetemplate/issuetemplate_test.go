@@ -0,0 +1,130 @@
+package issuetemplate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	data := []byte(`---
+name: Bug report
+about: Create a report to help us improve
+title: "[Bug]: "
+labels: bug, triage
+assignees:
+  - octocat
+---
+
+**Describe the bug**
+A clear description.
+`)
+	tmpl, err := ParseMarkdown(data)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if tmpl.Name != "Bug report" || tmpl.Title != "[Bug]: " {
+		t.Fatalf("unexpected template: %+v", tmpl)
+	}
+	if strings.Join(tmpl.Labels, ",") != "bug,triage" || strings.Join(tmpl.Assignees, ",") != "octocat" {
+		t.Fatalf("unexpected labels/assignees: %v %v", tmpl.Labels, tmpl.Assignees)
+	}
+	if tmpl.Body != "**Describe the bug**\nA clear description.\n" {
+		t.Fatalf("unexpected body: %q", tmpl.Body)
+	}
+}
+
+func TestParseForm(t *testing.T) {
+	data := []byte(`name: Feature request
+description: Suggest an idea
+labels: ["enhancement"]
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for taking the time!
+  - type: textarea
+    id: problem
+    attributes:
+      label: Problem
+      description: What problem does this solve?
+      placeholder: I'm always frustrated when...
+  - type: dropdown
+    attributes:
+      label: Priority
+      options:
+        - Low
+        - High
+  - type: textarea
+    attributes:
+      label: Logs
+      render: shell
+      value: "$ run"
+  - type: checkboxes
+    attributes:
+      label: Checklist
+      options:
+        - label: I searched existing issues
+`)
+	tmpl, err := ParseForm(data)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if !tmpl.Form || tmpl.About != "Suggest an idea" || strings.Join(tmpl.Labels, ",") != "enhancement" {
+		t.Fatalf("unexpected template: %+v", tmpl)
+	}
+	expected := `### Problem
+
+<!-- What problem does this solve? e.g. I'm always frustrated when... -->
+
+### Priority
+
+<!-- Options: Low, High -->
+
+### Logs
+
+` + "```shell\n$ run\n```" + `
+
+### Checklist
+
+- [ ] I searched existing issues
+`
+	if tmpl.Body != expected {
+		t.Fatalf("unexpected body:\n%s", tmpl.Body)
+	}
+}
+
+func TestLoadAndFind(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"bug_report.md": "---\nname: Bug report\n---\nBody\n",
+		"feature.yml":   "name: Feature\nbody: []\n",
+		"config.yml":    "blank_issues_enabled: false\n",
+		"README.txt":    "ignored",
+		"broken.yml":    "name: [unclosed\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	templates, skipped, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if len(skipped) != 1 || !strings.Contains(skipped[0].Error(), "broken.yml") {
+		t.Fatalf("expected broken template to be skipped, got %v", skipped)
+	}
+	if len(templates) != 2 || templates[0].ID != "bug_report" || templates[1].ID != "feature" {
+		t.Fatalf("unexpected templates: %+v", templates)
+	}
+	if tmpl, ok := Find(templates, "bug report"); !ok || tmpl.ID != "bug_report" {
+		t.Fatalf("expected to find template by name, got %+v %v", tmpl, ok)
+	}
+	if _, ok := Find(templates, "missing"); ok {
+		t.Fatalf("expected no match")
+	}
+	if missing, _, err := Load(filepath.Join(dir, "missing")); err != nil || missing != nil {
+		t.Fatalf("expected no templates for a missing directory, got %v %v", missing, err)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package lock
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLockFile(f *os.File, mode Mode) (bool, error) {
+	return false, errors.ErrUnsupported
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -2,10 +2,11 @@ package lock
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"syscall"
+	"strings"
 	"time"
 )
 
@@ -15,20 +16,61 @@ const (
 	PollInterval   = 100 * time.Millisecond
 )
 
+// ErrTimeout is returned by Acquire when another process holds the lock for
+// longer than the timeout.
+var ErrTimeout = errors.New("timeout waiting for lock (another process may be running)")
+
+// Mode selects between shared (read) and exclusive (write) locking.
+type Mode int
+
+const (
+	// Exclusive locks are held by commands that modify issue files.
+	Exclusive Mode = iota
+	// Shared locks are held by read-only commands; any number of them can be
+	// held at once, but not together with an exclusive lock.
+	Shared
+)
+
+func (m Mode) String() string {
+	if m == Shared {
+		return "shared"
+	}
+	return "exclusive"
+}
+
+// LockInfo describes the holder of an exclusive lock. It is written into the
+// lock file for diagnostics only; the lock itself is an OS file lock
+// (flock on Unix, LockFileEx on Windows), which is released automatically
+// when the holder exits.
 type LockInfo struct {
 	PID       int       `json:"pid"`
+	Host      string    `json:"host,omitempty"`
+	Command   string    `json:"command,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 type Lock struct {
 	path string
+	file *os.File
+	mode Mode
 }
 
-// Acquire tries to acquire a lock in the given directory.
+// Acquire takes an exclusive lock in the given directory.
 // It will block up to timeout waiting for the lock to become available.
-// Returns a Lock that must be released when done, or an error if the lock
+// Returns a Lock that must be released when done, or ErrTimeout if the lock
 // could not be acquired within the timeout.
 func Acquire(lockDir string, timeout time.Duration) (*Lock, error) {
+	return AcquireMode(lockDir, Exclusive, timeout)
+}
+
+// AcquireShared takes a shared lock in the given directory, waiting up to
+// timeout for an exclusive holder to finish.
+func AcquireShared(lockDir string, timeout time.Duration) (*Lock, error) {
+	return AcquireMode(lockDir, Shared, timeout)
+}
+
+// AcquireMode takes a lock of the given mode in lockDir.
+func AcquireMode(lockDir string, mode Mode, timeout time.Duration) (*Lock, error) {
 	if err := os.MkdirAll(lockDir, 0o755); err != nil {
 		return nil, fmt.Errorf("failed to create lock directory: %w", err)
 	}
@@ -38,17 +80,17 @@ func Acquire(lockDir string, timeout time.Duration) (*Lock, error) {
 
 	for {
 		// Try to acquire the lock
-		acquired, err := tryAcquire(lockPath)
+		lck, err := tryAcquire(lockPath, mode)
 		if err != nil {
 			return nil, err
 		}
-		if acquired {
-			return &Lock{path: lockPath}, nil
+		if lck != nil {
+			return lck, nil
 		}
 
 		// Check if we've exceeded the timeout
 		if time.Now().After(deadline) {
-			return nil, fmt.Errorf("timeout waiting for lock (another process may be running)")
+			return nil, ErrTimeout
 		}
 
 		// Wait before trying again
@@ -57,98 +99,165 @@ func Acquire(lockDir string, timeout time.Duration) (*Lock, error) {
 }
 
 // tryAcquire attempts to acquire the lock once.
-// Returns true if the lock was acquired, false if it's held by another process.
-func tryAcquire(lockPath string) (bool, error) {
-	// Check if lock file exists
-	data, err := os.ReadFile(lockPath)
-	if err == nil {
-		// Lock file exists, check if the process is still alive
-		var info LockInfo
-		if err := json.Unmarshal(data, &info); err == nil {
-			if isProcessAlive(info.PID) {
-				// Process is still alive, lock is valid
-				return false, nil
-			}
-			// Process is dead, remove stale lock
-			os.Remove(lockPath)
-		} else {
-			// Corrupted lock file, remove it
-			os.Remove(lockPath)
+// Returns nil without an error if the lock is held by another process.
+func tryAcquire(lockPath string, mode Mode) (*Lock, error) {
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	locked, err := tryLockFile(f, mode)
+	if err != nil || !locked {
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock %s: %w", lockPath, err)
+		}
+		return nil, nil
+	}
+
+	// The previous exclusive holder removes the file on release. If that
+	// happened between our open and lock, we locked an orphaned file and
+	// have to start over with the new one.
+	if !sameFile(f, lockPath) {
+		unlockFile(f)
+		f.Close()
+		return nil, nil
+	}
+
+	lck := &Lock{path: lockPath, file: f, mode: mode}
+	if mode == Exclusive {
+		if err := writeInfo(f); err != nil {
+			lck.Release()
+			return nil, fmt.Errorf("failed to write lock file: %w", err)
 		}
-	} else if !os.IsNotExist(err) {
-		return false, fmt.Errorf("failed to read lock file: %w", err)
 	}
+	return lck, nil
+}
+
+func sameFile(f *os.File, path string) bool {
+	opened, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(opened, current)
+}
 
-	// Try to create the lock file atomically
+func writeInfo(f *os.File) error {
 	info := LockInfo{
 		PID:       os.Getpid(),
+		Command:   commandLine(),
 		CreatedAt: time.Now().UTC(),
 	}
-	data, err = json.Marshal(info)
+	info.Host, _ = os.Hostname()
+	data, err := json.Marshal(info)
 	if err != nil {
-		return false, fmt.Errorf("failed to marshal lock info: %w", err)
+		return err
 	}
-
-	// Use O_EXCL for atomic creation
-	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
-	if err != nil {
-		if os.IsExist(err) {
-			// Another process created the lock first
-			return false, nil
-		}
-		return false, fmt.Errorf("failed to create lock file: %w", err)
+	if err := f.Truncate(0); err != nil {
+		return err
 	}
-	defer f.Close()
+	_, err = f.WriteAt(append(data, '\n'), 0)
+	return err
+}
 
-	if _, err := f.Write(data); err != nil {
-		os.Remove(lockPath)
-		return false, fmt.Errorf("failed to write lock file: %w", err)
+func commandLine() string {
+	if len(os.Args) == 0 {
+		return ""
 	}
-
-	return true, nil
+	args := append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...)
+	return strings.Join(args, " ")
 }
 
 // Release releases the lock.
 func (l *Lock) Release() error {
-	if l == nil || l.path == "" {
+	if l == nil || l.file == nil {
 		return nil
 	}
+	f := l.file
+	l.file = nil
+
+	// An exclusive holder removes the lock file so stale holder info does not
+	// linger. Shared holders leave it, other readers may still use it.
+	owned := l.mode == Exclusive && sameFile(f, l.path)
+	removed := false
+	if owned {
+		removed = os.Remove(l.path) == nil
+	}
+	unlockErr := unlockFile(f)
+	closeErr := f.Close()
+	if owned && !removed {
+		// Windows cannot remove files that are still open. If another
+		// process opened it in the meantime the file simply stays.
+		_ = os.Remove(l.path)
+	}
+	if unlockErr != nil {
+		return unlockErr
+	}
+	return closeErr
+}
 
-	// Verify we still own the lock before releasing
-	data, err := os.ReadFile(l.path)
+// Status describes the current state of a lock.
+type Status struct {
+	Held bool
+	Mode Mode
+	// Info is the last exclusive holder's info, if recorded. When Held is
+	// false it belongs to a holder that exited without cleaning up.
+	Info *LockInfo
+}
+
+// Inspect reports whether the lock in lockDir is held and by whom, without
+// waiting for it.
+func Inspect(lockDir string) (Status, error) {
+	lockPath := filepath.Join(lockDir, LockFileName)
+	f, err := os.OpenFile(lockPath, os.O_RDWR, 0)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil // Lock already released
+			return Status{}, nil
 		}
-		return fmt.Errorf("failed to read lock file: %w", err)
+		return Status{}, fmt.Errorf("failed to open lock file: %w", err)
 	}
+	defer f.Close()
 
-	var info LockInfo
-	if err := json.Unmarshal(data, &info); err != nil {
-		// Corrupted, just remove it
-		return os.Remove(l.path)
+	var status Status
+	data, err := os.ReadFile(lockPath)
+	if err == nil && len(strings.TrimSpace(string(data))) > 0 {
+		var info LockInfo
+		if json.Unmarshal(data, &info) == nil {
+			status.Info = &info
+		}
 	}
 
-	if info.PID != os.Getpid() {
-		// Not our lock anymore (shouldn't happen, but be safe)
-		return nil
+	for _, mode := range []Mode{Exclusive, Shared} {
+		locked, err := tryLockFile(f, mode)
+		if err != nil {
+			return Status{}, fmt.Errorf("failed to lock %s: %w", lockPath, err)
+		}
+		if locked {
+			unlockFile(f)
+			if mode == Shared {
+				// Readers hold it; any recorded info is from an old writer
+				status.Held = true
+				status.Mode = Shared
+				status.Info = nil
+			}
+			return status, nil
+		}
 	}
-
-	return os.Remove(l.path)
+	status.Held = true
+	status.Mode = Exclusive
+	return status, nil
 }
 
-// isProcessAlive checks if a process with the given PID is still running.
-func isProcessAlive(pid int) bool {
-	if pid <= 0 {
-		return false
+// Break removes the lock file so new commands no longer wait for the current
+// holder. A process that still holds the old lock keeps running unaware, so
+// this is only safe when the holder is known to be gone or hung.
+func Break(lockDir string) error {
+	err := os.Remove(filepath.Join(lockDir, LockFileName))
+	if err != nil && !os.IsNotExist(err) {
+		return err
 	}
-
-	process, err := os.FindProcess(pid)
-	if err != nil {
-		return false
-	}
-
-	// On Unix, FindProcess always succeeds. Send signal 0 to check if process exists.
-	err = process.Signal(syscall.Signal(0))
-	return err == nil
+	return nil
 }
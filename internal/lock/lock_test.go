@@ -120,3 +120,75 @@ func TestNilRelease(t *testing.T) {
 		t.Fatalf("nil release should not error: %v", err)
 	}
 }
+
+func TestSharedLocks(t *testing.T) {
+	dir := t.TempDir()
+
+	reader1, err := AcquireShared(dir, DefaultTimeout)
+	if err != nil {
+		t.Fatalf("failed to acquire first shared lock: %v", err)
+	}
+	reader2, err := AcquireShared(dir, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("shared locks should not block each other: %v", err)
+	}
+
+	// Writers wait for readers
+	if _, err := Acquire(dir, 200*time.Millisecond); err != ErrTimeout {
+		t.Fatalf("expected exclusive acquire to time out, got %v", err)
+	}
+	status, err := Inspect(dir)
+	if err != nil || !status.Held || status.Mode != Shared {
+		t.Fatalf("expected shared lock status, got %+v %v", status, err)
+	}
+
+	reader1.Release()
+	reader2.Release()
+
+	writer, err := Acquire(dir, DefaultTimeout)
+	if err != nil {
+		t.Fatalf("failed to acquire exclusive lock after readers released: %v", err)
+	}
+	defer writer.Release()
+
+	// Readers wait for writers
+	if _, err := AcquireShared(dir, 200*time.Millisecond); err != ErrTimeout {
+		t.Fatalf("expected shared acquire to time out, got %v", err)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	dir := t.TempDir()
+
+	status, err := Inspect(dir)
+	if err != nil || status.Held {
+		t.Fatalf("expected unlocked status, got %+v %v", status, err)
+	}
+
+	lck, err := Acquire(dir, DefaultTimeout)
+	if err != nil {
+		t.Fatalf("failed to acquire lock: %v", err)
+	}
+	status, err = Inspect(dir)
+	if err != nil {
+		t.Fatalf("inspect failed: %v", err)
+	}
+	if !status.Held || status.Mode != Exclusive || status.Info == nil {
+		t.Fatalf("expected exclusive lock with info, got %+v", status)
+	}
+	host, _ := os.Hostname()
+	if status.Info.PID != os.Getpid() || status.Info.Host != host || status.Info.Command == "" {
+		t.Fatalf("unexpected lock info: %+v", status.Info)
+	}
+
+	// Breaking the lock lets others in even though the holder still runs
+	if err := Break(dir); err != nil {
+		t.Fatalf("break failed: %v", err)
+	}
+	other, err := Acquire(dir, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("expected acquire after break to succeed: %v", err)
+	}
+	other.Release()
+	lck.Release()
+}
@@ -0,0 +1,290 @@
+// Package oplog records pull and push operations so they can be undone.
+//
+// Each operation stores a snapshot of the issue files before and after it
+// ran. File contents live in a content-addressed blob store shared by all
+// snapshots, so recording an operation only costs the files that changed.
+package oplog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	OpsDirName   = "ops"
+	BlobsDirName = "blobs"
+	// MaxOperations is the number of operations kept for undo.
+	MaxOperations = 20
+)
+
+// Snapshot maps file paths (slash separated, relative to the snapshot root)
+// to the hash of their contents.
+type Snapshot map[string]string
+
+// Equal reports whether two snapshots describe the same files.
+func (s Snapshot) Equal(other Snapshot) bool {
+	return maps.Equal(s, other)
+}
+
+// IssueState holds the remotely editable fields of an issue.
+type IssueState struct {
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	Labels      []string `json:"labels,omitempty"`
+	Assignees   []string `json:"assignees,omitempty"`
+	Milestone   string   `json:"milestone,omitempty"`
+	State       string   `json:"state"`
+	StateReason string   `json:"state_reason,omitempty"`
+}
+
+// RemoteChange is an issue a push created or edited on GitHub.
+type RemoteChange struct {
+	Number  string `json:"number"`
+	Created bool   `json:"created,omitempty"`
+	// Before and After are the issue as it was on GitHub before the push and
+	// as the push left it. They are unset for created issues.
+	Before *IssueState `json:"before,omitempty"`
+	After  *IssueState `json:"after,omitempty"`
+}
+
+// Operation is a recorded pull or push.
+type Operation struct {
+	ID        string         `json:"id"`
+	Kind      string         `json:"kind"`
+	CreatedAt time.Time      `json:"created_at"`
+	Before    Snapshot       `json:"before"`
+	After     Snapshot       `json:"after"`
+	Remote    []RemoteChange `json:"remote,omitempty"`
+	// LastFullPull is the last full pull time from the config before the
+	// operation ran.
+	LastFullPull *time.Time `json:"last_full_pull,omitempty"`
+}
+
+// Log is an operations log stored in a directory.
+type Log struct {
+	Dir string
+	// Skip, if set, leaves files out of snapshots and restores. It gets the
+	// slash separated path relative to the snapshot root.
+	Skip func(rel string) bool
+}
+
+// New returns the log stored in dir.
+func New(dir string) *Log {
+	return &Log{Dir: dir}
+}
+
+func (l *Log) opsDir() string {
+	return filepath.Join(l.Dir, OpsDirName)
+}
+
+func (l *Log) blobPath(hash string) string {
+	return filepath.Join(l.Dir, BlobsDirName, hash[:2], hash[2:])
+}
+
+// Snapshot stores the files in dirs (relative to root) and returns a snapshot
+// of them. Missing directories are treated as empty.
+func (l *Log) Snapshot(root string, dirs []string) (Snapshot, error) {
+	snap := Snapshot{}
+	for _, dir := range dirs {
+		err := filepath.WalkDir(filepath.Join(root, dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if l.Skip != nil && l.Skip(rel) {
+				return nil
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			hash, err := l.storeBlob(data)
+			if err != nil {
+				return err
+			}
+			snap[rel] = hash
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snap, nil
+}
+
+func (l *Log) storeBlob(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	path := l.blobPath(hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", err
+	}
+	return hash, os.Rename(tmp, path)
+}
+
+// Restore makes the files in dirs (relative to root) match snap: files are
+// rewritten from the blob store and files not in the snapshot are removed.
+// Skipped files are left alone.
+func (l *Log) Restore(root string, dirs []string, snap Snapshot) error {
+	current, err := l.Snapshot(root, dirs)
+	if err != nil {
+		return err
+	}
+	for rel := range current {
+		if _, ok := snap[rel]; !ok {
+			if err := os.Remove(filepath.Join(root, filepath.FromSlash(rel))); err != nil {
+				return err
+			}
+		}
+	}
+	for rel, hash := range snap {
+		if current[rel] == hash {
+			continue
+		}
+		data, err := os.ReadFile(l.blobPath(hash))
+		if err != nil {
+			return fmt.Errorf("missing snapshot of %s: %w", rel, err)
+		}
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Record adds an operation to the log, dropping the oldest ones beyond
+// MaxOperations.
+func (l *Log) Record(op Operation) error {
+	if op.ID == "" {
+		op.ID = op.CreatedAt.UTC().Format("20060102T150405.000000000Z")
+	}
+	if err := os.MkdirAll(l.opsDir(), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(filepath.Join(l.opsDir(), op.ID+".json"), data, 0o644); err != nil {
+		return err
+	}
+	ops, err := l.List()
+	if err != nil {
+		return err
+	}
+	if len(ops) > MaxOperations {
+		for _, old := range ops[MaxOperations:] {
+			if err := os.Remove(filepath.Join(l.opsDir(), old.ID+".json")); err != nil {
+				return err
+			}
+		}
+		ops = ops[:MaxOperations]
+	}
+	// Also drops blobs of snapshots that were taken but never recorded
+	return l.collectGarbage(ops)
+}
+
+// List returns the recorded operations, newest first.
+func (l *Log) List() ([]Operation, error) {
+	entries, err := os.ReadDir(l.opsDir())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var ops []Operation
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(l.opsDir(), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var op Operation
+		if err := json.Unmarshal(data, &op); err != nil {
+			return nil, fmt.Errorf("invalid operation %s: %w", entry.Name(), err)
+		}
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].ID > ops[j].ID
+	})
+	return ops, nil
+}
+
+// Remove deletes an operation from the log, along with blobs no other
+// operation needs.
+func (l *Log) Remove(id string) error {
+	if err := os.Remove(filepath.Join(l.opsDir(), id+".json")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	ops, err := l.List()
+	if err != nil {
+		return err
+	}
+	return l.collectGarbage(ops)
+}
+
+// collectGarbage removes blobs not referenced by any of ops.
+func (l *Log) collectGarbage(ops []Operation) error {
+	used := make(map[string]struct{})
+	for _, op := range ops {
+		for _, hash := range op.Before {
+			used[hash] = struct{}{}
+		}
+		for _, hash := range op.After {
+			used[hash] = struct{}{}
+		}
+	}
+	blobsDir := filepath.Join(l.Dir, BlobsDirName)
+	return filepath.WalkDir(blobsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		hash := filepath.Base(filepath.Dir(path)) + d.Name()
+		if _, ok := used[hash]; !ok {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
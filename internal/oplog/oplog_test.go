@@ -0,0 +1,107 @@
+package oplog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	root := t.TempDir()
+	log := New(filepath.Join(root, ".sync", "oplog"))
+	dirs := []string{"open", "closed"}
+	writeFile(t, filepath.Join(root, "open", "1-a.md"), "one")
+	writeFile(t, filepath.Join(root, "open", "2-b.md"), "two")
+	if err := os.MkdirAll(filepath.Join(root, "closed"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	before, err := log.Snapshot(root, dirs)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if len(before) != 2 || before["open/1-a.md"] == "" {
+		t.Fatalf("unexpected snapshot %v", before)
+	}
+
+	// Edit, move and add files
+	writeFile(t, filepath.Join(root, "open", "1-a.md"), "changed")
+	if err := os.Rename(filepath.Join(root, "open", "2-b.md"), filepath.Join(root, "closed", "2-b.md")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	writeFile(t, filepath.Join(root, "open", "3-c.md"), "three")
+	after, err := log.Snapshot(root, dirs)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if after.Equal(before) {
+		t.Fatalf("expected snapshots to differ")
+	}
+	if err := log.Record(Operation{Kind: "pull", CreatedAt: time.Now(), Before: before, After: after}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	ops, err := log.List()
+	if err != nil || len(ops) != 1 {
+		t.Fatalf("expected one operation, got %v (%v)", ops, err)
+	}
+	if err := log.Restore(root, dirs, ops[0].Before); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	restored, err := log.Snapshot(root, dirs)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if !restored.Equal(before) {
+		t.Fatalf("expected restored files to match, got %v", restored)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "open", "1-a.md")); string(data) != "one" {
+		t.Fatalf("expected original content, got %q", data)
+	}
+}
+
+func TestRecordPrunesOldOperations(t *testing.T) {
+	root := t.TempDir()
+	log := New(filepath.Join(root, "oplog"))
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < MaxOperations+3; i++ {
+		writeFile(t, filepath.Join(root, "open", "1.md"), time.Duration(i).String())
+		snap, err := log.Snapshot(root, []string{"open"})
+		if err != nil {
+			t.Fatalf("snapshot: %v", err)
+		}
+		if err := log.Record(Operation{Kind: "pull", CreatedAt: start.Add(time.Duration(i) * time.Minute), Before: snap, After: snap}); err != nil {
+			t.Fatalf("record: %v", err)
+		}
+	}
+	ops, err := log.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(ops) != MaxOperations || !ops[0].CreatedAt.Equal(start.Add(time.Duration(MaxOperations+2)*time.Minute)) {
+		t.Fatalf("expected the newest %d operations, got %d starting at %v", MaxOperations, len(ops), ops[0].CreatedAt)
+	}
+
+	// Blobs of dropped operations are gone
+	blobs := 0
+	filepath.WalkDir(filepath.Join(root, "oplog", BlobsDirName), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			blobs++
+		}
+		return nil
+	})
+	if blobs != MaxOperations {
+		t.Fatalf("expected %d blobs, got %d", MaxOperations, blobs)
+	}
+}
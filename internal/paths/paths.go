@@ -8,36 +8,58 @@ import (
 const EnvIssuesDir = "GH_ISSUE_SYNC_DIR"
 
 const (
-	IssuesDirName      = ".issues"
-	SyncDirName        = ".sync"
-	OriginalsDirName   = "originals"
-	OpenDirName        = "open"
-	ClosedDirName      = "closed"
-	ConfigFileName     = "config.json"
-	LabelsFileName     = "labels.json"
-	MilestonesFileName = "milestones.json"
-	IssueTypesFileName = "issue_types.json"
-	ProjectsFileName   = "projects.json"
+	IssuesDirName         = ".issues"
+	SyncDirName           = ".sync"
+	OriginalsDirName      = "originals"
+	AssetsDirName         = "assets"
+	OplogDirName          = "oplog"
+	HistoryDirName        = "history"
+	TimelineDirName       = "timeline"
+	OpenDirName           = "open"
+	ClosedDirName         = "closed"
+	ConfigFileName        = "config.json"
+	LabelsFileName        = "labels.json"
+	MilestonesFileName    = "milestones.json"
+	IssueTypesFileName    = "issue_types.json"
+	ProjectsFileName      = "projects.json"
+	ProjectFieldsFileName = "project_fields.json"
+	JournalFileName       = "push_journal.json"
+	IDMapFileName         = "id_map.json"
+	GitScanFileName       = "git_scan.json"
+	CodeRefsFileName      = "code_refs.json"
 )
 
 type Paths struct {
-	Root           string
-	IssuesDir      string
-	SyncDir        string
-	OriginalsDir   string
-	OpenDir        string
-	ClosedDir      string
-	ConfigPath     string
-	LabelsPath     string
-	MilestonesPath string
-	IssueTypesPath string
-	ProjectsPath   string
+	Root              string
+	IssuesDir         string
+	SyncDir           string
+	OriginalsDir      string
+	AssetsDir         string
+	OplogDir          string
+	HistoryDir        string
+	TimelineDir       string
+	OpenDir           string
+	ClosedDir         string
+	ConfigPath        string
+	LabelsPath        string
+	MilestonesPath    string
+	IssueTypesPath    string
+	ProjectsPath      string
+	ProjectFieldsPath string
+	JournalPath       string
+	IDMapPath         string
+	GitScanPath       string
+	CodeRefsPath      string
 }
 
 func New(root string) Paths {
 	issuesDir := filepath.Join(root, IssuesDirName)
 	syncDir := filepath.Join(issuesDir, SyncDirName)
 	originalsDir := filepath.Join(syncDir, OriginalsDirName)
+	assetsDir := filepath.Join(syncDir, AssetsDirName)
+	oplogDir := filepath.Join(syncDir, OplogDirName)
+	historyDir := filepath.Join(syncDir, HistoryDirName)
+	timelineDir := filepath.Join(syncDir, TimelineDirName)
 	openDir := filepath.Join(issuesDir, OpenDirName)
 	closedDir := filepath.Join(issuesDir, ClosedDirName)
 	configPath := filepath.Join(syncDir, ConfigFileName)
@@ -46,19 +68,33 @@ func New(root string) Paths {
 	issueTypesPath := filepath.Join(syncDir, IssueTypesFileName)
 
 	projectsPath := filepath.Join(syncDir, ProjectsFileName)
+	projectFieldsPath := filepath.Join(syncDir, ProjectFieldsFileName)
+	journalPath := filepath.Join(syncDir, JournalFileName)
+	idMapPath := filepath.Join(syncDir, IDMapFileName)
+	gitScanPath := filepath.Join(syncDir, GitScanFileName)
+	codeRefsPath := filepath.Join(syncDir, CodeRefsFileName)
 
 	return Paths{
-		Root:           root,
-		IssuesDir:      issuesDir,
-		SyncDir:        syncDir,
-		OriginalsDir:   originalsDir,
-		OpenDir:        openDir,
-		ClosedDir:      closedDir,
-		ConfigPath:     configPath,
-		LabelsPath:     labelsPath,
-		MilestonesPath: milestonesPath,
-		IssueTypesPath: issueTypesPath,
-		ProjectsPath:   projectsPath,
+		Root:              root,
+		IssuesDir:         issuesDir,
+		SyncDir:           syncDir,
+		OriginalsDir:      originalsDir,
+		AssetsDir:         assetsDir,
+		OplogDir:          oplogDir,
+		HistoryDir:        historyDir,
+		TimelineDir:       timelineDir,
+		OpenDir:           openDir,
+		ClosedDir:         closedDir,
+		ConfigPath:        configPath,
+		LabelsPath:        labelsPath,
+		MilestonesPath:    milestonesPath,
+		IssueTypesPath:    issueTypesPath,
+		ProjectsPath:      projectsPath,
+		ProjectFieldsPath: projectFieldsPath,
+		JournalPath:       journalPath,
+		IDMapPath:         idMapPath,
+		GitScanPath:       gitScanPath,
+		CodeRefsPath:      codeRefsPath,
 	}
 }
 
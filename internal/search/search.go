@@ -3,6 +3,7 @@ package search
 
 import (
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/mitsuhiko/gh-issue-sync/internal/issue"
@@ -27,12 +28,22 @@ type Query struct {
 	NoType    bool     // no:type
 	Projects  []string // project:X
 	NoProject bool     // no:project
+	LinkedPR  bool     // linked:pr
+	NoLinkedPR bool    // no:linked-pr
+	Extras    []ExtraFilter // extra.KEY:VALUE, extra.KEY:>N
 
 	// Sort
 	SortField string // "created", "updated", "comments" (default: "created")
 	SortAsc   bool   // true for ascending, false for descending (default: false = desc)
 }
 
+// ExtraFilter matches a local-only extra front matter field.
+type ExtraFilter struct {
+	Key   string
+	Op    string // "=", ">", ">=", "<" or "<="
+	Value string
+}
+
 // Parse parses a GitHub-style search query string.
 // Examples:
 //   - "error no:assignee sort:created-asc"
@@ -85,6 +96,10 @@ func Parse(query string) Query {
 				q.Types = append(q.Types, value)
 			case "project":
 				q.Projects = append(q.Projects, value)
+			case "linked":
+				if strings.EqualFold(value, "pr") {
+					q.LinkedPR = true
+				}
 			case "no":
 				switch strings.ToLower(value) {
 				case "label":
@@ -97,10 +112,17 @@ func Parse(query string) Query {
 					q.NoType = true
 				case "project":
 					q.NoProject = true
+				case "linked-pr":
+					q.NoLinkedPR = true
 				}
 			case "sort":
 				parseSortValue(&q, value)
 			default:
+				// The prefix matches in any case, the key is kept as written
+				if name := tok[:idx]; len(name) > len("extra.") && strings.EqualFold(name[:len("extra.")], "extra.") {
+					q.Extras = append(q.Extras, parseExtraFilter(name[len("extra."):], value))
+					continue
+				}
 				// Unknown qualifier, treat as text
 				textParts = append(textParts, tok)
 			}
@@ -138,6 +160,57 @@ func parseSortValue(q *Query, value string) {
 	}
 }
 
+// parseExtraFilter parses values like "3", ">3" or "<=high" for an extra field.
+func parseExtraFilter(key, value string) ExtraFilter {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if rest, ok := strings.CutPrefix(value, op); ok {
+			return ExtraFilter{Key: key, Op: op, Value: strings.Trim(rest, "\"'")}
+		}
+	}
+	return ExtraFilter{Key: key, Op: "=", Value: value}
+}
+
+// Match reports whether any of the field values satisfies the filter. Values
+// are compared numerically when both sides are numbers, otherwise as
+// case-insensitive strings.
+func (f ExtraFilter) Match(values []string) bool {
+	for _, value := range values {
+		if compareSatisfies(compareExtra(value, f.Value), f.Op) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareExtra(a, b string) int {
+	fa, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	fb, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func compareSatisfies(cmp int, op string) bool {
+	switch op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return cmp == 0
+}
+
 // tokenize splits the query into tokens, respecting quoted strings
 func tokenize(query string) []string {
 	var tokens []string
@@ -190,9 +263,11 @@ type IssueData struct {
 	Milestone string
 	IssueType string
 	Projects  []string
+	LinkedPRs int    // Number of linked pull requests
 	SyncedAt  *int64 // Unix timestamp, nil if not synced
 	CreatedAt *int64 // Unix timestamp from GitHub
 	UpdatedAt *int64 // Unix timestamp from GitHub
+	Extra     map[string][]string // Local-only extra front matter values
 }
 
 // Match returns true if the issue matches the query.
@@ -259,6 +334,27 @@ func (q *Query) Match(iss IssueData) bool {
 		}
 	}
 
+	// Linked pull request filters
+	if q.LinkedPR && iss.LinkedPRs == 0 {
+		return false
+	}
+	if q.NoLinkedPR && iss.LinkedPRs > 0 {
+		return false
+	}
+
+	// Extra field filters (keys match case-insensitively)
+	for _, filter := range q.Extras {
+		var values []string
+		for key, v := range iss.Extra {
+			if strings.EqualFold(key, filter.Key) {
+				values = append(values, v...)
+			}
+		}
+		if !filter.Match(values) {
+			return false
+		}
+	}
+
 	// Mentions filter (search for @username in body)
 	for _, mention := range q.Mentions {
 		searchMention := "@" + mention
@@ -52,6 +52,16 @@ func TestParse(t *testing.T) {
 			query: "no:label",
 			want:  Query{NoLabel: true, SortField: "created", SortAsc: false},
 		},
+		{
+			name:  "linked:pr",
+			query: "linked:pr",
+			want:  Query{LinkedPR: true, SortField: "created", SortAsc: false},
+		},
+		{
+			name:  "no:linked-pr",
+			query: "no:linked-pr",
+			want:  Query{NoLinkedPR: true, SortField: "created", SortAsc: false},
+		},
 		{
 			name:  "assignee filter",
 			query: "assignee:alice",
@@ -234,6 +244,24 @@ func TestMatch(t *testing.T) {
 			issue: IssueData{Title: "Test", State: "open", Labels: []string{"bug", "urgent"}},
 			want:  true,
 		},
+		{
+			name:  "linked:pr with linked pull request",
+			query: "linked:pr",
+			issue: IssueData{Title: "Test", State: "open", LinkedPRs: 1},
+			want:  true,
+		},
+		{
+			name:  "linked:pr without linked pull request",
+			query: "linked:pr",
+			issue: IssueData{Title: "Test", State: "open"},
+			want:  false,
+		},
+		{
+			name:  "no:linked-pr with linked pull request",
+			query: "no:linked-pr",
+			issue: IssueData{Title: "Test", State: "open", LinkedPRs: 2},
+			want:  false,
+		},
 		{
 			name:  "no:assignee with no assignees",
 			query: "no:assignee",
@@ -336,6 +364,42 @@ func TestMatch(t *testing.T) {
 			issue: IssueData{Title: "Error handling", State: "open", Labels: []string{"bug"}, Assignees: []string{"alice"}},
 			want:  false,
 		},
+		{
+			name:  "extra numeric comparison match",
+			query: "extra.estimate:>3",
+			issue: IssueData{Title: "Test", State: "open", Extra: map[string][]string{"estimate": {"5"}}},
+			want:  true,
+		},
+		{
+			name:  "extra numeric comparison no match",
+			query: "extra.estimate:>3",
+			issue: IssueData{Title: "Test", State: "open", Extra: map[string][]string{"estimate": {"3"}}},
+			want:  false,
+		},
+		{
+			name:  "extra equality case insensitive",
+			query: "extra.Owner:Alice",
+			issue: IssueData{Title: "Test", State: "open", Extra: map[string][]string{"owner": {"alice"}}},
+			want:  true,
+		},
+		{
+			name:  "extra prefix case insensitive",
+			query: "Extra.priority:high",
+			issue: IssueData{Title: "Test", State: "open", Extra: map[string][]string{"priority": {"high"}}},
+			want:  true,
+		},
+		{
+			name:  "extra list value match",
+			query: "extra.areas:auth",
+			issue: IssueData{Title: "Test", State: "open", Extra: map[string][]string{"areas": {"ui", "auth"}}},
+			want:  true,
+		},
+		{
+			name:  "extra missing field no match",
+			query: "extra.estimate:<=8",
+			issue: IssueData{Title: "Test", State: "open"},
+			want:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,64 @@
+package watch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// newNative watches dirs with inotify.
+func newNative(dirs []string) (*Watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify_init: %w", err)
+	}
+	// Wrapping the non-blocking descriptor in an os.File lets reads go
+	// through the runtime poller, so Close unblocks a pending read.
+	file := os.NewFile(uintptr(fd), "inotify")
+
+	watches := make(map[int32]string, len(dirs))
+	for _, dir := range dirs {
+		wd, err := syscall.InotifyAddWatch(fd, dir, inotifyMask)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("watching %s: %w", dir, err)
+		}
+		watches[int32(wd)] = dir
+	}
+
+	events := make(chan string, 64)
+	w := &Watcher{Events: events, done: make(chan struct{}), stop: file.Close}
+	go func() {
+		defer close(events)
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+				nameStart := offset + syscall.SizeofInotifyEvent
+				nameEnd := nameStart + int(event.Len)
+				offset = nameEnd
+				dir, ok := watches[event.Wd]
+				if !ok || event.Len == 0 || event.Mask&syscall.IN_ISDIR != 0 {
+					continue
+				}
+				name := string(buf[nameStart:nameEnd])
+				for len(name) > 0 && name[len(name)-1] == 0 {
+					name = name[:len(name)-1]
+				}
+				if !w.send(events, filepath.Join(dir, name)) {
+					return
+				}
+			}
+		}
+	}()
+	return w, nil
+}
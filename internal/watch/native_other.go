@@ -0,0 +1,10 @@
+//go:build !linux
+
+package watch
+
+import "errors"
+
+// newNative is only implemented on Linux; other platforms poll.
+func newNative(dirs []string) (*Watcher, error) {
+	return nil, errors.New("native file watching is not supported on this platform")
+}
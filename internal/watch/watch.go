@@ -0,0 +1,117 @@
+// Package watch reports changes to files in a set of directories.
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is how often the polling watcher rescans directories.
+const DefaultPollInterval = time.Second
+
+// Watcher delivers the paths of files that were created, written, renamed or
+// removed in the watched directories. Subdirectories are not watched.
+type Watcher struct {
+	Events <-chan string
+
+	done      chan struct{}
+	closeOnce sync.Once
+	stop      func() error
+}
+
+// Close stops watching and releases its resources.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		if w.stop != nil {
+			err = w.stop()
+		}
+	})
+	return err
+}
+
+// New watches dirs with the platform's native notification mechanism
+// (inotify on Linux) and falls back to polling where none is available.
+func New(dirs []string) (*Watcher, error) {
+	if w, err := newNative(dirs); err == nil {
+		return w, nil
+	}
+	return NewPolling(dirs, DefaultPollInterval)
+}
+
+// NewPolling watches dirs by comparing file sizes and modification times
+// every interval.
+func NewPolling(dirs []string, interval time.Duration) (*Watcher, error) {
+	events := make(chan string, 64)
+	w := &Watcher{Events: events, done: make(chan struct{})}
+
+	snapshot := scan(dirs)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+			}
+			current := scan(dirs)
+			for path, info := range current {
+				if old, ok := snapshot[path]; !ok || old != info {
+					if !w.send(events, path) {
+						return
+					}
+				}
+			}
+			for path := range snapshot {
+				if _, ok := current[path]; !ok {
+					if !w.send(events, path) {
+						return
+					}
+				}
+			}
+			snapshot = current
+		}
+	}()
+	return w, nil
+}
+
+// send delivers an event, returning false once the watcher is closed.
+func (w *Watcher) send(events chan<- string, path string) bool {
+	select {
+	case events <- path:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+func scan(dirs []string) map[string]fileState {
+	files := make(map[string]fileState)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			files[filepath.Join(dir, entry.Name())] = fileState{size: info.Size(), modTime: info.ModTime()}
+		}
+	}
+	return files
+}
@@ -0,0 +1,63 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func expectEvent(t *testing.T, w *Watcher, want string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case path := <-w.Events:
+			if path == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for event on %s", want)
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	constructors := map[string]func(dirs []string) (*Watcher, error){
+		"native":  New,
+		"polling": func(dirs []string) (*Watcher, error) { return NewPolling(dirs, 10*time.Millisecond) },
+	}
+	for name, newWatcher := range constructors {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			w, err := newWatcher([]string{dir})
+			if err != nil {
+				t.Fatalf("watch: %v", err)
+			}
+			defer w.Close()
+
+			path := filepath.Join(dir, "1-issue.md")
+			if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			expectEvent(t, w, path)
+
+			renamed := filepath.Join(dir, "1-renamed.md")
+			if err := os.Rename(path, renamed); err != nil {
+				t.Fatalf("rename: %v", err)
+			}
+			expectEvent(t, w, renamed)
+
+			if err := os.Remove(renamed); err != nil {
+				t.Fatalf("remove: %v", err)
+			}
+			expectEvent(t, w, renamed)
+
+			if err := w.Close(); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+			for range w.Events {
+			}
+		})
+	}
+}